@@ -29,12 +29,14 @@ func setupCommonRootCommand(rootCmd *cobra.Command) (*cliflags.ClientOptions, *c
 	cobra.AddTemplateFunc("hasManagementSubCommands", hasManagementSubCommands)
 	cobra.AddTemplateFunc("hasSwarmSubCommands", hasSwarmSubCommands)
 	cobra.AddTemplateFunc("hasInvalidPlugins", hasInvalidPlugins)
+	cobra.AddTemplateFunc("hasUserAliases", hasUserAliases)
 	cobra.AddTemplateFunc("topCommands", topCommands)
 	cobra.AddTemplateFunc("commandAliases", commandAliases)
 	cobra.AddTemplateFunc("operationSubCommands", operationSubCommands)
 	cobra.AddTemplateFunc("managementSubCommands", managementSubCommands)
 	cobra.AddTemplateFunc("orchestratorSubCommands", orchestratorSubCommands)
 	cobra.AddTemplateFunc("invalidPlugins", invalidPlugins)
+	cobra.AddTemplateFunc("userAliases", userAliases)
 	cobra.AddTemplateFunc("wrappedFlagUsages", wrappedFlagUsages)
 	cobra.AddTemplateFunc("vendorAndVersion", vendorAndVersion)
 	cobra.AddTemplateFunc("invalidPluginReason", invalidPluginReason)
@@ -215,6 +217,17 @@ func isPlugin(cmd *cobra.Command) bool {
 	return cmd.Annotations[metadata.CommandAnnotationPlugin] == "true"
 }
 
+// UserAliasAnnotation is the annotation set on command stubs for
+// user-defined aliases. Its value is the alias' expansion.
+const UserAliasAnnotation = "userAlias"
+
+// IsUserAlias returns true if cmd is a stub for a user-defined alias in the
+// CLI's config-file.
+func IsUserAlias(cmd *cobra.Command) bool {
+	_, ok := cmd.Annotations[UserAliasAnnotation]
+	return ok
+}
+
 func hasAliases(cmd *cobra.Command) bool {
 	return len(cmd.Aliases) > 0 || cmd.Annotations["aliases"] != ""
 }
@@ -235,6 +248,10 @@ func hasInvalidPlugins(cmd *cobra.Command) bool {
 	return len(invalidPlugins(cmd)) > 0
 }
 
+func hasUserAliases(cmd *cobra.Command) bool {
+	return len(userAliases(cmd)) > 0
+}
+
 func hasTopCommands(cmd *cobra.Command) bool {
 	return len(topCommands(cmd)) > 0
 }
@@ -282,7 +299,7 @@ func topCommands(cmd *cobra.Command) []*cobra.Command {
 func operationSubCommands(cmd *cobra.Command) []*cobra.Command {
 	cmds := []*cobra.Command{}
 	for _, sub := range cmd.Commands() {
-		if isPlugin(sub) {
+		if isPlugin(sub) || IsUserAlias(sub) {
 			continue
 		}
 		if _, ok := sub.Annotations["category-top"]; ok {
@@ -374,6 +391,16 @@ func invalidPlugins(cmd *cobra.Command) []*cobra.Command {
 	return cmds
 }
 
+func userAliases(cmd *cobra.Command) []*cobra.Command {
+	cmds := []*cobra.Command{}
+	for _, sub := range cmd.Commands() {
+		if IsUserAlias(sub) {
+			cmds = append(cmds, sub)
+		}
+	}
+	return cmds
+}
+
 func invalidPluginReason(cmd *cobra.Command) string {
 	return cmd.Annotations[metadata.CommandAnnotationPluginInvalid]
 }
@@ -448,6 +475,15 @@ func invalidPluginReason(cmd *cobra.Command) string {
 {{- end}}
 {{- end}}
 
+{{- if hasUserAliases . }}
+
+User Aliases:
+
+{{- range userAliases . }}
+  {{rpad .Name .NamePadding }} {{.Short}}
+{{- end}}
+
+{{- end}}
 {{- if hasInvalidPlugins . }}
 
 Invalid Plugins:
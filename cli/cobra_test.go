@@ -83,6 +83,26 @@ func TestHiddenPlugin(t *testing.T) {
 	assert.DeepEqual(t, allManagementSubCommands(root), []*cobra.Command{sub2}, cmpopts.IgnoreFields(cobra.Command{}, "Run"), cmpopts.IgnoreUnexported(cobra.Command{}))
 }
 
+func TestUserAliases(t *testing.T) {
+	root := &cobra.Command{Use: "root"}
+	sub1 := &cobra.Command{
+		Use:         "sub1",
+		Annotations: map[string]string{UserAliasAnnotation: "sub2 --foo"},
+		Run:         func(cmd *cobra.Command, args []string) {},
+	}
+	sub2 := &cobra.Command{Use: "sub2", Run: func(cmd *cobra.Command, args []string) {}}
+	sub3 := &cobra.Command{Use: "sub3"}
+
+	assert.Check(t, !hasUserAliases(root))
+
+	root.AddCommand(sub1, sub2, sub3)
+	sub3.AddCommand(&cobra.Command{Use: "sub3sub1", Run: func(cmd *cobra.Command, args []string) {}})
+
+	assert.Check(t, hasUserAliases(root))
+	assert.DeepEqual(t, userAliases(root), []*cobra.Command{sub1}, cmpopts.IgnoreFields(cobra.Command{}, "Run"), cmpopts.IgnoreUnexported(cobra.Command{}))
+	assert.DeepEqual(t, operationSubCommands(root), []*cobra.Command{sub2}, cmpopts.IgnoreFields(cobra.Command{}, "Run"), cmpopts.IgnoreUnexported(cobra.Command{}))
+}
+
 func TestCommandAliases(t *testing.T) {
 	root := &cobra.Command{Use: "root"}
 	sub := &cobra.Command{Use: "subcommand", Aliases: []string{"alias1", "alias2"}}
@@ -0,0 +1,88 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package command
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/connhelper"
+	"github.com/docker/cli/cli/connhelper/broker"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/moby/moby/client"
+)
+
+// EnvConnectionBroker is the name of the environment variable to opt in to
+// using a connection broker for hosts that are connected to through a
+// connection helper, such as "ssh://" hosts. The value can either be a
+// boolean, or the duration after which an idle broker exits.
+const EnvConnectionBroker = "DOCKER_CONNECTION_BROKER"
+
+// defaultBrokerIdleTimeout is the duration after which an idle connection
+// broker exits if [EnvConnectionBroker] is set to "true".
+const defaultBrokerIdleTimeout = 10 * time.Minute
+
+// connectionBrokerIdleTimeout returns the idle timeout for the connection
+// broker that's configured through [EnvConnectionBroker]. It returns zero
+// if the connection broker is not enabled.
+func connectionBrokerIdleTimeout() (time.Duration, error) {
+	v := os.Getenv(EnvConnectionBroker)
+	if v == "" {
+		return 0, nil
+	}
+	if enabled, err := strconv.ParseBool(v); err == nil {
+		if !enabled {
+			return 0, nil
+		}
+		return defaultBrokerIdleTimeout, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid value for %s: %q: must be a boolean or a positive duration", EnvConnectionBroker, v)
+	}
+	return d, nil
+}
+
+// connectionBrokerOpts returns the options to connect to the daemon through
+// a connection broker if the connection broker is enabled, and the endpoint
+// uses a connection helper. The broker is started if it's not yet running.
+func connectionBrokerOpts(contextName string, ep docker.Endpoint) ([]client.Opt, error) {
+	idleTimeout, err := connectionBrokerIdleTimeout()
+	if err != nil || idleTimeout == 0 || ep.Host == "" {
+		return nil, err
+	}
+	helper, err := connhelper.GetConnectionHelper(ep.Host)
+	if err != nil || helper == nil {
+		return nil, err
+	}
+	socketPath := brokerSocketPath(contextName, ep.Host)
+	start := func() error {
+		exe, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		return broker.Start(exe, "system", "connection-broker", "--host", ep.Host, "--socket", socketPath, "--idle-timeout", idleTimeout.String())
+	}
+	return []client.Opt{
+		client.WithDialContext(func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return broker.Dial(ctx, socketPath, start)
+		}),
+	}, nil
+}
+
+// brokerSocketPath returns the path of the socket of the connection broker
+// for the given context and host. Brokers are keyed by both, so that a new
+// broker is started if the host of a context is changed.
+func brokerSocketPath(contextName, host string) string {
+	sum := sha256.Sum256([]byte(contextName + "\x00" + host))
+	return filepath.Join(config.Dir(), "brokers", hex.EncodeToString(sum[:8])+".sock")
+}
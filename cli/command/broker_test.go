@@ -0,0 +1,52 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/context/docker"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestConnectionBrokerIdleTimeout(t *testing.T) {
+	tests := []struct {
+		value       string
+		expected    time.Duration
+		expectedErr string
+	}{
+		{value: "", expected: 0},
+		{value: "0", expected: 0},
+		{value: "false", expected: 0},
+		{value: "1", expected: defaultBrokerIdleTimeout},
+		{value: "true", expected: defaultBrokerIdleTimeout},
+		{value: "30m", expected: 30 * time.Minute},
+		{value: "-1m", expectedErr: `invalid value for DOCKER_CONNECTION_BROKER: "-1m": must be a boolean or a positive duration`},
+		{value: "always", expectedErr: `invalid value for DOCKER_CONNECTION_BROKER: "always": must be a boolean or a positive duration`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			t.Setenv(EnvConnectionBroker, tc.value)
+			d, err := connectionBrokerIdleTimeout()
+			if tc.expectedErr != "" {
+				assert.Check(t, is.Error(err, tc.expectedErr))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(d, tc.expected))
+		})
+	}
+}
+
+func TestConnectionBrokerOpts(t *testing.T) {
+	t.Setenv(EnvConnectionBroker, "1")
+	opts, err := connectionBrokerOpts("default", docker.Endpoint{EndpointMeta: docker.EndpointMeta{Host: "unix:///var/run/docker.sock"}})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(opts, 0))
+
+	opts, err = connectionBrokerOpts("remote", docker.Endpoint{EndpointMeta: docker.EndpointMeta{Host: "ssh://me@example.com"}})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(opts, 1))
+
+	assert.Check(t, brokerSocketPath("remote", "ssh://me@example.com") != brokerSocketPath("other", "ssh://me@example.com"))
+}
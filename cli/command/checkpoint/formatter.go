@@ -12,8 +12,8 @@
 
 // newFormat returns a format for use with a checkpointContext.
 func newFormat(source string) formatter.Format {
-	if source == formatter.TableFormatKey {
-		return defaultCheckpointFormat
+	if source == formatter.TableFormatKey || source == formatter.CSVFormatKey {
+		return formatter.TableOrCSV(source, defaultCheckpointFormat)
 	}
 	return formatter.Format(source)
 }
@@ -11,6 +11,7 @@
 	"os"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -26,6 +27,7 @@
 	dopts "github.com/docker/cli/opts"
 	"github.com/moby/moby/api/types/build"
 	"github.com/moby/moby/client"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -58,22 +60,23 @@ type Cli interface {
 // constructor to make sure they are properly initialized with defaults
 // set.
 type DockerCli struct {
-	configFile         *configfile.ConfigFile
-	options            *cliflags.ClientOptions
-	clientOpts         []client.Opt
-	in                 *streams.In
-	out                *streams.Out
-	err                *streams.Out
-	client             client.APIClient
-	serverInfo         ServerInfo
-	contextStore       store.Store
-	currentContext     string
-	init               sync.Once
-	initErr            error
-	dockerEndpoint     docker.Endpoint
-	contextStoreConfig *store.Config
-	initTimeout        time.Duration
-	res                telemetryResource
+	configFile           *configfile.ConfigFile
+	options              *cliflags.ClientOptions
+	clientOpts           []client.Opt
+	in                   *streams.In
+	out                  *streams.Out
+	err                  *streams.Out
+	client               client.APIClient
+	serverInfo           ServerInfo
+	contextStore         store.Store
+	currentContext       string
+	currentContextSource string
+	init                 sync.Once
+	initErr              error
+	dockerEndpoint       docker.Endpoint
+	contextStoreConfig   *store.Config
+	initTimeout          time.Duration
+	res                  telemetryResource
 
 	// baseCtx is the base context used for internal operations. In the future
 	// this may be replaced by explicitly passing a context to functions that
@@ -249,9 +252,9 @@ func (cli *DockerCli) Initialize(opts *cliflags.ClientOptions, ops ...CLIOption)
 
 	cli.options = opts
 	cli.configFile = config.LoadDefaultConfigFile(cli.err)
-	cli.currentContext = resolveContextName(cli.options, cli.configFile)
+	cli.currentContext, cli.currentContextSource = resolveContextName(cli.options, cli.configFile)
 	cli.contextStore = &ContextStoreWithDefault{
-		Store: store.New(config.ContextStoreDir(), *cli.contextStoreConfig),
+		Store: newContextStore(config.ContextStoreDir(), *cli.contextStoreConfig, cli.configFile),
 		Resolver: func() (*DefaultContext, error) {
 			return resolveDefaultContext(cli.options, *cli.contextStoreConfig)
 		},
@@ -288,23 +291,46 @@ func NewAPIClientFromFlags(opts *cliflags.ClientOptions, configFile *configfile.
 
 	storeConfig := DefaultContextStoreConfig()
 	contextStore := &ContextStoreWithDefault{
-		Store: store.New(config.ContextStoreDir(), storeConfig),
+		Store: newContextStore(config.ContextStoreDir(), storeConfig, configFile),
 		Resolver: func() (*DefaultContext, error) {
 			return resolveDefaultContext(opts, storeConfig)
 		},
 	}
-	endpoint, err := resolveDockerEndpoint(contextStore, resolveContextName(opts, configFile))
+	contextName, _ := resolveContextName(opts, configFile)
+	endpoint, err := resolveDockerEndpoint(contextStore, contextName)
 	if err != nil {
 		return nil, fmt.Errorf("unable to resolve docker endpoint: %w", err)
 	}
-	return newAPIClientFromEndpoint(endpoint, configFile, client.WithUserAgent(UserAgent()))
+	return newAPIClientFromEndpoint(context.Background(), contextName, endpoint, configFile, client.WithUserAgent(UserAgent()))
 }
 
-func newAPIClientFromEndpoint(ep docker.Endpoint, configFile *configfile.ConfigFile, extraOpts ...client.Opt) (client.APIClient, error) {
+// NewAPIClientFromContext creates a new APIClient for the context with the
+// given name, using the context store and configuration file of dockerCLI.
+// It's used by commands that connect to a daemon other than the daemon of
+// the current context. The context is used to select the endpoint to connect
+// to if the context has failover endpoints.
+func NewAPIClientFromContext(ctx context.Context, dockerCLI Cli, contextName string) (client.APIClient, error) {
+	endpoint, err := resolveDockerEndpoint(dockerCLI.ContextStore(), contextName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve docker endpoint: %w", err)
+	}
+	return newAPIClientFromEndpoint(ctx, contextName, endpoint, dockerCLI.ConfigFile(), client.WithUserAgent(UserAgent()))
+}
+
+func newAPIClientFromEndpoint(ctx context.Context, contextName string, ep docker.Endpoint, configFile *configfile.ConfigFile, extraOpts ...client.Opt) (client.APIClient, error) {
+	ep, err := ep.Select(ctx)
+	if err != nil {
+		return nil, err
+	}
 	opts, err := ep.ClientOpts()
 	if err != nil {
 		return nil, err
 	}
+	brokerOpts, err := connectionBrokerOpts(contextName, ep)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, brokerOpts...)
 	if len(configFile.HTTPHeaders) > 0 {
 		opts = append(opts, client.WithHTTPHeaders(configFile.HTTPHeaders))
 	}
@@ -405,9 +431,11 @@ func (cli *DockerCli) ContextStore() store.Store {
 //
 //  1. The "--context" command-line option.
 //  2. The "DOCKER_CONTEXT" environment variable ([EnvOverrideContext]).
-//  3. The current context as configured through the in "currentContext"
+//  3. The context file (".docker-context") in the current working directory
+//     or its parent directories; see [config.ContextFile].
+//  4. The current context as configured through the in "currentContext"
 //     field in the CLI configuration file ("~/.docker/config.json").
-//  4. If no context is configured, use the "default" context.
+//  5. If no context is configured, use the "default" context.
 //
 // # Fallbacks for backward-compatibility
 //
@@ -430,30 +458,71 @@ func (cli *DockerCli) CurrentContext() string {
 	return cli.currentContext
 }
 
-// CurrentContext returns the current context name, based on flags,
-// environment variables and the cli configuration file. It does not
-// validate if the given context exists or if it's valid; errors may
-// occur when trying to use it.
+// CurrentContextSource returns a description of the source that selected
+// the current context, such as "--context flag", or the path of the context
+// file. Refer to [DockerCli.CurrentContext] for the order of preference.
+func (cli *DockerCli) CurrentContextSource() string {
+	return cli.currentContextSource
+}
+
+// resolveContextName returns the current context name, based on flags,
+// environment variables, the context file, and the cli configuration file,
+// and a description of the source that selected it. It does not validate
+// if the given context exists or if it's valid; errors may occur when
+// trying to use it.
+//
+// Sources are used in the following order of preference:
+//
+//  1. The "--context" flag.
+//  2. The "--host" flag, which selects the default context.
+//  3. The "DOCKER_HOST" environment variable, which selects the default context.
+//  4. The "DOCKER_CONTEXT" environment variable.
+//  5. The first line of the nearest context file (".docker-context"),
+//     looking in the current working directory and its parent directories.
+//     Empty context files are ignored.
+//  6. The "currentContext" field in the CLI configuration file(s).
+//  7. The default context.
 //
 // Refer to [DockerCli.CurrentContext] above for further details.
-func resolveContextName(opts *cliflags.ClientOptions, cfg *configfile.ConfigFile) string {
+func resolveContextName(opts *cliflags.ClientOptions, cfg *configfile.ConfigFile) (name string, source string) {
 	if opts != nil && opts.Context != "" {
-		return opts.Context
+		return opts.Context, "--context flag"
 	}
 	if opts != nil && len(opts.Hosts) > 0 {
-		return DefaultContextName
+		return DefaultContextName, "--host flag"
 	}
 	if os.Getenv(client.EnvOverrideHost) != "" {
-		return DefaultContextName
+		return DefaultContextName, client.EnvOverrideHost + " environment variable"
 	}
 	if ctxName := os.Getenv(EnvOverrideContext); ctxName != "" {
-		return ctxName
+		return ctxName, EnvOverrideContext + " environment variable"
+	}
+	if fn := config.ContextFile(); fn != "" {
+		if ctxName := readContextFile(fn); ctxName != "" {
+			return ctxName, fn
+		}
 	}
 	if cfg != nil && cfg.CurrentContext != "" {
 		// We don't validate if this context exists: errors may occur when trying to use it.
-		return cfg.CurrentContext
+		fn := cfg.Filename
+		if origin, ok := cfg.Origin("currentContext"); ok {
+			fn = origin.File
+		}
+		return cfg.CurrentContext, "currentContext in " + fn
+	}
+	return DefaultContextName, "default"
+}
+
+// readContextFile returns the context name in the first line of the context
+// file. It returns an empty string if the file is empty or cannot be read.
+func readContextFile(fn string) string {
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		logrus.WithError(err).Debug("failed to read context file")
+		return ""
 	}
-	return DefaultContextName
+	name, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimSpace(name)
 }
 
 // DockerEndpoint returns the current docker endpoint
@@ -532,14 +601,14 @@ func (cli *DockerCli) initialize() error {
 			cli.initErr = fmt.Errorf("unable to resolve docker endpoint: %w", cli.initErr)
 			return
 		}
+		if cli.baseCtx == nil {
+			cli.baseCtx = context.Background()
+		}
 		if cli.client == nil {
-			if cli.client, cli.initErr = newAPIClientFromEndpoint(cli.dockerEndpoint, cli.configFile, cli.clientOpts...); cli.initErr != nil {
+			if cli.client, cli.initErr = newAPIClientFromEndpoint(cli.baseCtx, cli.currentContext, cli.dockerEndpoint, cli.configFile, cli.clientOpts...); cli.initErr != nil {
 				return
 			}
 		}
-		if cli.baseCtx == nil {
-			cli.baseCtx = context.Background()
-		}
 		cli.initializeFromClient()
 	})
 	return cli.initErr
@@ -601,6 +670,8 @@ func UserAgent() string {
 
 var defaultStoreEndpoints = []store.NamedTypeGetter{
 	store.EndpointTypeGetter(docker.DockerEndpoint, func() any { return &docker.EndpointMeta{} }),
+	store.EndpointTypeGetter(docker.FailoverEndpoint, func() any { return &docker.FailoverMeta{} }),
+	store.EndpointTypeGetter(docker.ProxyEndpoint, func() any { return &docker.ProxyMeta{} }),
 }
 
 // RegisterDefaultStoreEndpoints registers a new named endpoint
@@ -316,6 +316,83 @@ func TestInitializeShouldAlwaysCreateTheContextStore(t *testing.T) {
 	assert.Check(t, cli.ContextStore() != nil)
 }
 
+func TestResolveContextName(t *testing.T) {
+	t.Setenv(client.EnvOverrideHost, "")
+	t.Setenv(EnvOverrideContext, "")
+
+	projectDir := t.TempDir()
+	workDir := filepath.Join(projectDir, "sub", "dir")
+	assert.NilError(t, os.MkdirAll(workDir, 0o755))
+	contextFile := filepath.Join(projectDir, config.ContextFileName)
+	assert.NilError(t, os.WriteFile(contextFile, []byte("  from-file \n# comment\n"), 0o644))
+	t.Chdir(workDir)
+
+	cfg := configfile.New(filepath.Join(t.TempDir(), "config.json"))
+	cfg.CurrentContext = "from-config"
+
+	tests := []struct {
+		doc            string
+		opts           *flags.ClientOptions
+		env            map[string]string
+		noContextFile  bool
+		expectedName   string
+		expectedSource string
+	}{
+		{
+			doc:            "context flag",
+			opts:           &flags.ClientOptions{Context: "from-flag"},
+			env:            map[string]string{EnvOverrideContext: "from-env"},
+			expectedName:   "from-flag",
+			expectedSource: "--context flag",
+		},
+		{
+			doc:            "host flag",
+			opts:           &flags.ClientOptions{Hosts: []string{"unix:///var/run/docker.sock"}},
+			expectedName:   DefaultContextName,
+			expectedSource: "--host flag",
+		},
+		{
+			doc:            "DOCKER_HOST",
+			env:            map[string]string{client.EnvOverrideHost: "unix:///var/run/docker.sock", EnvOverrideContext: "from-env"},
+			expectedName:   DefaultContextName,
+			expectedSource: "DOCKER_HOST environment variable",
+		},
+		{
+			doc:            "DOCKER_CONTEXT",
+			env:            map[string]string{EnvOverrideContext: "from-env"},
+			expectedName:   "from-env",
+			expectedSource: "DOCKER_CONTEXT environment variable",
+		},
+		{
+			doc:            "context file",
+			expectedName:   "from-file",
+			expectedSource: contextFile,
+		},
+		{
+			doc:            "config file",
+			noContextFile:  true,
+			expectedName:   "from-config",
+			expectedSource: "currentContext in " + cfg.Filename,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			if tc.noContextFile {
+				assert.NilError(t, os.WriteFile(contextFile, nil, 0o644))
+				defer func() {
+					assert.NilError(t, os.WriteFile(contextFile, []byte("from-file\n"), 0o644))
+				}()
+			}
+			name, source := resolveContextName(tc.opts, cfg)
+			assert.Equal(t, name, tc.expectedName)
+			assert.Equal(t, source, tc.expectedSource)
+		})
+	}
+}
+
 func TestHooksEnabled(t *testing.T) {
 	t.Run("disabled by default", func(t *testing.T) {
 		// Make sure we don't depend on any existing ~/.docker/config.json
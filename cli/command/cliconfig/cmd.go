@@ -0,0 +1,28 @@
+package cliconfig
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	commands.Register(newCLIConfigCommand)
+}
+
+// newCLIConfigCommand returns the cli-config cli subcommand
+func newCLIConfigCommand(dockerCLI command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cli-config",
+		Short: "Manage the CLI configuration",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCLI.Err()),
+
+		DisableFlagsInUseLine: true,
+	}
+	cmd.AddCommand(
+		newShowCommand(dockerCLI),
+	)
+	return cmd
+}
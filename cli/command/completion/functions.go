@@ -5,6 +5,7 @@
 	"strings"
 
 	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/context/store"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
 	"github.com/spf13/cobra"
@@ -20,6 +21,19 @@ type APIClientProvider interface {
 	Client() client.APIClient
 }
 
+// ContextStoreProvider provides a method to get the context store.
+type ContextStoreProvider interface {
+	ContextStore() store.Store
+}
+
+// ContextNames offers completion for the names of contexts.
+func ContextNames(dockerCLI ContextStoreProvider) cobra.CompletionFunc {
+	return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		names, _ := store.Names(dockerCLI.ContextStore())
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
 // ImageNames offers completion for images present within the local store
 func ImageNames(dockerCLI APIClientProvider, limit int) cobra.CompletionFunc {
 	return Unique(func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
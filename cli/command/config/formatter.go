@@ -39,11 +39,11 @@ func newFormat(source string, quiet bool) formatter.Format {
 	switch source {
 	case formatter.PrettyFormatKey:
 		return configInspectPrettyTemplate
-	case formatter.TableFormatKey:
+	case formatter.TableFormatKey, formatter.CSVFormatKey:
 		if quiet {
 			return formatter.DefaultQuietFormat
 		}
-		return defaultConfigTableFormat
+		return formatter.TableOrCSV(source, defaultConfigTableFormat)
 	}
 	return formatter.Format(source)
 }
@@ -0,0 +1,94 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/prompt"
+	"github.com/docker/cli/opts"
+	"github.com/moby/moby/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// bulkConfirmThreshold is the number of containers matching a filter above
+// which confirmation is requested before operating on the containers.
+const bulkConfirmThreshold = 10
+
+// bulkOptions are the options for selecting containers by filter, and for
+// operating on multiple containers in parallel, as used by the "stop",
+// "start", "restart", "kill", "rm", "pause", and "unpause" commands.
+type bulkOptions struct {
+	filter   opts.FilterOpt
+	parallel int
+	yes      bool
+}
+
+func newBulkOptions() bulkOptions {
+	return bulkOptions{filter: opts.NewFilterOpt()}
+}
+
+// installFlags adds the flags for bulkOptions to the flag-set, using
+// defaultParallel as the default for the "--parallel" flag.
+func (o *bulkOptions) installFlags(flags *pflag.FlagSet, defaultParallel int) {
+	flags.Var(&o.filter, "filter", `Select containers based on conditions provided (same syntax as "docker ps --filter")`)
+	flags.IntVar(&o.parallel, "parallel", defaultParallel, "Maximum number of containers to operate on in parallel")
+	flags.BoolVarP(&o.yes, "yes", "y", false, fmt.Sprintf("Do not prompt for confirmation when more than %d containers match the filter", bulkConfirmThreshold))
+}
+
+// args validates the positional arguments. Container names are required,
+// unless containers are selected using "--filter".
+func (o *bulkOptions) args(cmd *cobra.Command, args []string) error {
+	if len(o.filter.Value()) == 0 {
+		return cli.RequiresMinArgs(1)(cmd, args)
+	}
+	if len(args) > 0 {
+		return errors.New("conflicting options: cannot specify both container names and --filter")
+	}
+	return nil
+}
+
+// containers returns the containers to operate on. If no filter is set, the
+// containers passed as arguments are returned as-is. Otherwise, all containers
+// matching the filter are returned; the user is prompted for confirmation if
+// more than bulkConfirmThreshold containers match, unless "--yes" is set.
+func (o *bulkOptions) containers(ctx context.Context, dockerCLI command.Cli, args []string, action string) ([]string, error) {
+	if o.parallel < 1 {
+		return nil, fmt.Errorf("invalid value for --parallel: %d: must be greater than 0", o.parallel)
+	}
+	if len(o.filter.Value()) == 0 {
+		return args, nil
+	}
+
+	res, err := dockerCLI.Client().ContainerList(ctx, client.ContainerListOptions{
+		All:     true,
+		Filters: o.filter.Value(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	containers := make([]string, 0, len(res.Items))
+	for _, ctr := range res.Items {
+		if len(ctr.Names) > 0 {
+			containers = append(containers, strings.TrimPrefix(ctr.Names[0], "/"))
+		} else {
+			containers = append(containers, ctr.ID)
+		}
+	}
+
+	if len(containers) > bulkConfirmThreshold && !o.yes {
+		msg := fmt.Sprintf("WARNING! This will %s %d containers:\n  %s\nAre you sure you want to continue?", action, len(containers), strings.Join(containers, "\n  "))
+		r, err := prompt.Confirm(ctx, dockerCLI.In(), dockerCLI.Out(), msg)
+		if err != nil {
+			return nil, err
+		}
+		if !r {
+			return nil, cancelledErr{fmt.Errorf("container %s has been cancelled", action)}
+		}
+	}
+	return containers, nil
+}
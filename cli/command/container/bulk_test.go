@@ -0,0 +1,159 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// fakeBulkClient returns a fakeClient that lists n containers matching the
+// "label=app=web" filter, and records the containers that are stopped.
+func fakeBulkClient(t *testing.T, n int) (*fakeClient, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var stopped []string
+	return &fakeClient{
+		containerListFunc: func(options client.ContainerListOptions) (client.ContainerListResult, error) {
+			assert.Check(t, options.All)
+			assert.Check(t, is.DeepEqual(options.Filters, make(client.Filters).Add("label", "app=web")))
+			var res client.ContainerListResult
+			for i := 1; i <= n; i++ {
+				res.Items = append(res.Items, container.Summary{
+					ID:    fmt.Sprintf("id-%d", i),
+					Names: []string{fmt.Sprintf("/web-%d", i)},
+				})
+			}
+			return res, nil
+		},
+		containerStopFunc: func(_ context.Context, containerID string, _ client.ContainerStopOptions) (client.ContainerStopResult, error) {
+			if containerID == "web-2" {
+				return client.ContainerStopResult{}, errors.New("cannot stop container: web-2")
+			}
+			mu.Lock()
+			stopped = append(stopped, containerID)
+			mu.Unlock()
+			return client.ContainerStopResult{}, nil
+		},
+	}, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		sort.Strings(stopped)
+		return stopped
+	}
+}
+
+func TestBulkFilter(t *testing.T) {
+	apiClient, stopped := fakeBulkClient(t, 3)
+	cli := test.NewFakeCli(apiClient)
+	cmd := newStopCommand(cli)
+	cmd.SetArgs([]string{"--filter", "label=app=web", "--parallel", "2"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	assert.Check(t, is.Error(cmd.Execute(), "cannot stop container: web-2"))
+	assert.Check(t, is.DeepEqual(stopped(), []string{"web-1", "web-3"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "web-1\nweb-3\n"))
+}
+
+func TestBulkFilterNoMatch(t *testing.T) {
+	apiClient, stopped := fakeBulkClient(t, 0)
+	cli := test.NewFakeCli(apiClient)
+	cmd := newStopCommand(cli)
+	cmd.SetArgs([]string{"--filter", "label=app=web"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	assert.Check(t, cmd.Execute())
+	assert.Check(t, is.Len(stopped(), 0))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), ""))
+}
+
+func TestBulkFilterConfirm(t *testing.T) {
+	tests := []struct {
+		doc         string
+		args        []string
+		input       string
+		expectedErr string
+		stopped     int
+	}{
+		{
+			doc:         "declined",
+			args:        []string{"--filter", "label=app=web"},
+			input:       "n\n",
+			expectedErr: "container stop has been cancelled",
+		},
+		{
+			doc:         "confirmed",
+			args:        []string{"--filter", "label=app=web"},
+			input:       "y\n",
+			expectedErr: "cannot stop container: web-2",
+			stopped:     bulkConfirmThreshold,
+		},
+		{
+			doc:         "yes",
+			args:        []string{"--filter", "label=app=web", "--yes"},
+			expectedErr: "cannot stop container: web-2",
+			stopped:     bulkConfirmThreshold,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			apiClient, stopped := fakeBulkClient(t, bulkConfirmThreshold+1)
+			cli := test.NewFakeCli(apiClient)
+			cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader(tc.input))))
+			cmd := newStopCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			assert.Check(t, is.Error(cmd.Execute(), tc.expectedErr))
+			assert.Check(t, is.Len(stopped(), tc.stopped))
+			if tc.input != "" {
+				assert.Check(t, is.Contains(cli.OutBuffer().String(), "WARNING! This will stop 11 containers:\n  web-1\n"))
+			}
+		})
+	}
+}
+
+func TestBulkErrors(t *testing.T) {
+	tests := []struct {
+		args        []string
+		expectedErr string
+	}{
+		{
+			args:        []string{},
+			expectedErr: "requires at least 1 argument",
+		},
+		{
+			args:        []string{"--filter", "label=app=web", "web-1"},
+			expectedErr: "conflicting options: cannot specify both container names and --filter",
+		},
+		{
+			args:        []string{"--parallel", "0", "web-1"},
+			expectedErr: "invalid value for --parallel: 0: must be greater than 0",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expectedErr, func(t *testing.T) {
+			apiClient, stopped := fakeBulkClient(t, 1)
+			cmd := newStopCommand(test.NewFakeCli(apiClient))
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedErr))
+			assert.Check(t, is.Len(stopped(), 0))
+		})
+	}
+}
@@ -52,6 +52,16 @@ type fakeClient struct {
 	containerRenameFunc     func(ctx context.Context, oldName, newName string) error
 	containerCommitFunc     func(ctx context.Context, container string, options client.ContainerCommitOptions) (client.ContainerCommitResult, error)
 	containerPauseFunc      func(ctx context.Context, container string, options client.ContainerPauseOptions) (client.ContainerPauseResult, error)
+	eventsFunc              func(ctx context.Context, options client.EventsListOptions) client.EventsResult
+	imageInspectFunc        func(img string) (client.ImageInspectResult, error)
+	imageSaveFunc           func(images []string) (client.ImageSaveResult, error)
+	imageLoadFunc           func(input io.Reader) (client.ImageLoadResult, error)
+	imageImportFunc         func(source client.ImageImportSource) (client.ImageImportResult, error)
+	imageRemoveFunc         func(img string) (client.ImageRemoveResult, error)
+	volumeInspectFunc       func(volumeID string) (client.VolumeInspectResult, error)
+	volumeCreateFunc        func(options client.VolumeCreateOptions) (client.VolumeCreateResult, error)
+	networkInspectFunc      func(networkID string) (client.NetworkInspectResult, error)
+	networkCreateFunc       func(name string, options client.NetworkCreateOptions) (client.NetworkCreateResult, error)
 	Version                 string
 }
 
@@ -244,3 +254,76 @@ func (f *fakeClient) ContainerPause(ctx context.Context, containerID string, opt
 func (*fakeClient) Ping(_ context.Context, _ client.PingOptions) (client.PingResult, error) {
 	return client.PingResult{}, nil
 }
+
+func (f *fakeClient) Events(ctx context.Context, options client.EventsListOptions) client.EventsResult {
+	if f.eventsFunc != nil {
+		return f.eventsFunc(ctx, options)
+	}
+	return client.EventsResult{}
+}
+
+func (f *fakeClient) ImageInspect(_ context.Context, img string, _ ...client.ImageInspectOption) (client.ImageInspectResult, error) {
+	if f.imageInspectFunc != nil {
+		return f.imageInspectFunc(img)
+	}
+	return client.ImageInspectResult{}, nil
+}
+
+func (f *fakeClient) ImageSave(_ context.Context, images []string, _ ...client.ImageSaveOption) (client.ImageSaveResult, error) {
+	if f.imageSaveFunc != nil {
+		return f.imageSaveFunc(images)
+	}
+	return http.NoBody, nil
+}
+
+func (f *fakeClient) ImageLoad(_ context.Context, input io.Reader, _ ...client.ImageLoadOption) (client.ImageLoadResult, error) {
+	if f.imageLoadFunc != nil {
+		return f.imageLoadFunc(input)
+	}
+	return http.NoBody, nil
+}
+
+// ImageImport returns the ID of the helper image that's imported from an
+// empty archive, unless imageImportFunc is set.
+func (f *fakeClient) ImageImport(_ context.Context, source client.ImageImportSource, _ string, _ client.ImageImportOptions) (client.ImageImportResult, error) {
+	if f.imageImportFunc != nil {
+		return f.imageImportFunc(source)
+	}
+	_, _ = io.Copy(io.Discard, source.Source)
+	return io.NopCloser(strings.NewReader(`{"status":"sha256:7f4b1e2a9c3d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f"}` + "\r\n")), nil
+}
+
+func (f *fakeClient) ImageRemove(_ context.Context, img string, _ client.ImageRemoveOptions) (client.ImageRemoveResult, error) {
+	if f.imageRemoveFunc != nil {
+		return f.imageRemoveFunc(img)
+	}
+	return client.ImageRemoveResult{}, nil
+}
+
+func (f *fakeClient) VolumeInspect(_ context.Context, volumeID string, _ client.VolumeInspectOptions) (client.VolumeInspectResult, error) {
+	if f.volumeInspectFunc != nil {
+		return f.volumeInspectFunc(volumeID)
+	}
+	return client.VolumeInspectResult{}, nil
+}
+
+func (f *fakeClient) VolumeCreate(_ context.Context, options client.VolumeCreateOptions) (client.VolumeCreateResult, error) {
+	if f.volumeCreateFunc != nil {
+		return f.volumeCreateFunc(options)
+	}
+	return client.VolumeCreateResult{}, nil
+}
+
+func (f *fakeClient) NetworkInspect(_ context.Context, networkID string, _ client.NetworkInspectOptions) (client.NetworkInspectResult, error) {
+	if f.networkInspectFunc != nil {
+		return f.networkInspectFunc(networkID)
+	}
+	return client.NetworkInspectResult{}, nil
+}
+
+func (f *fakeClient) NetworkCreate(_ context.Context, name string, options client.NetworkCreateOptions) (client.NetworkCreateResult, error) {
+	if f.networkCreateFunc != nil {
+		return f.networkCreateFunc(name, options)
+	}
+	return client.NetworkCreateResult{}, nil
+}
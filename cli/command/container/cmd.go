@@ -52,7 +52,9 @@ func newContainerCommand(dockerCLI command.Cli) *cobra.Command {
 		newDiffCommand(dockerCLI),
 		newExecCommand(dockerCLI),
 		newExportCommand(dockerCLI),
+		newHealthCommand(dockerCLI),
 		newKillCommand(dockerCLI),
+		newMigrateCommand(dockerCLI),
 		newLogsCommand(dockerCLI),
 		newPauseCommand(dockerCLI),
 		newPortCommand(dockerCLI),
@@ -8,6 +8,7 @@
 	"sync"
 
 	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/pkg/kvfile"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
 	"github.com/moby/sys/capability"
@@ -125,7 +126,9 @@ func addCompletions(cmd *cobra.Command, dockerCLI completion.APIClientProvider)
 	_ = cmd.RegisterFlagCompletionFunc("cgroupns", completeCgroupns())
 	_ = cmd.RegisterFlagCompletionFunc("env", completion.EnvVarNames())
 	_ = cmd.RegisterFlagCompletionFunc("env-file", completion.FileNames())
+	_ = cmd.RegisterFlagCompletionFunc("env-file-format", completion.FromList(string(kvfile.FormatPlain), string(kvfile.FormatDotenv)))
 	_ = cmd.RegisterFlagCompletionFunc("ipc", completeIpc(dockerCLI))
+	_ = cmd.RegisterFlagCompletionFunc("label-file-format", completion.FromList(string(kvfile.FormatPlain), string(kvfile.FormatDotenv)))
 	_ = cmd.RegisterFlagCompletionFunc("link", completeLink(dockerCLI))
 	_ = cmd.RegisterFlagCompletionFunc("log-driver", completeLogDriver(dockerCLI))
 	_ = cmd.RegisterFlagCompletionFunc("log-opt", completeLogOpt)
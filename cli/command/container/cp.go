@@ -46,6 +46,9 @@ type cpConfig struct {
 	sourcePath string
 	destPath   string
 	container  string
+
+	// apiClient is the client for the daemon of the container.
+	apiClient client.APIClient
 }
 
 // copyProgressPrinter wraps io.ReadCloser to print progress information when
@@ -58,6 +61,7 @@ type copyProgressPrinter struct {
 const (
 	copyToContainerHeader       = "Copying to container - "
 	copyFromContainerHeader     = "Copying from container - "
+	copyAcrossContainersHeader  = "Copying between containers - "
 	copyProgressUpdateThreshold = 75 * time.Millisecond
 )
 
@@ -127,14 +131,18 @@ func newCopyCommand(dockerCLI command.Cli) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use: `cp [OPTIONS] CONTAINER:SRC_PATH DEST_PATH|-
-	docker cp [OPTIONS] SRC_PATH|- CONTAINER:DEST_PATH`,
-		Short: "Copy files/folders between a container and the local filesystem",
-		Long: `Copy files/folders between a container and the local filesystem
+	docker cp [OPTIONS] SRC_PATH|- CONTAINER:DEST_PATH
+	docker cp [OPTIONS] CONTAINER:SRC_PATH CONTAINER:DEST_PATH`,
+		Short: "Copy files/folders between a container and the local filesystem, or between containers",
+		Long: `Copy files/folders between a container and the local filesystem, or between containers
 
 Use '-' as the source to read a tar archive from stdin
 and extract it to a directory destination in a container.
 Use '-' as the destination to stream a tar archive of a
-container source to stdout.`,
+container source to stdout.
+
+Use CONTEXT/CONTAINER to refer to a container on the daemon
+of another context.`,
 		Args: cli.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if args[0] == "" {
@@ -227,25 +235,74 @@ func runCopy(ctx context.Context, dockerCli command.Cli, opts copyOptions) error
 	var direction copyDirection
 	if srcContainer != "" {
 		direction |= fromContainer
-		copyConfig.container = srcContainer
 	}
 	if destContainer != "" {
 		direction |= toContainer
-		copyConfig.container = destContainer
 	}
 
 	switch direction {
 	case fromContainer:
+		apiClient, ctr, closeClient, err := containerClient(ctx, dockerCli, srcContainer)
+		if err != nil {
+			return err
+		}
+		defer closeClient()
+		copyConfig.apiClient, copyConfig.container = apiClient, ctr
 		return copyFromContainer(ctx, dockerCli, copyConfig)
 	case toContainer:
+		apiClient, ctr, closeClient, err := containerClient(ctx, dockerCli, destContainer)
+		if err != nil {
+			return err
+		}
+		defer closeClient()
+		copyConfig.apiClient, copyConfig.container = apiClient, ctr
 		return copyToContainer(ctx, dockerCli, copyConfig)
 	case acrossContainers:
-		return errors.New("copying between containers is not supported")
+		if srcPath == "-" || destPath == "-" {
+			return errors.New(`"-" cannot be used when copying between containers`)
+		}
+		srcClient, srcCtr, closeSrc, err := containerClient(ctx, dockerCli, srcContainer)
+		if err != nil {
+			return err
+		}
+		defer closeSrc()
+		dstClient, dstCtr, closeDst, err := containerClient(ctx, dockerCli, destContainer)
+		if err != nil {
+			return err
+		}
+		defer closeDst()
+		src := copyConfig
+		src.apiClient, src.container = srcClient, srcCtr
+		dst := copyConfig
+		dst.apiClient, dst.container = dstClient, dstCtr
+		return copyAcrossContainers(ctx, dockerCli, src, dst)
 	default:
 		return errors.New("must specify at least one container source")
 	}
 }
 
+// containerClient returns the client for the daemon of the container in a
+// CONTAINER or CONTEXT/CONTAINER argument, and the name of the container.
+// The prefix is only used as a context if a context with that name exists,
+// as container names may contain a "/" in some setups (e.g., "host0/cname1").
+func containerClient(ctx context.Context, dockerCLI command.Cli, arg string) (_ client.APIClient, ctr string, closeClient func(), _ error) {
+	contextName, ctr, ok := strings.Cut(arg, "/")
+	if !ok || dockerCLI.ContextStore() == nil {
+		return dockerCLI.Client(), arg, func() {}, nil
+	}
+	if _, err := dockerCLI.ContextStore().GetMetadata(contextName); err != nil {
+		return dockerCLI.Client(), arg, func() {}, nil
+	}
+	if contextName == dockerCLI.CurrentContext() {
+		return dockerCLI.Client(), ctr, func() {}, nil
+	}
+	apiClient, err := command.NewAPIClientFromContext(ctx, dockerCLI, contextName)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return apiClient, ctr, func() { _ = apiClient.Close() }, nil
+}
+
 func resolveLocalPath(localPath string) (absPath string, _ error) {
 	absPath, err := filepath.Abs(localPath)
 	if err != nil {
@@ -270,26 +327,11 @@ func copyFromContainer(ctx context.Context, dockerCLI command.Cli, copyConfig cp
 		return err
 	}
 
-	apiClient := dockerCLI.Client()
+	apiClient := copyConfig.apiClient
 	// if client requests to follow symlinks, then must decide target file to be copied
 	var rebaseName string
 	if copyConfig.followLink {
-		src, err := apiClient.ContainerStatPath(ctx, copyConfig.container, client.ContainerStatPathOptions{
-			Path: srcPath,
-		})
-
-		// If the destination is a symbolic link, we should follow it.
-		if err == nil && src.Stat.Mode&os.ModeSymlink != 0 {
-			linkTarget := src.Stat.LinkTarget
-			if !isAbs(linkTarget) {
-				// Join with the parent directory.
-				srcParent, _ := archive.SplitPathDirEntry(srcPath)
-				linkTarget = filepath.Join(srcParent, linkTarget)
-			}
-
-			linkTarget, rebaseName = archive.GetRebaseName(srcPath, linkTarget)
-			srcPath = linkTarget
-		}
+		srcPath, rebaseName = resolveSourceLink(ctx, apiClient, copyConfig.container, srcPath)
 	}
 
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
@@ -365,37 +407,11 @@ func copyToContainer(ctx context.Context, dockerCLI command.Cli, copyConfig cpCo
 		srcPath = p
 	}
 
-	apiClient := dockerCLI.Client()
+	apiClient := copyConfig.apiClient
 	// Prepare destination copy info by stat-ing the container path.
-	dstInfo := archive.CopyInfo{Path: dstPath}
-	if dst, err := apiClient.ContainerStatPath(ctx, copyConfig.container, client.ContainerStatPathOptions{Path: dstPath}); err == nil {
-		// If the destination is a symbolic link, we should evaluate it.
-		if dst.Stat.Mode&os.ModeSymlink != 0 {
-			linkTarget := dst.Stat.LinkTarget
-			if !isAbs(linkTarget) {
-				// Join with the parent directory.
-				dstParent, _ := archive.SplitPathDirEntry(dstPath)
-				linkTarget = filepath.Join(dstParent, linkTarget)
-			}
-
-			dstInfo.Path = linkTarget
-			dst, err = apiClient.ContainerStatPath(ctx, copyConfig.container, client.ContainerStatPathOptions{Path: linkTarget})
-		}
-		// Validate the destination path
-		if err == nil {
-			if err := command.ValidateOutputPathFileMode(dst.Stat.Mode); err != nil {
-				return fmt.Errorf(`destination "%s:%s" must be a directory or a regular file: %w`, copyConfig.container, dstPath, err)
-			}
-			dstInfo.Exists, dstInfo.IsDir = true, dst.Stat.Mode.IsDir()
-		}
-
-		// Ignore any error and assume that the parent directory of the destination
-		// path exists, in which case the copy may still succeed. If there is any
-		// type of conflict (e.g., non-directory overwriting an existing directory
-		// or vice versa) the extraction will fail. If the destination simply did
-		// not exist, but the parent directory does, the extraction will still
-		// succeed.
-		_ = err // Intentionally ignore stat errors (see above)
+	dstInfo, err := containerDestInfo(ctx, apiClient, copyConfig.container, dstPath)
+	if err != nil {
+		return err
 	}
 
 	var (
@@ -470,7 +486,7 @@ func copyToContainer(ctx context.Context, dockerCLI command.Cli, copyConfig cpCo
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
 	restore, done := copyProgress(ctx, dockerCLI.Err(), copyToContainerHeader, &copiedSize)
 	// TODO(thaJeztah): error-handling looks odd here; should it be handled differently?
-	_, err := apiClient.CopyToContainer(ctx, copyConfig.container, options)
+	_, err = apiClient.CopyToContainer(ctx, copyConfig.container, options)
 	cancel()
 	<-done
 	restore()
@@ -483,6 +499,134 @@ func copyToContainer(ctx context.Context, dockerCLI command.Cli, copyConfig cpCo
 	return err
 }
 
+// copyAcrossContainers streams the archive of the source path in the src
+// container into the dst container, without writing it to the local
+// filesystem. The containers may be on the daemons of different contexts.
+func copyAcrossContainers(ctx context.Context, dockerCLI command.Cli, src, dst cpConfig) error {
+	srcPath := src.sourcePath
+	var rebaseName string
+	if src.followLink {
+		srcPath, rebaseName = resolveSourceLink(ctx, src.apiClient, src.container, srcPath)
+	}
+	dstInfo, err := containerDestInfo(ctx, dst.apiClient, dst.container, dst.destPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	cpRes, err := src.apiClient.CopyFromContainer(ctx, src.container, client.CopyFromContainerOptions{
+		SourcePath: srcPath,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = cpRes.Content.Close() }()
+
+	srcInfo := archive.CopyInfo{
+		Path:       srcPath,
+		Exists:     true,
+		IsDir:      cpRes.Stat.Mode.IsDir(),
+		RebaseName: rebaseName,
+	}
+	srcArchive := cpRes.Content
+	if len(srcInfo.RebaseName) != 0 {
+		_, srcBase := archive.SplitPathDirEntry(srcInfo.Path)
+		srcArchive = archive.RebaseArchiveEntries(srcArchive, srcBase, srcInfo.RebaseName)
+	}
+
+	// Alter the archive for the destination in the same way as when copying
+	// from the local filesystem; see copyToContainer.
+	dstDir, preparedArchive, err := archive.PrepareArchiveCopy(srcArchive, srcInfo, dstInfo)
+	if err != nil {
+		return err
+	}
+	defer preparedArchive.Close()
+
+	var copiedSize int64
+	options := client.CopyToContainerOptions{
+		DestinationPath: dstDir,
+		Content:         preparedArchive,
+		CopyUIDGID:      dst.copyUIDGID,
+	}
+	if dst.quiet {
+		_, err := dst.apiClient.CopyToContainer(ctx, dst.container, options)
+		return err
+	}
+
+	options.Content = &copyProgressPrinter{ReadCloser: preparedArchive, total: &copiedSize}
+	restore, done := copyProgress(ctx, dockerCLI.Err(), copyAcrossContainersHeader, &copiedSize)
+	_, err = dst.apiClient.CopyToContainer(ctx, dst.container, options)
+	cancel()
+	<-done
+	restore()
+	reportedSize := copiedSize
+	if !cpRes.Stat.Mode.IsDir() {
+		reportedSize = cpRes.Stat.Size
+	}
+	_, _ = fmt.Fprint(dockerCLI.Err(), copySummary(reportedSize, copiedSize, dst.container+":"+dstInfo.Path))
+	return err
+}
+
+// resolveSourceLink returns the path of the target of the source path in
+// the container if it's a symbolic link, and the name to rebase the archive
+// entries to. The source path is returned as-is if it's not a symbolic link.
+func resolveSourceLink(ctx context.Context, apiClient client.APIClient, ctr, srcPath string) (linkTarget, rebaseName string) {
+	src, err := apiClient.ContainerStatPath(ctx, ctr, client.ContainerStatPathOptions{
+		Path: srcPath,
+	})
+
+	// If the destination is a symbolic link, we should follow it.
+	if err != nil || src.Stat.Mode&os.ModeSymlink == 0 {
+		return srcPath, ""
+	}
+	linkTarget = src.Stat.LinkTarget
+	if !isAbs(linkTarget) {
+		// Join with the parent directory.
+		srcParent, _ := archive.SplitPathDirEntry(srcPath)
+		linkTarget = filepath.Join(srcParent, linkTarget)
+	}
+	return archive.GetRebaseName(srcPath, linkTarget)
+}
+
+// containerDestInfo returns the copy info of the destination path in the
+// container, by stat-ing the path.
+func containerDestInfo(ctx context.Context, apiClient client.APIClient, ctr, dstPath string) (archive.CopyInfo, error) {
+	dstInfo := archive.CopyInfo{Path: dstPath}
+	dst, err := apiClient.ContainerStatPath(ctx, ctr, client.ContainerStatPathOptions{Path: dstPath})
+	if err != nil {
+		// Ignore any error and assume that the parent directory of the destination
+		// path exists, in which case the copy may still succeed. If there is any
+		// type of conflict (e.g., non-directory overwriting an existing directory
+		// or vice versa) the extraction will fail. If the destination simply did
+		// not exist, but the parent directory does, the extraction will still
+		// succeed.
+		return dstInfo, nil
+	}
+
+	// If the destination is a symbolic link, we should evaluate it.
+	if dst.Stat.Mode&os.ModeSymlink != 0 {
+		linkTarget := dst.Stat.LinkTarget
+		if !isAbs(linkTarget) {
+			// Join with the parent directory.
+			dstParent, _ := archive.SplitPathDirEntry(dstPath)
+			linkTarget = filepath.Join(dstParent, linkTarget)
+		}
+
+		dstInfo.Path = linkTarget
+		dst, err = apiClient.ContainerStatPath(ctx, ctr, client.ContainerStatPathOptions{Path: linkTarget})
+	}
+	// Validate the destination path
+	if err == nil {
+		if err := command.ValidateOutputPathFileMode(dst.Stat.Mode); err != nil {
+			return dstInfo, fmt.Errorf(`destination "%s:%s" must be a directory or a regular file: %w`, ctr, dstPath, err)
+		}
+		dstInfo.Exists, dstInfo.IsDir = true, dst.Stat.Mode.IsDir()
+	}
+	return dstInfo, nil
+}
+
 // We use `:` as a delimiter between CONTAINER and PATH, but `:` could also be
 // in a valid LOCALPATH, like `file:name.txt`. We can resolve this ambiguity by
 // requiring a LOCALPATH with a `:` to be made explicit with a relative or
@@ -8,6 +8,9 @@
 	"strings"
 	"testing"
 
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/store"
 	"github.com/docker/cli/internal/test"
 	"github.com/moby/go-archive"
 	"github.com/moby/go-archive/compression"
@@ -25,12 +28,12 @@ func TestRunCopyWithInvalidArguments(t *testing.T) {
 		expectedErr string
 	}{
 		{
-			doc: "copy between container",
+			doc: "copy between container from stdin",
 			options: copyOptions{
-				source:      "first:/path",
+				source:      "first:-",
 				destination: "second:/path",
 			},
-			expectedErr: "copying between containers is not supported",
+			expectedErr: `"-" cannot be used when copying between containers`,
 		},
 		{
 			doc: "copy without a container",
@@ -69,6 +72,103 @@ func TestRunCopyFromContainerToStdout(t *testing.T) {
 	assert.Check(t, is.Equal("", cli.ErrBuffer().String()))
 }
 
+func TestRunCopyBetweenContainers(t *testing.T) {
+	srcDir := fs.NewDir(t, "cp-test",
+		fs.WithFile("file1", "content\n"))
+	var copied string
+	cli := test.NewFakeCli(&fakeClient{
+		containerCopyFromFunc: func(ctr, srcPath string) (client.CopyFromContainerResult, error) {
+			assert.Check(t, is.Equal("first", ctr))
+			assert.Check(t, is.Equal("/path/file1", srcPath))
+			readCloser, err := archive.Tar(srcDir.Path(), compression.None)
+			return client.CopyFromContainerResult{
+				Content: readCloser,
+				Stat:    container.PathStat{Name: "file1", Size: 8},
+			}, err
+		},
+		containerStatPathFunc: func(ctr, path string) (client.ContainerStatPathResult, error) {
+			assert.Check(t, is.Equal("second", ctr))
+			return client.ContainerStatPathResult{
+				Stat: container.PathStat{Name: "dest", Mode: os.ModeDir | 0o755},
+			}, nil
+		},
+		containerCopyToFunc: func(ctr string, options client.CopyToContainerOptions) (client.CopyToContainerResult, error) {
+			assert.Check(t, is.Equal("second", ctr))
+			assert.Check(t, is.Equal("/dest", options.DestinationPath))
+			b, err := io.ReadAll(options.Content)
+			copied = string(b)
+			return client.CopyToContainerResult{}, err
+		},
+	})
+	err := runCopy(context.TODO(), cli, copyOptions{
+		source:      "first:/path/file1",
+		destination: "second:/dest",
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(copied, "content\n"))
+	errOut := cli.ErrBuffer().String()
+	assert.Check(t, is.Contains(errOut, "Successfully copied 8B"))
+	assert.Check(t, is.Contains(errOut, "to second:/dest"))
+}
+
+func TestContainerClient(t *testing.T) {
+	contextStore := store.New(t.TempDir(), command.DefaultContextStoreConfig())
+	for _, name := range []string{"remote", "current"} {
+		assert.NilError(t, contextStore.CreateOrUpdate(store.Metadata{
+			Name: name,
+			Endpoints: map[string]any{
+				docker.DockerEndpoint: docker.EndpointMeta{Host: "tcp://" + name + ".example.com:2376"},
+			},
+			Metadata: command.DockerContext{},
+		}))
+	}
+	fakeCli := test.NewFakeCli(&fakeClient{})
+	fakeCli.SetContextStore(contextStore)
+	fakeCli.SetCurrentContext("current")
+
+	tests := []struct {
+		doc          string
+		arg          string
+		expectedCtr  string
+		expectedHost string // empty if the client of the current context is used
+	}{
+		{
+			doc:         "container",
+			arg:         "web",
+			expectedCtr: "web",
+		},
+		{
+			doc:          "context and container",
+			arg:          "remote/web",
+			expectedCtr:  "web",
+			expectedHost: "tcp://remote.example.com:2376",
+		},
+		{
+			doc:         "prefix that's not a context",
+			arg:         "host0/cname1",
+			expectedCtr: "host0/cname1",
+		},
+		{
+			doc:         "current context",
+			arg:         "current/web",
+			expectedCtr: "web",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			apiClient, ctr, closeClient, err := containerClient(context.Background(), fakeCli, tc.arg)
+			assert.NilError(t, err)
+			defer closeClient()
+			assert.Check(t, is.Equal(ctr, tc.expectedCtr))
+			if tc.expectedHost == "" {
+				assert.Check(t, apiClient == fakeCli.Client(), "expected the client of the current context")
+			} else {
+				assert.Check(t, is.Equal(apiClient.DaemonHost(), tc.expectedHost))
+			}
+		})
+	}
+}
+
 func TestRunCopyFromContainerToFilesystem(t *testing.T) {
 	srcDir := fs.NewDir(t, "cp-test",
 		fs.WithFile("file1", "content\n"))
@@ -48,12 +48,16 @@ type createOptions struct {
 func newCreateCommand(dockerCLI command.Cli) *cobra.Command {
 	var options createOptions
 	var copts *containerOptions
+	var presetOpts presetOptions
 
 	cmd := &cobra.Command{
 		Use:   "create [OPTIONS] IMAGE [COMMAND] [ARG...]",
 		Short: "Create a new container",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  presetArgs(&presetOpts, cli.RequiresMinArgs(1)),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if presetOpts.name != "" || presetOpts.print {
+				return runWithPreset(cmd, dockerCLI, &presetOpts, newCreateCommand, args)
+			}
 			copts.Image = args[0]
 			if len(args) > 1 {
 				copts.Args = args[1:]
@@ -91,6 +95,7 @@ func newCreateCommand(dockerCLI command.Cli) *cobra.Command {
 	copts = addFlags(flags)
 
 	addCompletions(cmd, dockerCLI)
+	addPresetFlags(cmd, dockerCLI, &presetOpts)
 
 	return cmd
 }
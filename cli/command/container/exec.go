@@ -11,6 +11,7 @@
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/opts"
+	"github.com/docker/cli/pkg/kvfile"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
 	"github.com/sirupsen/logrus"
@@ -29,6 +30,8 @@ type ExecOptions struct {
 	Workdir     string
 	Command     []string
 	EnvFile     opts.ListOpts
+	// EnvFileFormat is the format of env-files; see [kvfile.Format].
+	EnvFileFormat string
 }
 
 // NewExecOptions creates a new ExecOptions
@@ -75,11 +78,13 @@ func newExecCommand(dockerCLI command.Cli) *cobra.Command {
 	_ = flags.SetAnnotation("env", "version", []string{"1.25"})
 	flags.Var(&options.EnvFile, "env-file", "Read in a file of environment variables")
 	_ = flags.SetAnnotation("env-file", "version", []string{"1.25"})
+	flags.StringVar(&options.EnvFileFormat, "env-file-format", string(kvfile.FormatPlain), `Format of env-files ("plain", "dotenv")`)
 	flags.StringVarP(&options.Workdir, "workdir", "w", "", "Working directory inside the container")
 	_ = flags.SetAnnotation("workdir", "version", []string{"1.35"})
 
 	_ = cmd.RegisterFlagCompletionFunc("env", completion.EnvVarNames())
 	_ = cmd.RegisterFlagCompletionFunc("env-file", completion.FileNames())
+	_ = cmd.RegisterFlagCompletionFunc("env-file-format", completion.FromList(string(kvfile.FormatPlain), string(kvfile.FormatDotenv)))
 
 	return cmd
 }
@@ -230,7 +235,7 @@ func parseExec(execOpts ExecOptions, configFile *configfile.ConfigFile) (*client
 
 	// collect all the environment variables for the container
 	var err error
-	if execOptions.Env, err = opts.ReadKVEnvStrings(execOpts.EnvFile.GetSlice(), execOpts.Env.GetSlice()); err != nil {
+	if execOptions.Env, err = opts.ReadKVEnvStringsWithFormat(execOpts.EnvFile.GetSlice(), execOpts.Env.GetSlice(), kvfile.Format(execOpts.EnvFileFormat)); err != nil {
 		return nil, err
 	}
 
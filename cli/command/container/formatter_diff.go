@@ -15,8 +15,8 @@
 
 // newDiffFormat returns a format for use with a diff [formatter.Context].
 func newDiffFormat(source string) formatter.Format {
-	if source == formatter.TableFormatKey {
-		return defaultDiffTableFormat
+	if source == formatter.TableFormatKey || source == formatter.CSVFormatKey {
+		return formatter.TableOrCSV(source, defaultDiffTableFormat)
 	}
 	return formatter.Format(source)
 }
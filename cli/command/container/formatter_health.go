@@ -0,0 +1,138 @@
+package container
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/moby/moby/api/types/container"
+)
+
+const (
+	defaultHealthTableFormat = "table {{.Container}}\t{{.Status}}\t{{.FailingStreak}}\t{{.Start}}\t{{.End}}\t{{.ExitCode}}\t{{.Output}}"
+
+	healthStatusHeader  = "STATUS"
+	failingStreakHeader = "FAILING STREAK"
+	probeStartHeader    = "START"
+	probeEndHeader      = "END"
+	exitCodeHeader      = "EXIT CODE"
+	outputHeader        = "OUTPUT"
+
+	// maxHealthOutputWidth is the maximum width of the probe's output if
+	// the output is truncated.
+	maxHealthOutputWidth = 60
+)
+
+// newHealthFormat returns a format for use with a health [formatter.Context].
+func newHealthFormat(source string) formatter.Format {
+	if source == "" || source == formatter.TableFormatKey || source == formatter.CSVFormatKey {
+		return formatter.TableOrCSV(source, defaultHealthTableFormat)
+	}
+	return formatter.Format(source)
+}
+
+// healthFormatWrite writes the health check results of the containers using
+// the [formatter.Context]. A row is written for each probe in the health
+// check log; containers without results are written as a single row with
+// only the container's health status.
+func healthFormatWrite(fmtCtx formatter.Context, containers []container.InspectResponse) error {
+	return fmtCtx.Write(newHealthContext(), func(format func(subContext formatter.SubContext) error) error {
+		for _, ctr := range containers {
+			var health container.Health
+			if ctr.State != nil && ctr.State.Health != nil {
+				health = *ctr.State.Health
+			}
+			if len(health.Log) == 0 {
+				if err := format(&healthContext{trunc: fmtCtx.Trunc, name: ctr.Name, health: health}); err != nil {
+					return err
+				}
+				continue
+			}
+			for _, result := range health.Log {
+				if err := format(&healthContext{trunc: fmtCtx.Trunc, name: ctr.Name, health: health, result: result}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+type healthContext struct {
+	formatter.HeaderContext
+	trunc  bool
+	name   string
+	health container.Health
+	result *container.HealthcheckResult
+}
+
+func newHealthContext() *healthContext {
+	return &healthContext{
+		HeaderContext: formatter.HeaderContext{
+			Header: formatter.SubHeaderContext{
+				"Container":     containerHeader,
+				"Status":        healthStatusHeader,
+				"FailingStreak": failingStreakHeader,
+				"Start":         probeStartHeader,
+				"End":           probeEndHeader,
+				"ExitCode":      exitCodeHeader,
+				"Output":        outputHeader,
+			},
+		},
+	}
+}
+
+func (h *healthContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(h)
+}
+
+func (h *healthContext) Container() string {
+	return strings.TrimPrefix(h.name, "/")
+}
+
+// Status returns the container's health status, or "none" if the container
+// has no health check.
+func (h *healthContext) Status() string {
+	if h.health.Status == "" {
+		return string(container.NoHealthcheck)
+	}
+	return string(h.health.Status)
+}
+
+func (h *healthContext) FailingStreak() string {
+	return strconv.Itoa(h.health.FailingStreak)
+}
+
+func (h *healthContext) Start() string {
+	if h.result == nil || h.result.Start.IsZero() {
+		return ""
+	}
+	return h.result.Start.Format(time.RFC3339)
+}
+
+func (h *healthContext) End() string {
+	if h.result == nil || h.result.End.IsZero() {
+		return ""
+	}
+	return h.result.End.Format(time.RFC3339)
+}
+
+func (h *healthContext) ExitCode() string {
+	if h.result == nil {
+		return ""
+	}
+	return strconv.Itoa(h.result.ExitCode)
+}
+
+// Output returns the output of the probe. If trunc is set, whitespace and
+// newlines are collapsed, and the output is truncated.
+func (h *healthContext) Output() string {
+	if h.result == nil {
+		return ""
+	}
+	if !h.trunc {
+		return h.result.Output
+	}
+	return formatter.Ellipsis(strings.Join(strings.Fields(h.result.Output), " "), maxHealthOutputWidth)
+}
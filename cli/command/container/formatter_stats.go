@@ -103,11 +103,11 @@ func (cs *Stats) GetStatistics() StatsEntry {
 
 // NewStatsFormat returns a format for rendering an CStatsContext
 func NewStatsFormat(source, osType string) formatter.Format {
-	if source == formatter.TableFormatKey {
+	if source == formatter.TableFormatKey || source == formatter.CSVFormatKey {
 		if osType == winOSType {
-			return winDefaultStatsTableFormat
+			return formatter.TableOrCSV(source, winDefaultStatsTableFormat)
 		}
-		return defaultStatsTableFormat
+		return formatter.TableOrCSV(source, defaultStatsTableFormat)
 	}
 	return formatter.Format(source)
 }
@@ -0,0 +1,130 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
+	"github.com/spf13/cobra"
+)
+
+type healthOptions struct {
+	containers []string
+	watch      bool
+	noTrunc    bool
+	format     string
+}
+
+// newHealthCommand creates a new cobra.Command for "docker container health".
+func newHealthCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts healthOptions
+
+	cmd := &cobra.Command{
+		Use:   "health [OPTIONS] CONTAINER [CONTAINER...]",
+		Short: "Display the health check results of one or more containers",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.containers = args
+			return runHealth(cmd.Context(), dockerCLI, &opts)
+		},
+		ValidArgsFunction:     completion.ContainerNames(dockerCLI, false),
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.watch, "watch", "w", false, "Watch for health check results, and refresh the output")
+	flags.BoolVar(&opts.noTrunc, "no-trunc", false, "Don't truncate output")
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	return cmd
+}
+
+func runHealth(ctx context.Context, dockerCLI command.Cli, opts *healthOptions) error {
+	apiClient := dockerCLI.Client()
+	format := newHealthFormat(opts.format)
+
+	containers, err := inspectContainers(ctx, apiClient, opts.containers)
+	if err != nil {
+		return err
+	}
+	if !opts.watch {
+		return healthFormatWrite(formatter.Context{
+			Output: dockerCLI.Out(),
+			Format: format,
+			Trunc:  !opts.noTrunc,
+		}, containers)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	f := make(client.Filters).
+		Add("type", string(events.ContainerEventType)).
+		Add("event", string(events.ActionHealthStatus), string(events.ActionStart), string(events.ActionDie))
+	for _, ctr := range containers {
+		f.Add("container", ctr.ID)
+	}
+	res := apiClient.Events(ctx, client.EventsListOptions{Filters: f})
+
+	// Table formats are refreshed in-place; other formats print the results
+	// after each update.
+	var buf bytes.Buffer
+	render := func(containers []container.InspectResponse) error {
+		buf.Reset()
+		if format.IsTable() {
+			_, _ = io.WriteString(&buf, "\033[H\033[J")
+		}
+		if err := healthFormatWrite(formatter.Context{
+			Output: &buf,
+			Format: format,
+			Trunc:  !opts.noTrunc,
+		}, containers); err != nil {
+			return err
+		}
+		_, _ = dockerCLI.Out().Write(buf.Bytes())
+		return nil
+	}
+
+	if err := render(containers); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-res.Err:
+			if err == nil || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil
+			}
+			return err
+		case <-res.Messages:
+			containers, err = inspectContainers(ctx, apiClient, opts.containers)
+			if err != nil {
+				return err
+			}
+			if err := render(containers); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func inspectContainers(ctx context.Context, apiClient client.APIClient, containers []string) ([]container.InspectResponse, error) {
+	out := make([]container.InspectResponse, 0, len(containers))
+	for _, ctr := range containers {
+		res, err := apiClient.ContainerInspect(ctx, ctr, client.ContainerInspectOptions{})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, res.Container)
+	}
+	return out, nil
+}
@@ -0,0 +1,133 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+func healthInspectFunc(id string) (client.ContainerInspectResult, error) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	switch id {
+	case "web":
+		return client.ContainerInspectResult{
+			Container: container.InspectResponse{
+				ID:   "web-id",
+				Name: "/web",
+				State: &container.State{
+					Running: true,
+					Health: &container.Health{
+						Status:        container.Unhealthy,
+						FailingStreak: 2,
+						Log: []*container.HealthcheckResult{
+							{Start: start, End: start.Add(time.Second), ExitCode: 0, Output: "ok\n"},
+							{Start: start.Add(30 * time.Second), End: start.Add(31 * time.Second), ExitCode: 1, Output: "curl: (7) Failed to connect to localhost port 80 after 0 ms:\nCouldn't connect to server\n"},
+							{Start: start.Add(60 * time.Second), End: start.Add(61 * time.Second), ExitCode: 1, Output: "curl: (7) Failed to connect to localhost port 80 after 0 ms:\nCouldn't connect to server\n"},
+						},
+					},
+				},
+			},
+		}, nil
+	case "db":
+		return client.ContainerInspectResult{
+			Container: container.InspectResponse{
+				ID:    "db-id",
+				Name:  "/db",
+				State: &container.State{Running: true},
+			},
+		}, nil
+	default:
+		return client.ContainerInspectResult{}, errors.New("no such container: " + id)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	tests := []struct {
+		doc    string
+		args   []string
+		golden string
+	}{
+		{
+			doc:    "default",
+			args:   []string{"web", "db"},
+			golden: "container-health.golden",
+		},
+		{
+			doc:    "no-trunc",
+			args:   []string{"--no-trunc", "--format", "{{.Container}}: {{.ExitCode}} {{json .Output}}", "web"},
+			golden: "container-health-no-trunc.golden",
+		},
+		{
+			doc:    "json",
+			args:   []string{"--format", "json", "db"},
+			golden: "container-health-json.golden",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{inspectFunc: healthInspectFunc})
+			cmd := newHealthCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), tc.golden)
+		})
+	}
+}
+
+func TestHealthNotFound(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{inspectFunc: healthInspectFunc})
+	cmd := newHealthCommand(cli)
+	cmd.SetArgs([]string{"web", "nosuchcontainer"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "no such container: nosuchcontainer"))
+}
+
+func TestHealthWatch(t *testing.T) {
+	messages := make(chan events.Message, 1)
+	errs := make(chan error, 1)
+	var filters client.Filters
+
+	inspectCount := 0
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(id string) (client.ContainerInspectResult, error) {
+			inspectCount++
+			if inspectCount == 2 {
+				// Stop watching after refreshing the output.
+				errs <- io.EOF
+			}
+			return healthInspectFunc(id)
+		},
+		eventsFunc: func(_ context.Context, options client.EventsListOptions) client.EventsResult {
+			filters = options.Filters
+			messages <- events.Message{Action: events.ActionHealthStatusUnhealthy, Actor: events.Actor{ID: "web-id"}}
+			return client.EventsResult{Messages: messages, Err: errs}
+		},
+	})
+	cmd := newHealthCommand(cli)
+	cmd.SetArgs([]string{"--watch", "--format", "{{.Container}} {{.Status}} {{.ExitCode}}", "web"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.DeepEqual(filters, make(client.Filters).
+		Add("type", "container").
+		Add("event", "health_status", "start", "die").
+		Add("container", "web-id"),
+	))
+	expected := strings.Repeat("web unhealthy 0\nweb unhealthy 1\nweb unhealthy 1\n", 2)
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), expected))
+}
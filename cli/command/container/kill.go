@@ -5,7 +5,6 @@
 	"errors"
 	"fmt"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/moby/moby/client"
@@ -14,20 +13,25 @@
 
 type killOptions struct {
 	signal string
+	bulk   bulkOptions
 
 	containers []string
 }
 
 // newKillCommand creates a new cobra.Command for "docker container kill"
 func newKillCommand(dockerCLI command.Cli) *cobra.Command {
-	var opts killOptions
+	opts := killOptions{bulk: newBulkOptions()}
 
 	cmd := &cobra.Command{
 		Use:   "kill [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Kill one or more running containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  opts.bulk.args,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.containers = args
+			var err error
+			opts.containers, err = opts.bulk.containers(cmd.Context(), dockerCLI, args, "kill")
+			if err != nil {
+				return err
+			}
 			return runKill(cmd.Context(), dockerCLI, &opts)
 		},
 		Annotations: map[string]string{
@@ -39,6 +43,7 @@ func newKillCommand(dockerCLI command.Cli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.StringVarP(&opts.signal, "signal", "s", "", "Signal to send to the container")
+	opts.bulk.installFlags(flags, defaultParallel)
 
 	_ = cmd.RegisterFlagCompletionFunc("signal", completeSignals)
 
@@ -47,7 +52,7 @@ func newKillCommand(dockerCLI command.Cli) *cobra.Command {
 
 func runKill(ctx context.Context, dockerCLI command.Cli, opts *killOptions) error {
 	apiClient := dockerCLI.Client()
-	errChan := parallelOperation(ctx, opts.containers, func(ctx context.Context, container string) error {
+	errChan := parallelOperation(ctx, opts.containers, opts.bulk.parallel, func(ctx context.Context, container string) error {
 		_, err := apiClient.ContainerKill(ctx, container, client.ContainerKillOptions{
 			Signal: opts.signal,
 		})
@@ -25,6 +25,7 @@ type psOptions struct {
 	last        int
 	format      string
 	filter      opts.FilterOpt
+	list        formatter.ListOptions
 }
 
 // newPsCommand creates a new cobra.Command for "docker container ps"
@@ -57,6 +58,7 @@ func newPsCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.IntVarP(&options.last, "last", "n", -1, "Show n last created containers (includes all states)")
 	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
+	options.list.InstallFlags(flags)
 
 	return cmd
 }
@@ -111,6 +113,12 @@ func buildContainerListOptions(options *psOptions) (client.ContainerListOptions,
 		}
 	}
 
+	// request size if it's used as column, or to sort by, unless `size` was
+	// explicitly set to false.
+	if !options.quiet && !listOptions.Size && !options.sizeChanged && options.list.Uses("Size") {
+		listOptions.Size = true
+	}
+
 	return listOptions, nil
 }
 
@@ -136,6 +144,8 @@ func runPs(ctx context.Context, dockerCLI command.Cli, options *psOptions) error
 		Output: dockerCLI.Out(),
 		Format: formatter.NewContainerFormat(options.format, options.quiet, listOptions.Size),
 		Trunc:  !options.noTrunc,
+
+		ListOptions: options.list,
 	}
 	return formatter.ContainerWrite(containerCtx, res.Items)
 }
@@ -5,6 +5,7 @@
 	"io"
 	"testing"
 
+	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/cli/internal/test/builders"
@@ -93,6 +94,24 @@ func TestContainerListBuildContainerListOptions(t *testing.T) {
 			expectedLimit:   5,
 			expectedFilters: make(client.Filters).Add("foo", "bar").Add("baz", "foo"),
 		},
+		{
+			psOpts: &psOptions{
+				last: -1,
+				// With "size" as column, size should be true
+				list: formatter.ListOptions{Columns: []string{"ID", "size"}},
+			},
+			expectedSize:  true,
+			expectedLimit: -1,
+		},
+		{
+			psOpts: &psOptions{
+				last: -1,
+				// When sorting by size, size should be true
+				list: formatter.ListOptions{Sort: []string{"Size:desc"}},
+			},
+			expectedSize:  true,
+			expectedLimit: -1,
+		},
 	}
 
 	for _, c := range contexts {
@@ -331,6 +350,16 @@ func TestContainerListWithFormat(t *testing.T) {
 		golden.Assert(t, cli.OutBuffer().String(), "container-list-with-format.golden")
 	})
 
+	t.Run("with sort and columns", func(t *testing.T) {
+		cli.OutBuffer().Reset()
+		cmd := newListCommand(cli)
+		cmd.SetArgs([]string{"--sort", "names:desc", "--columns", "names,image"})
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		assert.NilError(t, cmd.Execute())
+		golden.Assert(t, cli.OutBuffer().String(), "container-list-sort-columns.golden")
+	})
+
 	t.Run("with format and quiet", func(t *testing.T) {
 		cli.OutBuffer().Reset()
 		cmd := newListCommand(cli)
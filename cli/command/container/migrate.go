@@ -0,0 +1,375 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	"github.com/docker/cli/cli/command/image"
+	"github.com/docker/cli/internal/volumehelper"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/mount"
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/api/types/volume"
+	"github.com/moby/moby/client"
+	"github.com/spf13/cobra"
+)
+
+type migrateOptions struct {
+	container string
+	to        string
+	stop      bool
+	dryRun    bool
+	quiet     bool
+}
+
+// newMigrateCommand creates a new cobra.Command for "docker container migrate".
+func newMigrateCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts migrateOptions
+
+	cmd := &cobra.Command{
+		Use:   "migrate [OPTIONS] CONTAINER",
+		Short: "Migrate a container and its volumes to the daemon of another context",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.container = args[0]
+			if opts.to == dockerCLI.CurrentContext() {
+				return fmt.Errorf("cannot migrate container to context %q: context is the current context", opts.to)
+			}
+			dst, err := command.NewAPIClientFromContext(cmd.Context(), dockerCLI, opts.to)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = dst.Close() }()
+			return runMigrate(cmd.Context(), dockerCLI, dst, &opts)
+		},
+		ValidArgsFunction:     completion.ContainerNames(dockerCLI, true),
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.to, "to", "", "Name of the context to migrate the container to")
+	flags.BoolVar(&opts.stop, "stop", false, "Stop the container before copying its volumes")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "Show what would be migrated, without migrating")
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress the image transfer output")
+	_ = cmd.MarkFlagRequired("to")
+
+	_ = cmd.RegisterFlagCompletionFunc("to", completion.ContextNames(dockerCLI))
+	return cmd
+}
+
+// migrationPlan describes the objects that are migrated with a container.
+type migrationPlan struct {
+	container container.InspectResponse
+
+	// image is the image that's transferred, and used to create the
+	// container on the destination. It's the image reference the container
+	// was created with, if that still resolves to the image the container
+	// runs, or the ID of that image otherwise.
+	image    string
+	volumes  []migrateVolume
+	networks []migrateNetwork
+
+	// binds are the sources of bind mounts, of which the content is not
+	// migrated.
+	binds []string
+}
+
+type migrateVolume struct {
+	volume volume.Volume
+	exists bool
+}
+
+type migrateNetwork struct {
+	network network.Inspect
+	exists  bool
+}
+
+func (p *migrationPlan) name() string {
+	return strings.TrimPrefix(p.container.Name, "/")
+}
+
+// runMigrate recreates the container on the dst daemon. The image of the
+// container is transferred, the content of its named volumes is copied,
+// and the user-defined networks it's connected to are created if missing.
+// The container is started on the dst daemon if it's running.
+func runMigrate(ctx context.Context, dockerCLI command.Cli, dst client.APIClient, opts *migrateOptions) error {
+	src := dockerCLI.Client()
+	plan, err := planMigration(ctx, src, dst, opts.container)
+	if err != nil {
+		return err
+	}
+	if opts.dryRun {
+		printMigrationPlan(dockerCLI.Out(), plan, opts)
+		return nil
+	}
+	for _, b := range plan.binds {
+		_, _ = fmt.Fprintf(dockerCLI.Err(), "WARNING: the content of bind mount %s is not migrated\n", b)
+	}
+
+	c := plan.container
+	if plan.image != c.Config.Image {
+		_, _ = fmt.Fprintf(dockerCLI.Err(), "WARNING: image %s no longer refers to the image of container %s; migrating image %s\n", c.Config.Image, plan.name(), plan.image)
+	}
+	if err := image.TransferImage(ctx, dockerCLI, dst, plan.image, opts.quiet); err != nil {
+		return fmt.Errorf("failed to transfer image %s: %w", plan.image, err)
+	}
+	for _, n := range plan.networks {
+		if n.exists {
+			continue
+		}
+		if err := createNetwork(ctx, dst, n.network); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(dockerCLI.Out(), "Created network %s\n", n.network.Name)
+	}
+	if opts.stop && c.State != nil && c.State.Running {
+		if _, err := src.ContainerStop(ctx, c.ID, client.ContainerStopOptions{}); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(dockerCLI.Out(), "Stopped container %s\n", plan.name())
+	}
+	for _, v := range plan.volumes {
+		if !v.exists {
+			if _, err := dst.VolumeCreate(ctx, client.VolumeCreateOptions{
+				Name:       v.volume.Name,
+				Driver:     v.volume.Driver,
+				DriverOpts: v.volume.Options,
+				Labels:     v.volume.Labels,
+			}); err != nil {
+				return err
+			}
+		}
+		if err := copyVolume(ctx, src, dst, v.volume.Name); err != nil {
+			return fmt.Errorf("failed to copy volume %s: %w", v.volume.Name, err)
+		}
+		_, _ = fmt.Fprintf(dockerCLI.Out(), "Copied volume %s\n", v.volume.Name)
+	}
+
+	res, err := dst.ContainerCreate(ctx, migrateCreateOptions(plan))
+	if err != nil {
+		return err
+	}
+	for _, w := range res.Warnings {
+		_, _ = fmt.Fprintln(dockerCLI.Err(), "WARNING:", w)
+	}
+	if c.State != nil && c.State.Running {
+		if _, err := dst.ContainerStart(ctx, res.ID, client.ContainerStartOptions{}); err != nil {
+			return err
+		}
+	}
+	_, _ = fmt.Fprintln(dockerCLI.Out(), plan.name())
+	return nil
+}
+
+// planMigration collects the objects that are migrated with the container,
+// and whether they exist on the dst daemon.
+func planMigration(ctx context.Context, src, dst client.APIClient, ctr string) (*migrationPlan, error) {
+	res, err := src.ContainerInspect(ctx, ctr, client.ContainerInspectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	c := res.Container
+	if c.Config == nil || c.HostConfig == nil {
+		return nil, fmt.Errorf("container %s has no configuration", ctr)
+	}
+	if c.HostConfig.NetworkMode.IsContainer() {
+		return nil, fmt.Errorf("cannot migrate container %s: it uses the network namespace of another container", ctr)
+	}
+	plan := &migrationPlan{container: c, image: c.Image}
+	if img, err := src.ImageInspect(ctx, c.Config.Image); err == nil && img.ID == c.Image {
+		plan.image = c.Config.Image
+	} else if err != nil && !errdefs.IsNotFound(err) {
+		return nil, err
+	}
+	if _, err := dst.ContainerInspect(ctx, plan.name(), client.ContainerInspectOptions{}); err == nil {
+		return nil, fmt.Errorf("container %s already exists on the destination", plan.name())
+	} else if !errdefs.IsNotFound(err) {
+		return nil, err
+	}
+
+	// Only the volumes that are referenced by name are migrated; anonymous
+	// volumes are created from the image on the destination.
+	named := make(map[string]bool)
+	for _, b := range c.HostConfig.Binds {
+		name, _, _ := strings.Cut(b, ":")
+		named[name] = true
+	}
+	for _, m := range c.HostConfig.Mounts {
+		if m.Type == mount.TypeVolume && m.Source != "" {
+			named[m.Source] = true
+		}
+	}
+	for _, m := range c.Mounts {
+		switch {
+		case m.Type == mount.TypeBind:
+			plan.binds = append(plan.binds, m.Source)
+		case m.Type == mount.TypeVolume && named[m.Name]:
+			v, err := src.VolumeInspect(ctx, m.Name, client.VolumeInspectOptions{})
+			if err != nil {
+				return nil, err
+			}
+			exists, err := existsOn(dst.VolumeInspect(ctx, m.Name, client.VolumeInspectOptions{}))
+			if err != nil {
+				return nil, err
+			}
+			plan.volumes = append(plan.volumes, migrateVolume{volume: v.Volume, exists: exists})
+		}
+	}
+
+	if c.NetworkSettings != nil {
+		for name := range c.NetworkSettings.Networks {
+			if !container.NetworkMode(name).IsUserDefined() {
+				continue
+			}
+			n, err := src.NetworkInspect(ctx, name, client.NetworkInspectOptions{})
+			if err != nil {
+				return nil, err
+			}
+			exists, err := existsOn(dst.NetworkInspect(ctx, name, client.NetworkInspectOptions{}))
+			if err != nil {
+				return nil, err
+			}
+			plan.networks = append(plan.networks, migrateNetwork{network: n.Network, exists: exists})
+		}
+		sort.Slice(plan.networks, func(i, j int) bool {
+			return plan.networks[i].network.Name < plan.networks[j].network.Name
+		})
+	}
+	return plan, nil
+}
+
+// existsOn returns whether an object exists, based on the result of
+// inspecting it.
+func existsOn[T any](_ T, err error) (bool, error) {
+	switch {
+	case err == nil:
+		return true, nil
+	case errdefs.IsNotFound(err):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func printMigrationPlan(out io.Writer, plan *migrationPlan, opts *migrateOptions) {
+	c := plan.container
+	tw := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "TYPE\tNAME\tACTION")
+	_, _ = fmt.Fprintf(tw, "image\t%s\ttransfer\n", plan.image)
+	for _, n := range plan.networks {
+		action := "create"
+		if n.exists {
+			action = "use existing"
+		}
+		_, _ = fmt.Fprintf(tw, "network\t%s\t%s\n", n.network.Name, action)
+	}
+	if opts.stop && c.State != nil && c.State.Running {
+		_, _ = fmt.Fprintf(tw, "container\t%s\tstop source\n", plan.name())
+	}
+	for _, v := range plan.volumes {
+		action := "create and copy"
+		if v.exists {
+			action = "copy into existing"
+		}
+		_, _ = fmt.Fprintf(tw, "volume\t%s\t%s\n", v.volume.Name, action)
+	}
+	for _, b := range plan.binds {
+		_, _ = fmt.Fprintf(tw, "bind mount\t%s\tskip\n", b)
+	}
+	action := "create"
+	if c.State != nil && c.State.Running {
+		action = "create and start"
+	}
+	_, _ = fmt.Fprintf(tw, "container\t%s\t%s\n", plan.name(), action)
+	_ = tw.Flush()
+}
+
+// migrateCreateOptions returns the options to create the container on the
+// destination, based on the configuration of the container.
+func migrateCreateOptions(plan *migrationPlan) client.ContainerCreateOptions {
+	c := plan.container
+	config := *c.Config
+	config.Image = plan.image
+	if len(c.ID) >= 12 && config.Hostname == c.ID[:12] {
+		// Let the destination generate the hostname from the ID of the
+		// new container, unless it was set explicitly.
+		config.Hostname = ""
+	}
+	endpoints := make(map[string]*network.EndpointSettings)
+	if c.NetworkSettings != nil {
+		for name, ep := range c.NetworkSettings.Networks {
+			if ep == nil {
+				continue
+			}
+			endpoints[name] = &network.EndpointSettings{
+				IPAMConfig: ep.IPAMConfig,
+				Links:      ep.Links,
+				Aliases:    ep.Aliases,
+				DriverOpts: ep.DriverOpts,
+				GwPriority: ep.GwPriority,
+				MacAddress: ep.MacAddress,
+			}
+		}
+	}
+	return client.ContainerCreateOptions{
+		Name:             plan.name(),
+		Config:           &config,
+		HostConfig:       c.HostConfig,
+		NetworkingConfig: &network.NetworkingConfig{EndpointsConfig: endpoints},
+	}
+}
+
+func createNetwork(ctx context.Context, apiClient client.APIClient, n network.Inspect) error {
+	_, err := apiClient.NetworkCreate(ctx, n.Name, client.NetworkCreateOptions{
+		Driver:     n.Driver,
+		EnableIPv4: &n.EnableIPv4,
+		EnableIPv6: &n.EnableIPv6,
+		IPAM:       &n.IPAM,
+		Internal:   n.Internal,
+		Attachable: n.Attachable,
+		Options:    n.Options,
+		Labels:     n.Labels,
+	})
+	return err
+}
+
+// copyVolume copies the content of the volume from the src to the dst
+// daemon, using helper containers that have the volume mounted; see
+// [volumehelper.Create].
+func copyVolume(ctx context.Context, src, dst client.APIClient, name string) error {
+	srcHelper, removeSrcHelper, err := volumehelper.Create(ctx, src, name, true)
+	if err != nil {
+		return err
+	}
+	defer removeSrcHelper()
+	dstHelper, removeDstHelper, err := volumehelper.Create(ctx, dst, name, false)
+	if err != nil {
+		return err
+	}
+	defer removeDstHelper()
+
+	// The archive contains the content of the volume in a directory named
+	// after the mount path, so it's extracted into the volume at "/".
+	res, err := src.CopyFromContainer(ctx, srcHelper, client.CopyFromContainerOptions{SourcePath: volumehelper.MountPath})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Content.Close() }()
+	_, err = dst.CopyToContainer(ctx, dstHelper, client.CopyToContainerOptions{
+		DestinationPath: "/",
+		Content:         res.Content,
+		CopyUIDGID:      true,
+	})
+	return err
+}
@@ -0,0 +1,268 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/api/types/mount"
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/api/types/volume"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// migrateSource returns a fake client with a running container "web" that
+// has a named volume, an anonymous volume, a bind mount, and is connected to
+// the default bridge and a user-defined network.
+func migrateSource() *fakeClient {
+	return &fakeClient{
+		inspectFunc: func(string) (client.ContainerInspectResult, error) {
+			return client.ContainerInspectResult{Container: container.InspectResponse{
+				ID:    "0123456789abcdef",
+				Name:  "/web",
+				Image: "sha256:image",
+				State: &container.State{Running: true},
+				Config: &container.Config{
+					Hostname: "0123456789ab",
+					Image:    "nginx:latest",
+				},
+				HostConfig: &container.HostConfig{
+					Binds: []string{"data:/data", "/srv:/srv"},
+				},
+				Mounts: []container.MountPoint{
+					{Type: mount.TypeVolume, Name: "data", Destination: "/data"},
+					{Type: mount.TypeVolume, Name: "4f0a6c8e", Destination: "/cache"},
+					{Type: mount.TypeBind, Source: "/srv", Destination: "/srv"},
+				},
+				NetworkSettings: &container.NetworkSettings{
+					Networks: map[string]*network.EndpointSettings{
+						"bridge":  {},
+						"backend": {Aliases: []string{"web"}, NetworkID: "1234"},
+					},
+				},
+			}}, nil
+		},
+		imageInspectFunc: func(img string) (client.ImageInspectResult, error) {
+			if img == "nginx:latest" {
+				return client.ImageInspectResult{InspectResponse: image.InspectResponse{ID: "sha256:image"}}, nil
+			}
+			return client.ImageInspectResult{}, errdefs.ErrNotFound
+		},
+		volumeInspectFunc: func(volumeID string) (client.VolumeInspectResult, error) {
+			return client.VolumeInspectResult{Volume: volume.Volume{
+				Name:   volumeID,
+				Driver: "local",
+				Labels: map[string]string{"app": "web"},
+			}}, nil
+		},
+		networkInspectFunc: func(networkID string) (client.NetworkInspectResult, error) {
+			return client.NetworkInspectResult{Network: network.Inspect{
+				Network: network.Network{Name: networkID, Driver: "bridge", Internal: true},
+			}}, nil
+		},
+	}
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	cli := test.NewFakeCli(migrateSource())
+	dst := &fakeClient{
+		inspectFunc: func(string) (client.ContainerInspectResult, error) {
+			return client.ContainerInspectResult{}, errdefs.ErrNotFound
+		},
+		volumeInspectFunc: func(string) (client.VolumeInspectResult, error) {
+			return client.VolumeInspectResult{}, errdefs.ErrNotFound
+		},
+	}
+	err := runMigrate(context.Background(), cli, dst, &migrateOptions{container: "web", to: "remote", stop: true, dryRun: true})
+	assert.NilError(t, err)
+	expected := `TYPE         NAME           ACTION
+image        nginx:latest   transfer
+network      backend        use existing
+container    web            stop source
+volume       data           create and copy
+bind mount   /srv           skip
+container    web            create and start
+`
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), expected))
+}
+
+func TestMigrateUpdatedImage(t *testing.T) {
+	src := migrateSource()
+	src.imageInspectFunc = func(string) (client.ImageInspectResult, error) {
+		return client.ImageInspectResult{InspectResponse: image.InspectResponse{ID: "sha256:newer"}}, nil
+	}
+	cli := test.NewFakeCli(src)
+	dst := &fakeClient{
+		inspectFunc: func(string) (client.ContainerInspectResult, error) {
+			return client.ContainerInspectResult{}, errdefs.ErrNotFound
+		},
+	}
+	plan, err := planMigration(context.Background(), src, dst, "web")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(plan.image, "sha256:image"))
+	assert.Check(t, is.Equal(migrateCreateOptions(plan).Config.Image, "sha256:image"))
+
+	err = runMigrate(context.Background(), cli, dst, &migrateOptions{container: "web", to: "remote", dryRun: true})
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "image        sha256:image   transfer\n"))
+}
+
+func TestMigrate(t *testing.T) {
+	const helperImage = "sha256:7f4b1e2a9c3d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f"
+	var calls []string
+	src := migrateSource()
+	src.imageSaveFunc = func(images []string) (client.ImageSaveResult, error) {
+		calls = append(calls, "save "+strings.Join(images, ","))
+		return io.NopCloser(strings.NewReader("archive")), nil
+	}
+	src.containerStopFunc = func(_ context.Context, containerID string, _ client.ContainerStopOptions) (client.ContainerStopResult, error) {
+		calls = append(calls, "stop "+containerID)
+		return client.ContainerStopResult{}, nil
+	}
+	src.createContainerFunc = func(options client.ContainerCreateOptions) (client.ContainerCreateResult, error) {
+		m := options.HostConfig.Mounts[0]
+		calls = append(calls, fmt.Sprintf("create src helper %s %s:%s ro=%t", options.Config.Image, m.Source, m.Target, m.ReadOnly))
+		return client.ContainerCreateResult{ID: "src-helper"}, nil
+	}
+	src.containerCopyFromFunc = func(containerID, srcPath string) (client.CopyFromContainerResult, error) {
+		calls = append(calls, "copy from "+containerID+":"+srcPath)
+		return client.CopyFromContainerResult{Content: io.NopCloser(strings.NewReader("volume content"))}, nil
+	}
+	removeImage := func(img string) (client.ImageRemoveResult, error) {
+		calls = append(calls, "remove image "+img)
+		return client.ImageRemoveResult{}, nil
+	}
+	src.imageRemoveFunc = removeImage
+	src.containerRemoveFunc = func(_ context.Context, containerID string, _ client.ContainerRemoveOptions) (client.ContainerRemoveResult, error) {
+		calls = append(calls, "remove "+containerID)
+		return client.ContainerRemoveResult{}, nil
+	}
+
+	var created client.ContainerCreateOptions
+	dst := &fakeClient{
+		inspectFunc: func(string) (client.ContainerInspectResult, error) {
+			return client.ContainerInspectResult{}, errdefs.ErrNotFound
+		},
+		imageLoadFunc: func(input io.Reader) (client.ImageLoadResult, error) {
+			b, err := io.ReadAll(input)
+			assert.NilError(t, err)
+			calls = append(calls, "load "+string(b))
+			return io.NopCloser(strings.NewReader("")), nil
+		},
+		volumeInspectFunc: func(string) (client.VolumeInspectResult, error) {
+			return client.VolumeInspectResult{}, errdefs.ErrNotFound
+		},
+		volumeCreateFunc: func(options client.VolumeCreateOptions) (client.VolumeCreateResult, error) {
+			assert.Check(t, is.DeepEqual(options.Labels, map[string]string{"app": "web"}))
+			calls = append(calls, "create volume "+options.Name+" "+options.Driver)
+			return client.VolumeCreateResult{}, nil
+		},
+		networkInspectFunc: func(string) (client.NetworkInspectResult, error) {
+			return client.NetworkInspectResult{}, errdefs.ErrNotFound
+		},
+		networkCreateFunc: func(name string, options client.NetworkCreateOptions) (client.NetworkCreateResult, error) {
+			assert.Check(t, options.Internal)
+			calls = append(calls, "create network "+name+" "+options.Driver)
+			return client.NetworkCreateResult{}, nil
+		},
+		createContainerFunc: func(options client.ContainerCreateOptions) (client.ContainerCreateResult, error) {
+			if options.Name == "" {
+				m := options.HostConfig.Mounts[0]
+				calls = append(calls, fmt.Sprintf("create dst helper %s %s:%s ro=%t", options.Config.Image, m.Source, m.Target, m.ReadOnly))
+				return client.ContainerCreateResult{ID: "dst-helper"}, nil
+			}
+			created = options
+			calls = append(calls, "create container "+options.Name)
+			return client.ContainerCreateResult{ID: "new-id"}, nil
+		},
+		containerCopyToFunc: func(containerID string, options client.CopyToContainerOptions) (client.CopyToContainerResult, error) {
+			b, err := io.ReadAll(options.Content)
+			assert.NilError(t, err)
+			calls = append(calls, "copy to "+containerID+":"+options.DestinationPath+" "+string(b))
+			return client.CopyToContainerResult{}, nil
+		},
+		imageRemoveFunc: removeImage,
+		containerRemoveFunc: func(_ context.Context, containerID string, _ client.ContainerRemoveOptions) (client.ContainerRemoveResult, error) {
+			calls = append(calls, "remove "+containerID)
+			return client.ContainerRemoveResult{}, nil
+		},
+		containerStartFunc: func(containerID string, _ client.ContainerStartOptions) (client.ContainerStartResult, error) {
+			calls = append(calls, "start "+containerID)
+			return client.ContainerStartResult{}, nil
+		},
+	}
+
+	cli := test.NewFakeCli(src)
+	err := runMigrate(context.Background(), cli, dst, &migrateOptions{container: "web", to: "remote", stop: true})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(calls, []string{
+		"save nginx:latest",
+		"load archive",
+		"create network backend bridge",
+		"stop 0123456789abcdef",
+		"create volume data local",
+		"create src helper " + helperImage + " data:/volume ro=true",
+		"create dst helper " + helperImage + " data:/volume ro=false",
+		"copy from src-helper:/volume",
+		"copy to dst-helper:/ volume content",
+		"remove dst-helper",
+		"remove image " + helperImage,
+		"remove src-helper",
+		"remove image " + helperImage,
+		"create container web",
+		"start new-id",
+	}))
+	assert.Check(t, is.Equal(created.Config.Image, "nginx:latest"))
+	assert.Check(t, is.Equal(created.Config.Hostname, ""))
+	assert.Check(t, is.DeepEqual(created.NetworkingConfig.EndpointsConfig["backend"].Aliases, []string{"web"}))
+	assert.Check(t, is.Equal(created.NetworkingConfig.EndpointsConfig["backend"].NetworkID, ""))
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), "WARNING: the content of bind mount /srv is not migrated"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "Created network backend\nStopped container web\nCopied volume data\nweb\n"))
+}
+
+func TestMigrateErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "wrong-args",
+			args:          []string{"--to", "remote"},
+			expectedError: "requires 1 argument",
+		},
+		{
+			name:          "missing-to",
+			args:          []string{"web"},
+			expectedError: `required flag(s) "to" not set`,
+		},
+		{
+			name:          "current-context",
+			args:          []string{"--to", "default", "web"},
+			expectedError: `cannot migrate container to context "default": context is the current context`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newMigrateCommand(test.NewFakeCli(&fakeClient{}))
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
+
+func TestMigrateExistingContainer(t *testing.T) {
+	cli := test.NewFakeCli(migrateSource())
+	err := runMigrate(context.Background(), cli, &fakeClient{}, &migrateOptions{container: "web", to: "remote"})
+	assert.Check(t, is.Error(err, "container web already exists on the destination"))
+}
@@ -21,6 +21,7 @@
 	"github.com/docker/cli/internal/lazyregexp"
 	"github.com/docker/cli/internal/volumespec"
 	"github.com/docker/cli/opts"
+	"github.com/docker/cli/pkg/kvfile"
 	"github.com/docker/go-connections/nat"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/api/types/mount"
@@ -74,12 +75,14 @@ type containerOptions struct {
 	extraHosts          opts.ListOpts
 	volumesFrom         opts.ListOpts
 	envFile             opts.ListOpts
+	envFileFormat       string
 	capAdd              opts.ListOpts
 	capDrop             opts.ListOpts
 	groupAdd            opts.ListOpts
 	securityOpt         opts.ListOpts
 	storageOpt          opts.ListOpts
 	labelsFile          opts.ListOpts
+	labelsFileFormat    string
 	loggingOpts         opts.ListOpts
 	privileged          bool
 	pidMode             string
@@ -191,6 +194,7 @@ func addFlags(flags *pflag.FlagSet) *containerOptions {
 	flags.SetAnnotation("gpus", "version", []string{"1.40"})
 	flags.VarP(&copts.env, "env", "e", "Set environment variables")
 	flags.Var(&copts.envFile, "env-file", "Read in a file of environment variables")
+	flags.StringVar(&copts.envFileFormat, "env-file-format", string(kvfile.FormatPlain), `Format of env-files ("plain", "dotenv")`)
 	flags.StringVar(&copts.entrypoint, "entrypoint", "", "Overwrite the default ENTRYPOINT of the image")
 	flags.Var(&copts.groupAdd, "group-add", "Add additional groups to join")
 	flags.StringVarP(&copts.hostname, "hostname", "h", "", "Container host name")
@@ -198,6 +202,7 @@ func addFlags(flags *pflag.FlagSet) *containerOptions {
 	flags.BoolVarP(&copts.stdin, "interactive", "i", false, "Keep STDIN open even if not attached")
 	flags.VarP(&copts.labels, "label", "l", "Set meta data on a container")
 	flags.Var(&copts.labelsFile, "label-file", "Read in a line delimited file of labels")
+	flags.StringVar(&copts.labelsFileFormat, "label-file-format", string(kvfile.FormatPlain), `Format of label-files ("plain", "dotenv")`)
 	flags.BoolVar(&copts.readonlyRootfs, "read-only", false, "Mount the container's root filesystem as read only")
 	flags.StringVar(&copts.restartPolicy, "restart", string(container.RestartPolicyDisabled), "Restart policy to apply when a container exits")
 	flags.StringVar(&copts.stopSignal, "stop-signal", "", "Signal to stop the container")
@@ -504,13 +509,13 @@ func parse(flags *pflag.FlagSet, copts *containerOptions, serverOS string) (*con
 	}
 
 	// collect all the environment variables for the container
-	envVariables, err := opts.ReadKVEnvStrings(copts.envFile.GetSlice(), copts.env.GetSlice())
+	envVariables, err := opts.ReadKVEnvStringsWithFormat(copts.envFile.GetSlice(), copts.env.GetSlice(), kvfile.Format(copts.envFileFormat))
 	if err != nil {
 		return nil, fmt.Errorf("--env-file: %w", err)
 	}
 
 	// collect all the labels for the container
-	labels, err := opts.ReadKVStrings(copts.labelsFile.GetSlice(), copts.labels.GetSlice())
+	labels, err := opts.ReadKVStringsWithFormat(copts.labelsFile.GetSlice(), copts.labels.GetSlice(), kvfile.Format(copts.labelsFileFormat))
 	if err != nil {
 		return nil, fmt.Errorf("--label-file: %w", err)
 	}
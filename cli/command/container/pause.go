@@ -5,7 +5,6 @@
 	"errors"
 	"fmt"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/moby/moby/api/types/container"
@@ -14,19 +13,25 @@
 )
 
 type pauseOptions struct {
+	bulk bulkOptions
+
 	containers []string
 }
 
 // newPauseCommand creates a new cobra.Command for "docker container pause"
 func newPauseCommand(dockerCLI command.Cli) *cobra.Command {
-	var opts pauseOptions
+	opts := pauseOptions{bulk: newBulkOptions()}
 
-	return &cobra.Command{
-		Use:   "pause CONTAINER [CONTAINER...]",
+	cmd := &cobra.Command{
+		Use:   "pause [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Pause all processes within one or more containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  opts.bulk.args,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.containers = args
+			var err error
+			opts.containers, err = opts.bulk.containers(cmd.Context(), dockerCLI, args, "pause")
+			if err != nil {
+				return err
+			}
 			return runPause(cmd.Context(), dockerCLI, &opts)
 		},
 		Annotations: map[string]string{
@@ -37,11 +42,14 @@ func newPauseCommand(dockerCLI command.Cli) *cobra.Command {
 		}),
 		DisableFlagsInUseLine: true,
 	}
+
+	opts.bulk.installFlags(cmd.Flags(), defaultParallel)
+	return cmd
 }
 
 func runPause(ctx context.Context, dockerCLI command.Cli, opts *pauseOptions) error {
 	apiClient := dockerCLI.Client()
-	errChan := parallelOperation(ctx, opts.containers, func(ctx context.Context, container string) error {
+	errChan := parallelOperation(ctx, opts.containers, opts.bulk.parallel, func(ctx context.Context, container string) error {
 		_, err := apiClient.ContainerPause(ctx, container, client.ContainerPauseOptions{})
 		return err
 	})
@@ -0,0 +1,166 @@
+package container
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/google/shlex"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// presetOptions are the options for applying a preset from the "runPresets"
+// in the CLI's configuration file to "docker run" and "docker create".
+type presetOptions struct {
+	name  string
+	print bool
+
+	// args are the flags that were set on the command line, in the order
+	// in which they were set.
+	args []string
+}
+
+// addPresetFlags adds the "--preset" and "--print" flags to the command.
+// It must be called after all other flags are added, as it wraps the
+// values of all flags to record the flags that are set on the command line.
+func addPresetFlags(cmd *cobra.Command, dockerCLI command.Cli, opts *presetOptions) {
+	flags := cmd.Flags()
+	flags.VisitAll(func(f *pflag.Flag) {
+		f.Value = &recordedValue{Value: f.Value, flag: f, args: &opts.args}
+	})
+
+	flags.StringVar(&opts.name, "preset", "", "Apply the flags of a preset in the CLI configuration file")
+	flags.BoolVar(&opts.print, "print", false, "Print the effective command, including the flags of the preset, and exit")
+
+	_ = cmd.RegisterFlagCompletionFunc("preset", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		names := make([]string, 0, len(dockerCLI.ConfigFile().RunPresets))
+		for name := range dockerCLI.ConfigFile().RunPresets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// presetArgs returns a function that validates the arguments of the command
+// with validateArgs, unless the "--print" flag is set. With "--print", the
+// effective command is printed without running it, so that the preset can be
+// printed without arguments.
+func presetArgs(opts *presetOptions, validateArgs cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if opts.print {
+			return nil
+		}
+		return validateArgs(cmd, args)
+	}
+}
+
+// recordedValue records each value that is set for a flag.
+type recordedValue struct {
+	pflag.Value
+	flag *pflag.Flag
+	args *[]string
+}
+
+func (v *recordedValue) Set(value string) error {
+	if v.flag.NoOptDefVal != "" && value == v.flag.NoOptDefVal {
+		*v.args = append(*v.args, "--"+v.flag.Name)
+	} else {
+		*v.args = append(*v.args, "--"+v.flag.Name+"="+value)
+	}
+	return v.Value.Set(value)
+}
+
+// runWithPreset runs the command with the flags of the preset, followed by
+// the flags that were set on the command line and the command's arguments,
+// using a new instance of the command created by newCmd. Flags set on the
+// command line are parsed after the preset's flags, so that they override
+// the preset's values, and add to the values of flags that can be repeated,
+// such as "--env" and "--volume".
+func runWithPreset(cmd *cobra.Command, dockerCLI command.Cli, opts *presetOptions, newCmd func(command.Cli) *cobra.Command, args []string) error {
+	var effective []string
+	if opts.name != "" {
+		presetArgs, err := loadPreset(dockerCLI, opts.name, newCmd)
+		if err != nil {
+			return err
+		}
+		effective = append(effective, presetArgs...)
+	}
+	effective = append(effective, opts.args...)
+	effective = append(effective, args...)
+
+	if opts.print {
+		quoted := make([]string, 0, len(effective))
+		for _, a := range effective {
+			quoted = append(quoted, shellQuote(a))
+		}
+		_, _ = fmt.Fprintln(dockerCLI.Out(), cmd.CommandPath(), strings.Join(quoted, " "))
+		return nil
+	}
+
+	c := newCmd(dockerCLI)
+	c.SetContext(cmd.Context())
+	c.SetIn(cmd.InOrStdin())
+	c.SetOut(cmd.OutOrStdout())
+	c.SetErr(cmd.ErrOrStderr())
+	if err := c.ParseFlags(effective); err != nil {
+		return err
+	}
+	if err := c.ValidateArgs(c.Flags().Args()); err != nil {
+		return err
+	}
+	return c.RunE(c, c.Flags().Args())
+}
+
+// loadPreset returns the flags of the preset with the given name. Environment
+// variables in the preset are expanded; use "$$" for a literal "$".
+func loadPreset(dockerCLI command.Cli, name string, newCmd func(command.Cli) *cobra.Command) ([]string, error) {
+	preset, ok := dockerCLI.ConfigFile().RunPresets[name]
+	if !ok {
+		return nil, fmt.Errorf("preset %q not found: presets can be defined in the %q option of the CLI configuration file", name, "runPresets")
+	}
+	presetArgs, err := shlex.Split(preset)
+	if err != nil {
+		return nil, fmt.Errorf("preset %q: invalid flags: %w", name, err)
+	}
+	for i, a := range presetArgs {
+		presetArgs[i] = os.Expand(a, func(v string) string {
+			if v == "$" {
+				return "$"
+			}
+			return os.Getenv(v)
+		})
+	}
+
+	// Validate the preset's flags, and that it does not contain arguments.
+	c := newCmd(dockerCLI)
+	c.SetOut(dockerCLI.Err())
+	c.SetErr(dockerCLI.Err())
+	if err := c.ParseFlags(presetArgs); err != nil {
+		return nil, fmt.Errorf("preset %q: %w", name, err)
+	}
+	if c.Flags().NArg() > 0 {
+		return nil, fmt.Errorf("preset %q: presets can only contain flags, found argument %q", name, c.Flags().Arg(0))
+	}
+	for _, f := range []string{"preset", "print"} {
+		if c.Flags().Changed(f) {
+			return nil, fmt.Errorf("preset %q: the --%s flag cannot be used in a preset", name, f)
+		}
+	}
+	return presetArgs, nil
+}
+
+// shellSafe matches strings that do not need to be quoted for use in a shell.
+var shellSafe = regexp.MustCompile(`^[a-zA-Z0-9_@%+=:,./-]+$`)
+
+// shellQuote quotes s for use in a POSIX shell, if needed.
+func shellQuote(s string) string {
+	if shellSafe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
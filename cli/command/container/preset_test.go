@@ -0,0 +1,121 @@
+package container
+
+import (
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+var testPresets = map[string]string{
+	"dev":     `--rm -it --network dev -e FOO=preset -e BAR=preset -v "$PWD:/src" -w /src --label 'com.example.price=$$5'`,
+	"invalid": `--no-such-flag`,
+	"image":   `--rm alpine`,
+	"nested":  `--preset dev`,
+}
+
+func TestCreatePreset(t *testing.T) {
+	t.Setenv("PWD", "/home/me/project")
+
+	var created client.ContainerCreateOptions
+	cli := test.NewFakeCli(&fakeClient{
+		createContainerFunc: func(options client.ContainerCreateOptions) (client.ContainerCreateResult, error) {
+			created = options
+			return client.ContainerCreateResult{ID: "container-id"}, nil
+		},
+	})
+	cli.SetConfigFile(&configfile.ConfigFile{RunPresets: testPresets})
+	cmd := newCreateCommand(cli)
+	cmd.SetArgs([]string{"--preset", "dev", "-e", "FOO=cli", "-w", "/app", "--name", "web", "alpine", "sh"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.Equal(created.Name, "web"))
+	assert.Check(t, is.Equal(created.Config.Image, "alpine"))
+	assert.Check(t, is.DeepEqual([]string(created.Config.Cmd), []string{"sh"}))
+	assert.Check(t, created.Config.Tty)
+	assert.Check(t, created.Config.OpenStdin)
+	assert.Check(t, is.Equal(created.Config.WorkingDir, "/app"))
+	assert.Check(t, is.Equal(created.Config.Labels["com.example.price"], "$5"))
+	// Values set on the command line are added to the preset's values, and
+	// take precedence over them.
+	sort.Strings(created.Config.Env)
+	assert.Check(t, is.DeepEqual(created.Config.Env, []string{"BAR=preset", "FOO=cli"}))
+	assert.Check(t, created.HostConfig.AutoRemove)
+	assert.Check(t, is.Equal(string(created.HostConfig.NetworkMode), "dev"))
+	assert.Check(t, is.DeepEqual(created.HostConfig.Binds, []string{"/home/me/project:/src"}))
+}
+
+func TestRunPresetPrint(t *testing.T) {
+	t.Setenv("PWD", "/home/me/my project")
+
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetConfigFile(&configfile.ConfigFile{RunPresets: testPresets})
+	cmd := newRunCommand(cli)
+	cmd.SetArgs([]string{"--preset", "dev", "--print", "-e", "FOO=cli", "--detach", "alpine", "sh", "-c", "echo hello"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	const expected = `run --rm -it --network dev -e FOO=preset -e BAR=preset -v '/home/me/my project:/src' -w /src --label 'com.example.price=$5' --env=FOO=cli --detach alpine sh -c 'echo hello'` + "\n"
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), expected))
+}
+
+func TestRunPresetPrintWithoutImage(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetConfigFile(&configfile.ConfigFile{RunPresets: map[string]string{"dev": "--rm -it"}})
+	cmd := newRunCommand(cli)
+	cmd.SetArgs([]string{"--preset", "dev", "--print"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "run --rm -it\n"))
+
+	// Running the preset without an image fails.
+	cmd = newRunCommand(cli)
+	cmd.SetArgs([]string{"--preset", "dev"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "requires at least 1 argument"))
+}
+
+func TestRunPresetErrors(t *testing.T) {
+	tests := []struct {
+		preset      string
+		expectedErr string
+	}{
+		{
+			preset:      "nosuchpreset",
+			expectedErr: `preset "nosuchpreset" not found: presets can be defined in the "runPresets" option of the CLI configuration file`,
+		},
+		{
+			preset:      "invalid",
+			expectedErr: `preset "invalid": unknown flag: --no-such-flag`,
+		},
+		{
+			preset:      "image",
+			expectedErr: `preset "image": presets can only contain flags, found argument "alpine"`,
+		},
+		{
+			preset:      "nested",
+			expectedErr: `preset "nested": the --preset flag cannot be used in a preset`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.preset, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{})
+			cli.SetConfigFile(&configfile.ConfigFile{RunPresets: testPresets})
+			cmd := newRunCommand(cli)
+			cmd.SetArgs([]string{"--preset", tc.preset, "alpine"})
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.Check(t, is.Error(cmd.Execute(), tc.expectedErr))
+		})
+	}
+}
@@ -1,11 +1,16 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
 package container
 
 import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/moby/moby/client"
@@ -16,23 +21,46 @@ type restartOptions struct {
 	signal         string
 	timeout        int
 	timeoutChanged bool
+	bulk           bulkOptions
+
+	rolling        bool
+	maxUnavailable int
+	waitHealthy    bool
+	waitTimeout    time.Duration
+	gracePeriod    time.Duration
 
 	containers []string
 }
 
 // newRestartCommand creates a new cobra.Command for "docker container restart".
 func newRestartCommand(dockerCLI command.Cli) *cobra.Command {
-	var opts restartOptions
+	opts := restartOptions{bulk: newBulkOptions()}
 
 	cmd := &cobra.Command{
 		Use:   "restart [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Restart one or more containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  opts.bulk.args,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if cmd.Flags().Changed("time") && cmd.Flags().Changed("timeout") {
 				return errors.New("conflicting options: cannot specify both --timeout and --time")
 			}
-			opts.containers = args
+			if !opts.rolling {
+				for _, flag := range []string{"max-unavailable", "grace-period"} {
+					if cmd.Flags().Changed(flag) {
+						return fmt.Errorf("--%s can only be used with --rolling", flag)
+					}
+				}
+			} else if cmd.Flags().Changed("parallel") {
+				return errors.New("conflicting options: --parallel cannot be used with --rolling; use --max-unavailable instead")
+			}
+			if opts.maxUnavailable < 1 {
+				return fmt.Errorf("invalid value for --max-unavailable: %d: must be greater than 0", opts.maxUnavailable)
+			}
+			var err error
+			opts.containers, err = opts.bulk.containers(cmd.Context(), dockerCLI, args, "restart")
+			if err != nil {
+				return err
+			}
 			opts.timeoutChanged = cmd.Flags().Changed("timeout") || cmd.Flags().Changed("time")
 			return runRestart(cmd.Context(), dockerCLI, &opts)
 		},
@@ -51,6 +79,15 @@ func newRestartCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.IntVar(&opts.timeout, "time", 0, "Seconds to wait before killing the container (deprecated: use --timeout)")
 	_ = flags.MarkDeprecated("time", "use --timeout instead")
 
+	// Containers are restarted sequentially by default.
+	opts.bulk.installFlags(flags, 1)
+
+	flags.BoolVar(&opts.rolling, "rolling", false, "Restart containers in batches, waiting for each batch to be running or healthy")
+	flags.IntVar(&opts.maxUnavailable, "max-unavailable", 1, "Number of containers to restart at the same time with --rolling")
+	flags.DurationVar(&opts.gracePeriod, "grace-period", 5*time.Second, "Duration containers without a health check must be running before restarting the next batch with --rolling")
+	flags.BoolVar(&opts.waitHealthy, "wait-healthy", false, "Wait for the containers to be healthy")
+	flags.DurationVar(&opts.waitTimeout, "wait-timeout", 0, "Maximum time to wait for containers to be healthy, or for each batch with --rolling (0 to wait without timeout)")
+
 	_ = cmd.RegisterFlagCompletionFunc("signal", completeSignals)
 
 	return cmd
@@ -62,19 +99,148 @@ func runRestart(ctx context.Context, dockerCLI command.Cli, opts *restartOptions
 		timeout = &opts.timeout
 	}
 
+	if opts.rolling {
+		return runRollingRestart(ctx, dockerCLI, opts, timeout)
+	}
+
+	restarted, err := restartContainers(ctx, dockerCLI, opts.containers, opts.bulk.parallel, opts.signal, timeout)
+	if opts.waitHealthy && len(restarted) > 0 {
+		if waitErr := waitHealthy(ctx, dockerCLI.Client(), restarted, opts.waitTimeout); waitErr != nil {
+			return errors.Join(err, waitErr)
+		}
+	}
+	return err
+}
+
+// restartContainers restarts the containers, and prints the name of each
+// container that is restarted. It returns the containers that were restarted,
+// and an error for each container that failed to restart.
+func restartContainers(ctx context.Context, dockerCLI command.Cli, containers []string, parallel int, signal string, timeout *int) ([]string, error) {
 	apiClient := dockerCLI.Client()
-	var errs []error
-	// TODO(thaJeztah): consider using parallelOperation for restart, similar to "stop" and "remove"
-	for _, name := range opts.containers {
+	errChan := parallelOperation(ctx, containers, parallel, func(ctx context.Context, name string) error {
 		_, err := apiClient.ContainerRestart(ctx, name, client.ContainerRestartOptions{
-			Signal:  opts.signal,
+			Signal:  signal,
 			Timeout: timeout,
 		})
-		if err != nil {
+		return err
+	})
+
+	var restarted []string
+	var errs []error
+	for _, name := range containers {
+		if err := <-errChan; err != nil {
 			errs = append(errs, err)
 			continue
 		}
 		_, _ = fmt.Fprintln(dockerCLI.Out(), name)
+		restarted = append(restarted, name)
+	}
+	return restarted, errors.Join(errs...)
+}
+
+// runRollingRestart restarts the containers in batches of at most
+// opts.maxUnavailable containers. After restarting a batch, it waits for
+// the containers in the batch to be available before restarting the next
+// batch; containers are available if they are healthy (with --wait-healthy
+// for containers that have a health check), or if they have been running
+// for the grace period. The rolling restart is aborted if a batch fails
+// to restart or to become available.
+func runRollingRestart(ctx context.Context, dockerCLI command.Cli, opts *restartOptions, timeout *int) error {
+	var batches [][]string
+	for i := 0; i < len(opts.containers); i += opts.maxUnavailable {
+		end := i + opts.maxUnavailable
+		if end > len(opts.containers) {
+			end = len(opts.containers)
+		}
+		batches = append(batches, opts.containers[i:end])
+	}
+
+	var done []string
+	for i, batch := range batches {
+		restarted, err := restartContainers(ctx, dockerCLI, batch, len(batch), opts.signal, timeout)
+		if err == nil {
+			restarted, err = waitForBatch(ctx, dockerCLI.Client(), batch, opts)
+		}
+		if err != nil {
+			return rollingRestartError(err, i, batches, append(done, restarted...))
+		}
+		done = append(done, batch...)
+	}
+	return nil
+}
+
+// waitForBatch waits for the containers in a batch to be available, and
+// returns the containers that are available.
+func waitForBatch(ctx context.Context, apiClient client.APIClient, batch []string, opts *restartOptions) ([]string, error) {
+	if opts.waitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.waitTimeout)
+		defer cancel()
+	}
+
+	var available, healthchecked, others []string
+	onMet := func(name string) {
+		available = append(available, name)
+	}
+	for _, name := range batch {
+		if opts.waitHealthy {
+			res, err := apiClient.ContainerInspect(ctx, name, client.ContainerInspectOptions{})
+			if err != nil {
+				return nil, err
+			}
+			if hasHealthcheck(res.Container.Config) {
+				healthchecked = append(healthchecked, name)
+				continue
+			}
+		}
+		others = append(others, name)
+	}
+
+	if len(healthchecked) > 0 {
+		if err := waitForCondition(ctx, apiClient, healthchecked, waitConditionOptions{
+			condition: waitCondition{kind: waitConditionHealthy},
+			onMet:     onMet,
+		}); err != nil {
+			return available, err
+		}
+	}
+	if len(others) > 0 {
+		cond := waitCondition{kind: waitConditionRunning}
+		if opts.gracePeriod > 0 {
+			cond = waitCondition{kind: waitConditionStartedFor, duration: opts.gracePeriod}
+		}
+		if err := waitForCondition(ctx, apiClient, others, waitConditionOptions{condition: cond, onMet: onMet}); err != nil {
+			return available, err
+		}
+	}
+	return available, nil
+}
+
+// rollingRestartError wraps the error of the failed batch, reporting which
+// containers were restarted, which containers of the batch failed to restart
+// or to become available, and which containers were not restarted.
+func rollingRestartError(err error, failed int, batches [][]string, done []string) error {
+	var failedContainers, notRestarted []string
+	for _, name := range batches[failed] {
+		if !slices.Contains(done, name) {
+			failedContainers = append(failedContainers, name)
+		}
+	}
+	for _, batch := range batches[failed+1:] {
+		notRestarted = append(notRestarted, batch...)
+	}
+	var sb strings.Builder
+	for _, line := range []struct {
+		label      string
+		containers []string
+	}{
+		{label: "restarted", containers: done},
+		{label: "failed", containers: failedContainers},
+		{label: "not restarted", containers: notRestarted},
+	} {
+		if len(line.containers) > 0 {
+			_, _ = fmt.Fprintf(&sb, "\n%s: %s", line.label, strings.Join(line.containers, ", "))
+		}
 	}
-	return errors.Join(errs...)
+	return fmt.Errorf("rolling restart aborted: batch %d of %d failed: %w%s", failed+1, len(batches), err, sb.String())
 }
@@ -8,7 +8,9 @@
 	"sync"
 	"testing"
 
+	"github.com/containerd/errdefs"
 	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
@@ -68,7 +70,8 @@ func TestRestart(t *testing.T) {
 						return client.ContainerRestartResult{}, notFound(errors.New("Error: no such container: " + containerID))
 					}
 
-					// TODO(thaJeztah): consider using parallelOperation for restart, similar to "stop" and "remove"
+					// containerRestartFunc may be called in parallel when using
+					// "--parallel", so append must be synchronized.
 					mutex.Lock()
 					restarted = append(restarted, containerID)
 					mutex.Unlock()
@@ -92,3 +95,125 @@ func TestRestart(t *testing.T) {
 		})
 	}
 }
+
+func TestRestartRolling(t *testing.T) {
+	apiClient, setStatus := fakeHealthClient(map[string]container.HealthStatus{
+		"one":   container.Healthy,
+		"two":   container.Healthy,
+		"three": container.Healthy,
+	})
+	var restarted []string
+	mutex := new(sync.Mutex)
+	apiClient.containerRestartFunc = func(_ context.Context, containerID string, _ client.ContainerRestartOptions) (client.ContainerRestartResult, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if containerID == "three" {
+			// "one" and "two" must be restarted before "three" is restarted.
+			sort.Strings(restarted)
+			assert.Check(t, is.DeepEqual(restarted, []string{"one", "two"}))
+		} else {
+			setStatus(containerID, container.Starting)
+			go setStatus(containerID, container.Healthy)
+		}
+		restarted = append(restarted, containerID)
+		return client.ContainerRestartResult{}, nil
+	}
+
+	cli := test.NewFakeCli(apiClient)
+	cmd := newRestartCommand(cli)
+	cmd.SetArgs([]string{"--rolling", "--max-unavailable", "2", "--wait-healthy", "one", "two", "three"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Len(restarted, 3))
+}
+
+func TestRestartRollingAborted(t *testing.T) {
+	apiClient, _ := fakeHealthClient(map[string]container.HealthStatus{
+		"one":   container.Healthy,
+		"two":   container.Unhealthy,
+		"three": container.Healthy,
+	})
+	var restarted []string
+	apiClient.containerRestartFunc = func(_ context.Context, containerID string, _ client.ContainerRestartOptions) (client.ContainerRestartResult, error) {
+		restarted = append(restarted, containerID)
+		return client.ContainerRestartResult{}, nil
+	}
+
+	cli := test.NewFakeCli(apiClient)
+	cmd := newRestartCommand(cli)
+	cmd.SetArgs([]string{"--rolling", "--wait-healthy", "--wait-timeout", "10ms", "one", "two", "three"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	const expected = `rolling restart aborted: batch 2 of 3 failed: timeout waiting for condition 'healthy': two
+restarted: one
+failed: two
+not restarted: three`
+	assert.Check(t, is.Error(cmd.Execute(), expected))
+	assert.Check(t, is.DeepEqual(restarted, []string{"one", "two"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "one\ntwo\n"))
+}
+
+func TestRestartRollingRestartFailed(t *testing.T) {
+	var restarted []string
+	mutex := new(sync.Mutex)
+	cli := test.NewFakeCli(&fakeClient{
+		containerRestartFunc: func(_ context.Context, containerID string, _ client.ContainerRestartOptions) (client.ContainerRestartResult, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			restarted = append(restarted, containerID)
+			if containerID == "two" {
+				return client.ContainerRestartResult{}, errdefs.ErrNotFound
+			}
+			return client.ContainerRestartResult{}, nil
+		},
+	})
+	cmd := newRestartCommand(cli)
+	cmd.SetArgs([]string{"--rolling", "--max-unavailable", "2", "one", "two", "three"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	const expected = `rolling restart aborted: batch 1 of 2 failed: not found
+restarted: one
+failed: two
+not restarted: three`
+	err := cmd.Execute()
+	assert.Check(t, is.Error(err, expected))
+	assert.Check(t, is.ErrorIs(err, errdefs.ErrNotFound))
+	sort.Strings(restarted)
+	assert.Check(t, is.DeepEqual(restarted, []string{"one", "two"}))
+}
+
+func TestRestartRollingErrors(t *testing.T) {
+	tests := []struct {
+		args        []string
+		expectedErr string
+	}{
+		{
+			args:        []string{"--max-unavailable", "2", "one"},
+			expectedErr: "--max-unavailable can only be used with --rolling",
+		},
+		{
+			args:        []string{"--grace-period", "10s", "one"},
+			expectedErr: "--grace-period can only be used with --rolling",
+		},
+		{
+			args:        []string{"--rolling", "--parallel", "2", "one"},
+			expectedErr: "conflicting options: --parallel cannot be used with --rolling; use --max-unavailable instead",
+		},
+		{
+			args:        []string{"--rolling", "--max-unavailable", "0", "one"},
+			expectedErr: "invalid value for --max-unavailable: 0: must be greater than 0",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expectedErr, func(t *testing.T) {
+			cmd := newRestartCommand(test.NewFakeCli(&fakeClient{}))
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.Check(t, is.Error(cmd.Execute(), tc.expectedErr))
+		})
+	}
+}
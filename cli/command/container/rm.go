@@ -7,7 +7,6 @@
 	"strings"
 
 	"github.com/containerd/errdefs"
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/moby/moby/api/types/container"
@@ -19,13 +18,14 @@ type rmOptions struct {
 	rmVolumes bool
 	rmLink    bool
 	force     bool
+	bulk      bulkOptions
 
 	containers []string
 }
 
 // newRmCommand creates a new cobra.Command for "docker container rm".
 func newRmCommand(dockerCLI command.Cli) *cobra.Command {
-	var opts rmOptions
+	opts := rmOptions{bulk: newBulkOptions()}
 
 	completeLinkNames := completeLinks(dockerCLI)
 	completeNames := completion.ContainerNames(dockerCLI, true, func(ctr container.Summary) bool {
@@ -35,9 +35,13 @@ func newRmCommand(dockerCLI command.Cli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "rm [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Remove one or more containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  opts.bulk.args,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.containers = args
+			var err error
+			opts.containers, err = opts.bulk.containers(cmd.Context(), dockerCLI, args, "remove")
+			if err != nil {
+				return err
+			}
 			return runRm(cmd.Context(), dockerCLI, &opts)
 		},
 		Annotations: map[string]string{
@@ -57,6 +61,7 @@ func newRmCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVarP(&opts.rmVolumes, "volumes", "v", false, "Remove anonymous volumes associated with the container")
 	flags.BoolVarP(&opts.rmLink, "link", "l", false, "Remove the specified link")
 	flags.BoolVarP(&opts.force, "force", "f", false, "Force the removal of a running container (uses SIGKILL)")
+	opts.bulk.installFlags(flags, defaultParallel)
 	return cmd
 }
 
@@ -71,7 +76,7 @@ func newRemoveCommand(dockerCli command.Cli) *cobra.Command {
 
 func runRm(ctx context.Context, dockerCLI command.Cli, opts *rmOptions) error {
 	apiClient := dockerCLI.Client()
-	errChan := parallelOperation(ctx, opts.containers, func(ctx context.Context, ctrID string) error {
+	errChan := parallelOperation(ctx, opts.containers, opts.bulk.parallel, func(ctx context.Context, ctrID string) error {
 		ctrID = strings.Trim(ctrID, "/")
 		if ctrID == "" {
 			return errors.New("container name cannot be empty")
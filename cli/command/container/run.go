@@ -8,6 +8,7 @@
 	"os"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -24,21 +25,27 @@
 
 type runOptions struct {
 	createOptions
-	detach     bool
-	sigProxy   bool
-	detachKeys string
+	detach      bool
+	sigProxy    bool
+	detachKeys  string
+	waitHealthy bool
+	waitTimeout time.Duration
 }
 
 // newRunCommand create a new "docker run" command.
 func newRunCommand(dockerCLI command.Cli) *cobra.Command {
 	var options runOptions
 	var copts *containerOptions
+	var presetOpts presetOptions
 
 	cmd := &cobra.Command{
 		Use:   "run [OPTIONS] IMAGE [COMMAND] [ARG...]",
 		Short: "Create and run a new container from an image",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  presetArgs(&presetOpts, cli.RequiresMinArgs(1)),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if presetOpts.name != "" || presetOpts.print {
+				return runWithPreset(cmd, dockerCLI, &presetOpts, newRunCommand, args)
+			}
 			copts.Image = args[0]
 			if len(args) > 1 {
 				copts.Args = args[1:]
@@ -61,6 +68,8 @@ func newRunCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVar(&options.sigProxy, "sig-proxy", true, "Proxy received signals to the process")
 	flags.StringVar(&options.name, "name", "", "Assign a name to the container")
 	flags.StringVar(&options.detachKeys, "detach-keys", "", "Override the key sequence for detaching a container")
+	flags.BoolVar(&options.waitHealthy, "wait-healthy", false, "Wait for the container to be healthy (requires --detach)")
+	flags.DurationVar(&options.waitTimeout, "wait-timeout", 0, "Maximum time to wait for the container to be healthy (0 to wait without timeout)")
 	flags.StringVar(&options.pull, "pull", PullImageMissing, `Pull image before running ("`+PullImageAlways+`", "`+PullImageMissing+`", "`+PullImageNever+`")`)
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Suppress the pull output")
 	flags.BoolVarP(&options.createOptions.useAPISocket, "use-api-socket", "", false, "Bind mount Docker API socket and required auth")
@@ -80,6 +89,7 @@ func newRunCommand(dockerCLI command.Cli) *cobra.Command {
 
 	_ = cmd.RegisterFlagCompletionFunc("detach-keys", completeDetachKeys)
 	addCompletions(cmd, dockerCLI)
+	addPresetFlags(cmd, dockerCLI, &presetOpts)
 
 	return cmd
 }
@@ -126,6 +136,9 @@ func runContainer(ctx context.Context, dockerCli command.Cli, runOpts *runOption
 	config.ArgsEscaped = false
 
 	if !runOpts.detach {
+		if runOpts.waitHealthy {
+			return errors.New("conflicting options: --wait-healthy requires --detach")
+		}
 		if err := dockerCli.In().CheckTty(config.AttachStdin, config.Tty); err != nil {
 			return err
 		}
@@ -223,6 +236,9 @@ func runContainer(ctx context.Context, dockerCli command.Cli, runOpts *runOption
 	if !attach {
 		// Detached mode
 		<-waitDisplayID
+		if runOpts.waitHealthy {
+			return waitHealthy(ctx, apiClient, []string{containerID}, runOpts.waitTimeout)
+		}
 		return nil
 	}
 
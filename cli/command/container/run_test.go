@@ -37,6 +37,11 @@ func TestRunValidateFlags(t *testing.T) {
 			args:        []string{"--detach-keys", "shift-a", "myimage"},
 			expectedErr: "invalid detach keys (shift-a):",
 		},
+		{
+			name:        "with --wait-healthy without --detach",
+			args:        []string{"--wait-healthy", "myimage"},
+			expectedErr: "conflicting options: --wait-healthy requires --detach",
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			cmd := newRunCommand(test.NewFakeCli(&fakeClient{}))
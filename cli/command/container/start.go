@@ -6,6 +6,7 @@
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -24,20 +25,30 @@ type StartOptions struct {
 	DetachKeys    string
 	Checkpoint    string
 	CheckpointDir string
+	WaitHealthy   bool
+	WaitTimeout   time.Duration
+
+	// bulk holds the options for selecting containers by filter. Containers
+	// are started sequentially if bulk.parallel is not set.
+	bulk bulkOptions
 
 	Containers []string
 }
 
 // newStartCommand creates a new cobra.Command for "docker container start".
 func newStartCommand(dockerCLI command.Cli) *cobra.Command {
-	var opts StartOptions
+	opts := StartOptions{bulk: newBulkOptions()}
 
 	cmd := &cobra.Command{
 		Use:   "start [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Start one or more stopped containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  opts.bulk.args,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.Containers = args
+			var err error
+			opts.Containers, err = opts.bulk.containers(cmd.Context(), dockerCLI, args, "start")
+			if err != nil {
+				return err
+			}
 			return RunStart(cmd.Context(), dockerCLI, &opts)
 		},
 		Annotations: map[string]string{
@@ -53,6 +64,9 @@ func newStartCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVarP(&opts.Attach, "attach", "a", false, "Attach STDOUT/STDERR and forward signals")
 	flags.BoolVarP(&opts.OpenStdin, "interactive", "i", false, "Attach container's STDIN")
 	flags.StringVar(&opts.DetachKeys, "detach-keys", "", "Override the key sequence for detaching a container")
+	flags.BoolVar(&opts.WaitHealthy, "wait-healthy", false, "Wait for the containers to be healthy")
+	flags.DurationVar(&opts.WaitTimeout, "wait-timeout", 0, "Maximum time to wait for containers to be healthy (0 to wait without timeout)")
+	opts.bulk.installFlags(flags, 1)
 
 	flags.StringVar(&opts.Checkpoint, "checkpoint", "", "Restore from this checkpoint")
 	flags.SetAnnotation("checkpoint", "experimental", nil)
@@ -78,6 +92,10 @@ func RunStart(ctx context.Context, dockerCli command.Cli, opts *StartOptions) er
 		return err
 	}
 
+	if opts.WaitHealthy && (opts.Attach || opts.OpenStdin || opts.Checkpoint != "") {
+		return errors.New("conflicting options: --wait-healthy cannot be used with --attach, --interactive, or --checkpoint")
+	}
+
 	switch {
 	case opts.Attach || opts.OpenStdin:
 		// We're going to attach to a container.
@@ -194,19 +212,38 @@ func RunStart(ctx context.Context, dockerCli command.Cli, opts *StartOptions) er
 	default:
 		// We're not going to attach to anything.
 		// Start as many containers as we want.
-		return startContainersWithoutAttachments(ctx, dockerCli, opts.Containers)
+		return startContainersWithoutAttachments(ctx, dockerCli, opts)
 	}
 }
 
-func startContainersWithoutAttachments(ctx context.Context, dockerCli command.Cli, containers []string) error {
-	var failedContainers []string
-	for _, ctr := range containers {
-		if _, err := dockerCli.Client().ContainerStart(ctx, ctr, client.ContainerStartOptions{}); err != nil {
+func startContainersWithoutAttachments(ctx context.Context, dockerCli command.Cli, opts *StartOptions) error {
+	parallel := opts.bulk.parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+	errChan := parallelOperation(ctx, opts.Containers, parallel, func(ctx context.Context, ctr string) error {
+		_, err := dockerCli.Client().ContainerStart(ctx, ctr, client.ContainerStartOptions{})
+		return err
+	})
+
+	var failedContainers, started []string
+	for _, ctr := range opts.Containers {
+		if err := <-errChan; err != nil {
 			_, _ = fmt.Fprintln(dockerCli.Err(), err)
 			failedContainers = append(failedContainers, ctr)
 			continue
 		}
 		_, _ = fmt.Fprintln(dockerCli.Out(), ctr)
+		started = append(started, ctr)
+	}
+
+	if opts.WaitHealthy && len(started) > 0 {
+		if err := waitHealthy(ctx, dockerCli.Client(), started, opts.WaitTimeout); err != nil {
+			if len(failedContainers) > 0 {
+				return fmt.Errorf("failed to start containers: %s: %w", strings.Join(failedContainers, ", "), err)
+			}
+			return err
+		}
 	}
 
 	if len(failedContainers) > 0 {
@@ -214,3 +251,16 @@ func startContainersWithoutAttachments(ctx context.Context, dockerCli command.Cl
 	}
 	return nil
 }
+
+// waitHealthy waits for all containers to be healthy, as used by the
+// "--wait-healthy" option of "docker run" and "docker start".
+func waitHealthy(ctx context.Context, apiClient client.APIClient, containers []string, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return waitForCondition(ctx, apiClient, containers, waitConditionOptions{
+		condition: waitCondition{kind: waitConditionHealthy},
+	})
+}
@@ -20,6 +20,11 @@ func TestStartValidateFlags(t *testing.T) {
 			args:        []string{"--detach-keys", "shift-a", "myimage"},
 			expectedErr: "invalid detach keys (shift-a):",
 		},
+		{
+			name:        "with --wait-healthy and --attach",
+			args:        []string{"--wait-healthy", "--attach", "myimage"},
+			expectedErr: "conflicting options: --wait-healthy cannot be used with --attach, --interactive, or --checkpoint",
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			cmd := newStartCommand(test.NewFakeCli(&fakeClient{}))
@@ -5,7 +5,6 @@
 	"errors"
 	"fmt"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/moby/moby/client"
@@ -16,23 +15,28 @@ type stopOptions struct {
 	signal         string
 	timeout        int
 	timeoutChanged bool
+	bulk           bulkOptions
 
 	containers []string
 }
 
 // newStopCommand creates a new cobra.Command for "docker container stop".
 func newStopCommand(dockerCLI command.Cli) *cobra.Command {
-	var opts stopOptions
+	opts := stopOptions{bulk: newBulkOptions()}
 
 	cmd := &cobra.Command{
 		Use:   "stop [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Stop one or more running containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  opts.bulk.args,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if cmd.Flags().Changed("time") && cmd.Flags().Changed("timeout") {
 				return errors.New("conflicting options: cannot specify both --timeout and --time")
 			}
-			opts.containers = args
+			var err error
+			opts.containers, err = opts.bulk.containers(cmd.Context(), dockerCLI, args, "stop")
+			if err != nil {
+				return err
+			}
 			opts.timeoutChanged = cmd.Flags().Changed("timeout") || cmd.Flags().Changed("time")
 			return runStop(cmd.Context(), dockerCLI, &opts)
 		},
@@ -51,6 +55,8 @@ func newStopCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.IntVar(&opts.timeout, "time", 0, "Seconds to wait before killing the container (deprecated: use --timeout)")
 	_ = flags.MarkDeprecated("time", "use --timeout instead")
 
+	opts.bulk.installFlags(flags, defaultParallel)
+
 	_ = cmd.RegisterFlagCompletionFunc("signal", completeSignals)
 
 	return cmd
@@ -63,7 +69,7 @@ func runStop(ctx context.Context, dockerCLI command.Cli, opts *stopOptions) erro
 	}
 
 	apiClient := dockerCLI.Client()
-	errChan := parallelOperation(ctx, opts.containers, func(ctx context.Context, id string) error {
+	errChan := parallelOperation(ctx, opts.containers, opts.bulk.parallel, func(ctx context.Context, id string) error {
 		_, err := apiClient.ContainerStop(ctx, id, client.ContainerStopOptions{
 			Signal:  opts.signal,
 			Timeout: timeout,
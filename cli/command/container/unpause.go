@@ -5,7 +5,6 @@
 	"errors"
 	"fmt"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/moby/moby/api/types/container"
@@ -14,19 +13,25 @@
 )
 
 type unpauseOptions struct {
+	bulk bulkOptions
+
 	containers []string
 }
 
 // newUnpauseCommand creates a new cobra.Command for "docker container unpause".
 func newUnpauseCommand(dockerCLI command.Cli) *cobra.Command {
-	var opts unpauseOptions
+	opts := unpauseOptions{bulk: newBulkOptions()}
 
 	cmd := &cobra.Command{
-		Use:   "unpause CONTAINER [CONTAINER...]",
+		Use:   "unpause [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Unpause all processes within one or more containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  opts.bulk.args,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.containers = args
+			var err error
+			opts.containers, err = opts.bulk.containers(cmd.Context(), dockerCLI, args, "unpause")
+			if err != nil {
+				return err
+			}
 			return runUnpause(cmd.Context(), dockerCLI, &opts)
 		},
 		Annotations: map[string]string{
@@ -37,12 +42,14 @@ func newUnpauseCommand(dockerCLI command.Cli) *cobra.Command {
 		}),
 		DisableFlagsInUseLine: true,
 	}
+
+	opts.bulk.installFlags(cmd.Flags(), defaultParallel)
 	return cmd
 }
 
 func runUnpause(ctx context.Context, dockerCLI command.Cli, opts *unpauseOptions) error {
 	apiClient := dockerCLI.Client()
-	errChan := parallelOperation(ctx, opts.containers, func(ctx context.Context, container string) error {
+	errChan := parallelOperation(ctx, opts.containers, opts.bulk.parallel, func(ctx context.Context, container string) error {
 		_, err := apiClient.ContainerUnpause(ctx, container, client.ContainerUnpauseOptions{})
 		return err
 	})
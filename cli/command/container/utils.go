@@ -49,12 +49,18 @@ func waitExitOrRemoved(ctx context.Context, apiClient client.APIClient, containe
 	return statusC
 }
 
-func parallelOperation(ctx context.Context, containers []string, op func(ctx context.Context, containerID string) error) chan error {
+// defaultParallel is the default number of containers to operate on in
+// parallel for commands that use parallelOperation.
+const defaultParallel int = 50
+
+func parallelOperation(ctx context.Context, containers []string, parallel int, op func(ctx context.Context, containerID string) error) chan error {
 	if len(containers) == 0 {
 		return nil
 	}
-	const defaultParallel int = 50
-	sem := make(chan struct{}, defaultParallel)
+	if parallel < 1 {
+		parallel = defaultParallel
+	}
+	sem := make(chan struct{}, parallel)
 	errChan := make(chan error)
 
 	// make sure result is printed in correct order
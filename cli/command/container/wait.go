@@ -5,16 +5,21 @@
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
+	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
 	"github.com/spf13/cobra"
 )
 
 type waitOptions struct {
 	containers []string
+	condition  string
+	timeout    time.Duration
+	waitAny    bool
 }
 
 // newWaitCommand creates a new cobra.Command for "docker container wait".
@@ -22,7 +27,7 @@ func newWaitCommand(dockerCLI command.Cli) *cobra.Command {
 	var opts waitOptions
 
 	cmd := &cobra.Command{
-		Use:   "wait CONTAINER [CONTAINER...]",
+		Use:   "wait [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Block until one or more containers stop, then print their exit codes",
 		Args:  cli.RequiresMinArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -36,20 +41,73 @@ func newWaitCommand(dockerCLI command.Cli) *cobra.Command {
 		DisableFlagsInUseLine: true,
 	}
 
+	flags := cmd.Flags()
+	flags.StringVar(&opts.condition, "condition", "", `Condition to wait for ("not-running", "next-exit", "removed", "healthy", "running", "started-for=DURATION")`)
+	flags.DurationVar(&opts.timeout, "timeout", 0, "Maximum time to wait (0 to wait without timeout)")
+	flags.BoolVar(&opts.waitAny, "any", false, `Return when any container meets the condition ("healthy", "running", and "started-for" conditions)`)
+
+	_ = cmd.RegisterFlagCompletionFunc("condition", completion.FromList(
+		string(container.WaitConditionNotRunning),
+		string(container.WaitConditionNextExit),
+		string(container.WaitConditionRemoved),
+		waitConditionHealthy,
+		waitConditionRunning,
+		waitConditionStartedFor+"=",
+	))
+
 	return cmd
 }
 
 func runWait(ctx context.Context, dockerCLI command.Cli, opts *waitOptions) error {
+	condition, isClientCondition, err := parseWaitCondition(opts.condition)
+	if err != nil {
+		return err
+	}
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
+	if isClientCondition {
+		// Client-side conditions print the name of each container that
+		// meets the condition.
+		return waitForCondition(ctx, dockerCLI.Client(), opts.containers, waitConditionOptions{
+			condition: condition,
+			waitAny:   opts.waitAny,
+			onMet: func(ctr string) {
+				_, _ = fmt.Fprintln(dockerCLI.Out(), ctr)
+			},
+		})
+	}
+
+	switch cond := container.WaitCondition(opts.condition); cond {
+	case "", container.WaitConditionNotRunning, container.WaitConditionNextExit, container.WaitConditionRemoved:
+	default:
+		return fmt.Errorf("invalid condition '%s': must be one of %q, %q, %q, %q, %q, or %q", cond,
+			container.WaitConditionNotRunning, container.WaitConditionNextExit, container.WaitConditionRemoved,
+			waitConditionHealthy, waitConditionRunning, waitConditionStartedFor+"=DURATION",
+		)
+	}
+	if opts.waitAny {
+		return fmt.Errorf(`--any can only be used with the %q, %q, and %q conditions`, waitConditionHealthy, waitConditionRunning, waitConditionStartedFor)
+	}
+
 	apiClient := dockerCLI.Client()
 
 	var errs []error
 	for _, ctr := range opts.containers {
-		res := apiClient.ContainerWait(ctx, ctr, client.ContainerWaitOptions{})
+		res := apiClient.ContainerWait(ctx, ctr, client.ContainerWaitOptions{
+			Condition: container.WaitCondition(opts.condition),
+		})
 
 		select {
 		case result := <-res.Result:
 			_, _ = fmt.Fprintln(dockerCLI.Out(), strconv.FormatInt(result.StatusCode, 10))
 		case err := <-res.Error:
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = fmt.Errorf("timeout waiting for container %s", ctr)
+			}
 			errs = append(errs, err)
 		}
 	}
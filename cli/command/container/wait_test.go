@@ -0,0 +1,283 @@
+package container
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestParseWaitCondition(t *testing.T) {
+	tests := []struct {
+		value       string
+		expected    waitCondition
+		isClient    bool
+		expectedErr string
+	}{
+		{value: ""},
+		{value: "not-running"},
+		{value: "next-exit"},
+		{value: "healthy", expected: waitCondition{kind: waitConditionHealthy}, isClient: true},
+		{value: "running", expected: waitCondition{kind: waitConditionRunning}, isClient: true},
+		{value: "started-for=30s", expected: waitCondition{kind: waitConditionStartedFor, duration: 30 * time.Second}, isClient: true},
+		{value: "healthy=true", isClient: true, expectedErr: "invalid condition 'healthy=true': condition 'healthy' does not take a value"},
+		{value: "started-for", isClient: true, expectedErr: "invalid condition 'started-for': expected a positive duration (for example, 'started-for=30s')"},
+		{value: "started-for=-1s", isClient: true, expectedErr: "invalid condition 'started-for=-1s': expected a positive duration (for example, 'started-for=30s')"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			cond, isClient, err := parseWaitCondition(tc.value)
+			if tc.expectedErr != "" {
+				assert.Check(t, is.Error(err, tc.expectedErr))
+			} else {
+				assert.Check(t, err)
+				assert.Check(t, is.Equal(cond, tc.expected))
+			}
+			assert.Check(t, is.Equal(isClient, tc.isClient))
+		})
+	}
+}
+
+func TestWaitConditionCheck(t *testing.T) {
+	now := time.Now()
+	healthcheck := &container.Config{Healthcheck: &container.HealthConfig{Test: []string{"CMD", "true"}}}
+	tests := []struct {
+		doc           string
+		condition     waitCondition
+		state         container.State
+		config        *container.Config
+		expectedMet   bool
+		expectedRetry time.Duration
+		expectedErr   string
+	}{
+		{
+			doc:       "running: not running",
+			condition: waitCondition{kind: waitConditionRunning},
+			state:     container.State{Status: container.StateCreated},
+		},
+		{
+			doc:         "running: running",
+			condition:   waitCondition{kind: waitConditionRunning},
+			state:       container.State{Status: container.StateRunning, Running: true},
+			expectedMet: true,
+		},
+		{
+			doc:         "healthy: exited",
+			condition:   waitCondition{kind: waitConditionHealthy},
+			state:       container.State{Status: container.StateExited, ExitCode: 3},
+			config:      healthcheck,
+			expectedErr: "container web exited with code 3",
+		},
+		{
+			doc:         "healthy: no health check",
+			condition:   waitCondition{kind: waitConditionHealthy},
+			state:       container.State{Status: container.StateRunning, Running: true},
+			expectedErr: "container web has no health check",
+		},
+		{
+			doc:         "healthy: health check disabled",
+			condition:   waitCondition{kind: waitConditionHealthy},
+			state:       container.State{Status: container.StateRunning, Running: true},
+			config:      &container.Config{Healthcheck: &container.HealthConfig{Test: []string{"NONE"}}},
+			expectedErr: "container web has no health check",
+		},
+		{
+			doc:       "healthy: starting",
+			condition: waitCondition{kind: waitConditionHealthy},
+			state:     container.State{Status: container.StateRunning, Running: true, Health: &container.Health{Status: container.Starting}},
+			config:    healthcheck,
+		},
+		{
+			doc:       "healthy: unhealthy",
+			condition: waitCondition{kind: waitConditionHealthy},
+			state:     container.State{Status: container.StateRunning, Running: true, Health: &container.Health{Status: container.Unhealthy}},
+			config:    healthcheck,
+		},
+		{
+			doc:         "healthy: healthy",
+			condition:   waitCondition{kind: waitConditionHealthy},
+			state:       container.State{Status: container.StateRunning, Running: true, Health: &container.Health{Status: container.Healthy}},
+			config:      healthcheck,
+			expectedMet: true,
+		},
+		{
+			doc:           "started-for: not long enough",
+			condition:     waitCondition{kind: waitConditionStartedFor, duration: 30 * time.Second},
+			state:         container.State{Status: container.StateRunning, Running: true, StartedAt: now.Add(-10 * time.Second).Format(time.RFC3339Nano)},
+			expectedRetry: 20 * time.Second,
+		},
+		{
+			doc:         "started-for: long enough",
+			condition:   waitCondition{kind: waitConditionStartedFor, duration: 30 * time.Second},
+			state:       container.State{Status: container.StateRunning, Running: true, StartedAt: now.Add(-time.Minute).Format(time.RFC3339Nano)},
+			expectedMet: true,
+		},
+		{
+			doc:         "started-for: not running",
+			condition:   waitCondition{kind: waitConditionStartedFor, duration: 30 * time.Second},
+			state:       container.State{Status: container.StateCreated},
+			expectedErr: "container web is not running",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			state := tc.state
+			ctr := container.InspectResponse{Name: "web", State: &state, Config: tc.config}
+			met, retry, err := tc.condition.check(ctr, now)
+			if tc.expectedErr != "" {
+				assert.Check(t, is.Error(err, tc.expectedErr))
+			} else {
+				assert.Check(t, err)
+			}
+			assert.Check(t, is.Equal(met, tc.expectedMet))
+			assert.Check(t, is.Equal(retry, tc.expectedRetry))
+		})
+	}
+}
+
+// fakeHealthClient returns a fakeClient for containers with a health check,
+// of which the health status can be updated through setStatus, which also
+// produces a "health_status" event.
+func fakeHealthClient(initial map[string]container.HealthStatus) (*fakeClient, func(id string, status container.HealthStatus)) {
+	var mu sync.Mutex
+	statuses := initial
+	messages := make(chan events.Message, 10)
+
+	setStatus := func(id string, status container.HealthStatus) {
+		mu.Lock()
+		statuses[id] = status
+		mu.Unlock()
+		messages <- events.Message{
+			Type:   events.ContainerEventType,
+			Action: events.Action(string(events.ActionHealthStatus) + ": " + string(status)),
+			Actor:  events.Actor{ID: id},
+		}
+	}
+
+	return &fakeClient{
+		inspectFunc: func(id string) (client.ContainerInspectResult, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return client.ContainerInspectResult{
+				Container: container.InspectResponse{
+					ID: id,
+					State: &container.State{
+						Status:  container.StateRunning,
+						Running: true,
+						Health:  &container.Health{Status: statuses[id]},
+					},
+					Config: &container.Config{Healthcheck: &container.HealthConfig{Test: []string{"CMD", "true"}}},
+				},
+			}, nil
+		},
+		eventsFunc: func(context.Context, client.EventsListOptions) client.EventsResult {
+			return client.EventsResult{Messages: messages, Err: make(chan error)}
+		},
+	}, setStatus
+}
+
+func TestWaitForConditionHealthy(t *testing.T) {
+	apiClient, setStatus := fakeHealthClient(map[string]container.HealthStatus{
+		"one": container.Starting,
+		"two": container.Healthy,
+	})
+
+	var met []string
+	done := make(chan error)
+	go func() {
+		done <- waitForCondition(context.Background(), apiClient, []string{"one", "two"}, waitConditionOptions{
+			condition: waitCondition{kind: waitConditionHealthy},
+			onMet:     func(ctr string) { met = append(met, ctr) },
+		})
+	}()
+
+	setStatus("one", container.Unhealthy)
+	setStatus("one", container.Healthy)
+	assert.NilError(t, <-done)
+	sort.Strings(met)
+	assert.Check(t, is.DeepEqual(met, []string{"one", "two"}))
+}
+
+func TestWaitForConditionAny(t *testing.T) {
+	apiClient, setStatus := fakeHealthClient(map[string]container.HealthStatus{
+		"one": container.Starting,
+		"two": container.Starting,
+	})
+
+	var met []string
+	done := make(chan error)
+	go func() {
+		done <- waitForCondition(context.Background(), apiClient, []string{"one", "two"}, waitConditionOptions{
+			condition: waitCondition{kind: waitConditionHealthy},
+			waitAny:   true,
+			onMet:     func(ctr string) { met = append(met, ctr) },
+		})
+	}()
+
+	setStatus("two", container.Healthy)
+	assert.NilError(t, <-done)
+	assert.Check(t, is.DeepEqual(met, []string{"two"}))
+}
+
+func TestWaitForConditionRemoved(t *testing.T) {
+	apiClient, _ := fakeHealthClient(map[string]container.HealthStatus{"one": container.Starting})
+	messages := make(chan events.Message, 1)
+	messages <- events.Message{Type: events.ContainerEventType, Action: events.ActionDestroy, Actor: events.Actor{ID: "one"}}
+	apiClient.eventsFunc = func(context.Context, client.EventsListOptions) client.EventsResult {
+		return client.EventsResult{Messages: messages, Err: make(chan error)}
+	}
+
+	err := waitForCondition(context.Background(), apiClient, []string{"one"}, waitConditionOptions{
+		condition: waitCondition{kind: waitConditionHealthy},
+	})
+	assert.Check(t, is.Error(err, "container one was removed"))
+}
+
+func TestRunWaitTimeout(t *testing.T) {
+	apiClient, _ := fakeHealthClient(map[string]container.HealthStatus{
+		"one": container.Starting,
+		"two": container.Healthy,
+		"six": container.Starting,
+	})
+	cli := test.NewFakeCli(apiClient)
+	cmd := newWaitCommand(cli)
+	cmd.SetArgs([]string{"--condition", "healthy", "--timeout", "10ms", "one", "two", "six"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "timeout waiting for condition 'healthy': one, six"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "two\n"))
+}
+
+func TestRunWaitErrors(t *testing.T) {
+	tests := []struct {
+		args        []string
+		expectedErr string
+	}{
+		{
+			args:        []string{"--condition", "stopped", "one"},
+			expectedErr: `invalid condition 'stopped': must be one of "not-running", "next-exit", "removed", "healthy", "running", or "started-for=DURATION"`,
+		},
+		{
+			args:        []string{"--any", "one", "two"},
+			expectedErr: `--any can only be used with the "healthy", "running", and "started-for" conditions`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expectedErr, func(t *testing.T) {
+			cmd := newWaitCommand(test.NewFakeCli(&fakeClient{}))
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.Check(t, is.Error(cmd.Execute(), tc.expectedErr))
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
+)
+
+// Client-side wait conditions, which are evaluated using the events stream,
+// in addition to the conditions supported by the API ("not-running",
+// "next-exit", and "removed").
+const (
+	waitConditionHealthy    = "healthy"
+	waitConditionRunning    = "running"
+	waitConditionStartedFor = "started-for"
+)
+
+// waitCondition is a client-side condition to wait for.
+type waitCondition struct {
+	kind string
+	// duration is the minimum duration a container must be running for
+	// the "started-for" condition.
+	duration time.Duration
+}
+
+// parseWaitCondition parses a client-side wait condition. It returns false
+// if s is not a client-side condition.
+func parseWaitCondition(s string) (waitCondition, bool, error) {
+	kind, value, hasValue := strings.Cut(s, "=")
+	switch kind {
+	case waitConditionHealthy, waitConditionRunning:
+		if hasValue {
+			return waitCondition{}, true, fmt.Errorf("invalid condition '%s': condition '%s' does not take a value", s, kind)
+		}
+		return waitCondition{kind: kind}, true, nil
+	case waitConditionStartedFor:
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return waitCondition{}, true, fmt.Errorf("invalid condition '%s': expected a positive duration (for example, '%s=30s')", s, kind)
+		}
+		return waitCondition{kind: kind, duration: d}, true, nil
+	default:
+		return waitCondition{}, false, nil
+	}
+}
+
+func (c waitCondition) String() string {
+	if c.kind == waitConditionStartedFor {
+		return c.kind + "=" + c.duration.String()
+	}
+	return c.kind
+}
+
+// check returns whether the condition is met for the container. If the
+// condition may be met at a later time without further events, it returns
+// the duration after which to check again. An error is returned if the
+// condition cannot be met; the "healthy" and "started-for" conditions
+// require the container to be running, and "healthy" requires the container
+// to have a health check.
+func (c waitCondition) check(ctr container.InspectResponse, now time.Time) (bool, time.Duration, error) {
+	if ctr.State == nil {
+		return false, 0, nil
+	}
+	if c.kind == waitConditionRunning {
+		return ctr.State.Running, 0, nil
+	}
+	if !ctr.State.Running && !ctr.State.Restarting {
+		if ctr.State.Status == container.StateExited {
+			return false, 0, fmt.Errorf("container %s exited with code %d", ctr.Name, ctr.State.ExitCode)
+		}
+		return false, 0, fmt.Errorf("container %s is not running", ctr.Name)
+	}
+
+	switch c.kind {
+	case waitConditionHealthy:
+		if ctr.State.Health == nil || ctr.State.Health.Status == container.NoHealthcheck {
+			if !hasHealthcheck(ctr.Config) {
+				return false, 0, fmt.Errorf("container %s has no health check", ctr.Name)
+			}
+			return false, 0, nil
+		}
+		return ctr.State.Health.Status == container.Healthy, 0, nil
+	case waitConditionStartedFor:
+		if !ctr.State.Running {
+			return false, 0, nil
+		}
+		startedAt, err := time.Parse(time.RFC3339Nano, ctr.State.StartedAt)
+		if err != nil {
+			return false, 0, fmt.Errorf("container %s: invalid start time: %w", ctr.Name, err)
+		}
+		if remaining := c.duration - now.Sub(startedAt); remaining > 0 {
+			return false, remaining, nil
+		}
+		return true, 0, nil
+	default:
+		return false, 0, fmt.Errorf("invalid condition: %s", c.kind)
+	}
+}
+
+func hasHealthcheck(config *container.Config) bool {
+	if config == nil || config.Healthcheck == nil || len(config.Healthcheck.Test) == 0 {
+		return false
+	}
+	return config.Healthcheck.Test[0] != "NONE"
+}
+
+// waitConditionOptions are the options for waitForCondition.
+type waitConditionOptions struct {
+	condition waitCondition
+	// waitAny returns as soon as the condition is met for any of the
+	// containers, instead of waiting for all containers.
+	waitAny bool
+	// onMet is called for each container that meets the condition.
+	onMet func(ctr string)
+}
+
+// waitForCondition waits for the containers to meet a client-side condition.
+// The condition is checked for each container when waiting starts, and after
+// each event that can change the container's state. Containers that are
+// removed while waiting, or that can no longer meet the condition, produce
+// an error.
+func waitForCondition(ctx context.Context, apiClient client.APIClient, containers []string, opts waitConditionOptions) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Resolve the containers to their IDs, which are used to filter events.
+	ids := make(map[string]string, len(containers))
+	f := make(client.Filters).Add("type", string(events.ContainerEventType))
+	for _, ctr := range containers {
+		res, err := apiClient.ContainerInspect(ctx, ctr, client.ContainerInspectOptions{})
+		if err != nil {
+			return err
+		}
+		ids[res.Container.ID] = ctr
+		f.Add("container", res.Container.ID)
+	}
+
+	// Subscribe to events before checking the current state of the containers,
+	// so that no state-changes are missed.
+	res := apiClient.Events(ctx, client.EventsListOptions{Filters: f})
+
+	recheck := make(chan string)
+	pending := make(map[string]struct{}, len(ids))
+	var errs []error
+
+	// evaluate checks the condition for the given container, and returns
+	// true if waiting is complete.
+	evaluate := func(id string) (bool, error) {
+		if _, ok := pending[id]; !ok {
+			return false, nil
+		}
+		inspect, err := apiClient.ContainerInspect(ctx, id, client.ContainerInspectOptions{})
+		if err != nil {
+			return false, err
+		}
+		inspect.Container.Name = ids[id]
+		met, retry, err := opts.condition.check(inspect.Container, time.Now())
+		switch {
+		case err != nil:
+			delete(pending, id)
+			errs = append(errs, err)
+			if !opts.waitAny || len(pending) == 0 {
+				return true, errors.Join(errs...)
+			}
+		case met:
+			delete(pending, id)
+			if opts.onMet != nil {
+				opts.onMet(ids[id])
+			}
+			return opts.waitAny || len(pending) == 0, nil
+		case retry > 0:
+			time.AfterFunc(retry, func() {
+				select {
+				case recheck <- id:
+				case <-ctx.Done():
+				}
+			})
+		}
+		return false, nil
+	}
+
+	for id := range ids {
+		pending[id] = struct{}{}
+	}
+	for id := range ids {
+		if done, err := evaluate(id); done || err != nil {
+			return err
+		}
+	}
+
+	for {
+		var id string
+		select {
+		case <-ctx.Done():
+			return waitError(ctx, ctx.Err(), pending, ids, opts.condition)
+		case err := <-res.Err:
+			return waitError(ctx, err, pending, ids, opts.condition)
+		case id = <-recheck:
+		case msg := <-res.Messages:
+			id = msg.Actor.ID
+			switch {
+			case msg.Action == events.ActionDestroy:
+				if _, ok := pending[id]; ok {
+					delete(pending, id)
+					errs = append(errs, fmt.Errorf("container %s was removed", ids[id]))
+					if !opts.waitAny || len(pending) == 0 {
+						return errors.Join(errs...)
+					}
+				}
+				continue
+			case !isStateChangeEvent(msg.Action):
+				continue
+			}
+		}
+		if done, err := evaluate(id); done || err != nil {
+			return err
+		}
+	}
+}
+
+// isStateChangeEvent returns whether the event can change the outcome of
+// a wait condition. Events such as "exec_start", which are produced for
+// each health check, are ignored.
+func isStateChangeEvent(action events.Action) bool {
+	switch action {
+	case events.ActionStart, events.ActionRestart, events.ActionDie, events.ActionPause, events.ActionUnPause:
+		return true
+	default:
+		return strings.HasPrefix(string(action), string(events.ActionHealthStatus))
+	}
+}
+
+// waitError returns a descriptive error if waiting was interrupted because
+// the timeout expired.
+func waitError(ctx context.Context, err error, pending map[string]struct{}, ids map[string]string, condition waitCondition) error {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	names := make([]string, 0, len(pending))
+	for id := range pending {
+		names = append(names, ids[id])
+	}
+	sort.Strings(names)
+	return fmt.Errorf("timeout waiting for condition '%s': %s", condition, strings.Join(names, ", "))
+}
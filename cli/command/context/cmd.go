@@ -31,6 +31,8 @@ func newContextCommand(dockerCLI command.Cli) *cobra.Command {
 		newUpdateCommand(dockerCLI),
 		newInspectCommand(dockerCLI),
 		newShowCommand(dockerCLI),
+		newDetectCommand(dockerCLI),
+		newMigrateKeysCommand(dockerCLI),
 	)
 	return cmd
 }
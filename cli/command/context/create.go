@@ -23,6 +23,9 @@ type createOptions struct {
 	endpoint    map[string]string
 	from        string
 
+	failover       []string
+	failoverPolicy string
+
 	// Additional Metadata to store in the context. This option is not
 	// currently exposed to the user.
 	metaData map[string]any
@@ -58,6 +61,9 @@ func newCreateCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.StringVar(&opts.description, "description", "", "Description of the context")
 	flags.StringToStringVar(&opts.endpoint, "docker", nil, "set the docker endpoint")
 	flags.StringVar(&opts.from, "from", "", "create context from a named context")
+	flags.StringArrayVar(&opts.failover, "failover", nil, "Add a docker endpoint to fail over to, in the same format as --docker")
+	flags.StringVar(&opts.failoverPolicy, "failover-policy", "", `Policy to select an endpoint ("first", "latency", "round-robin")`)
+	_ = cmd.RegisterFlagCompletionFunc("failover-policy", cobra.FixedCompletions(docker.Policies, cobra.ShellCompDirectiveNoFileComp))
 	return cmd
 }
 
@@ -68,6 +74,10 @@ func runCreate(dockerCLI command.Cli, name string, opts createOptions) error {
 	if err != nil {
 		return err
 	}
+	proxyCreds, err := extractEndpointsProxyCredentials(opts.endpoint, opts.failover)
+	if err != nil {
+		return err
+	}
 	switch {
 	case opts.from == "" && opts.endpoint == nil:
 		err = createFromExistingContext(s, name, dockerCLI.CurrentContext(), opts)
@@ -76,6 +86,9 @@ func runCreate(dockerCLI command.Cli, name string, opts createOptions) error {
 	default:
 		err = createNewContext(s, name, opts)
 	}
+	if err == nil {
+		err = storeProxyCredentials(dockerCLI.ConfigFile(), proxyCreds)
+	}
 	if err == nil {
 		_, _ = fmt.Fprintln(dockerCLI.Out(), name)
 		_, _ = fmt.Fprintf(dockerCLI.Err(), "Successfully created context %q\n", name)
@@ -87,13 +100,13 @@ func createNewContext(contextStore store.ReaderWriter, name string, opts createO
 	if opts.endpoint == nil {
 		return errors.New("docker endpoint configuration is required")
 	}
-	dockerEP, dockerTLS, err := getDockerEndpointMetadataAndTLS(contextStore, opts.endpoint)
+	dockerEP, dockerTLS, err := getDockerEndpointAndTLS(contextStore, opts.endpoint)
 	if err != nil {
 		return fmt.Errorf("unable to create docker endpoint config: %w", err)
 	}
 	contextMetadata := store.Metadata{
 		Endpoints: map[string]any{
-			docker.DockerEndpoint: dockerEP,
+			docker.DockerEndpoint: dockerEP.EndpointMeta,
 		},
 		Metadata: command.DockerContext{
 			Description:      opts.description,
@@ -101,12 +114,27 @@ func createNewContext(contextStore store.ReaderWriter, name string, opts createO
 		},
 		Name: name,
 	}
-	contextTLSData := store.ContextTLSData{}
+	contextTLSData := store.ContextTLSData{
+		Endpoints: map[string]store.EndpointTLSData{},
+	}
 	if dockerTLS != nil {
-		contextTLSData.Endpoints = map[string]store.EndpointTLSData{
-			docker.DockerEndpoint: *dockerTLS,
+		contextTLSData.Endpoints[docker.DockerEndpoint] = *dockerTLS
+	}
+	proxies := docker.ProxyMeta{Proxy: dockerEP.Proxy}
+	if len(opts.failover) > 0 {
+		failover, failoverProxies, failoverTLS, err := getFailoverMetadataAndTLS(contextStore, opts.failover, opts.failoverPolicy)
+		if err != nil {
+			return fmt.Errorf("unable to create failover endpoint config: %w", err)
 		}
+		contextMetadata.Endpoints[docker.FailoverEndpoint] = failover
+		proxies.Failover = failoverProxies
+		for ep, tlsData := range failoverTLS {
+			contextTLSData.Endpoints[ep] = *tlsData
+		}
+	} else if opts.failoverPolicy != "" {
+		return errors.New("cannot use --failover-policy flag without --failover")
 	}
+	setProxies(&contextMetadata, proxies)
 	if err := validateEndpoints(contextMetadata); err != nil {
 		return err
 	}
@@ -133,6 +161,9 @@ func createFromExistingContext(s store.ReaderWriter, name string, fromContextNam
 	if len(opts.endpoint) != 0 {
 		return errors.New("cannot use --docker flag when --from is set")
 	}
+	if len(opts.failover) != 0 || opts.failoverPolicy != "" {
+		return errors.New("cannot use --failover flags when --from is set")
+	}
 	reader := store.Export(fromContextName, &descriptionDecorator{
 		Reader:      s,
 		description: opts.description,
@@ -0,0 +1,263 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package context
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/internal/prompt"
+	"github.com/moby/moby/client"
+	"github.com/spf13/cobra"
+)
+
+type detectOptions struct {
+	yes     bool
+	timeout time.Duration
+}
+
+func newDetectCommand(dockerCLI command.Cli) *cobra.Command {
+	opts := detectOptions{}
+	cmd := &cobra.Command{
+		Use:   "detect [OPTIONS]",
+		Short: "Detect local daemons and create contexts for them",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDetect(cmd.Context(), dockerCLI, opts)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.yes, "yes", "y", false, "Create contexts for all detected daemons without prompting for confirmation")
+	flags.DurationVar(&opts.timeout, "timeout", 2*time.Second, "Maximum time to wait for each daemon to respond")
+	return cmd
+}
+
+// detectCandidate is a well-known location of a daemon's API socket.
+type detectCandidate struct {
+	// name is the name of the context to create for the daemon.
+	name        string
+	host        string
+	description string
+
+	// explicit indicates that the candidate was configured by the user,
+	// and must be reported if the daemon is not reachable.
+	explicit bool
+}
+
+// detectCandidates returns the candidates to probe. It's a variable so that
+// it can be replaced in tests.
+var detectCandidates = wellKnownEndpoints
+
+// wellKnownEndpoints returns the locations at which rootful and rootless
+// Docker Engine, Docker Desktop, and other common runtimes provide their API
+// socket by default, and the location that's set through the DOCKER_HOST
+// environment variable.
+func wellKnownEndpoints() []detectCandidate {
+	var candidates []detectCandidate
+	if host := os.Getenv(client.EnvOverrideHost); host != "" {
+		candidates = append(candidates, detectCandidate{name: "docker-host", host: host, description: client.EnvOverrideHost + " environment variable", explicit: true})
+	}
+	if runtime.GOOS == "windows" {
+		return append(candidates,
+			detectCandidate{name: "docker-engine", host: "npipe:////./pipe/docker_engine", description: "Docker Engine"},
+			detectCandidate{name: "desktop-linux", host: "npipe:////./pipe/dockerDesktopLinuxEngine", description: "Docker Desktop"},
+			detectCandidate{name: "podman", host: "npipe:////./pipe/podman-machine-default", description: "Podman"},
+			detectCandidate{name: "tcp", host: "tcp://localhost:2375", description: "Docker Engine on TCP port 2375"},
+		)
+	}
+
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		runtimeDir = "/run/user/" + strconv.Itoa(os.Getuid())
+	}
+	candidates = append(candidates,
+		detectCandidate{name: "rootful", host: "unix:///var/run/docker.sock", description: "Docker Engine"},
+		detectCandidate{name: "rootless", host: "unix://" + filepath.Join(runtimeDir, "docker.sock"), description: "Docker Engine (rootless)"},
+		detectCandidate{name: "podman", host: "unix://" + filepath.Join(runtimeDir, "podman", "podman.sock"), description: "Podman (rootless)"},
+		detectCandidate{name: "podman-rootful", host: "unix:///run/podman/podman.sock", description: "Podman"},
+	)
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates,
+			detectCandidate{name: "desktop", host: "unix://" + filepath.Join(home, ".docker", "run", "docker.sock"), description: "Docker Desktop"},
+			detectCandidate{name: "orbstack", host: "unix://" + filepath.Join(home, ".orbstack", "run", "docker.sock"), description: "OrbStack"},
+			detectCandidate{name: "rancher-desktop", host: "unix://" + filepath.Join(home, ".rd", "docker.sock"), description: "Rancher Desktop"},
+		)
+		colima, _ := filepath.Glob(filepath.Join(home, ".colima", "*", "docker.sock"))
+		for _, sock := range colima {
+			profile := filepath.Base(filepath.Dir(sock))
+			name := "colima"
+			if profile != "default" {
+				name += "-" + profile
+			}
+			candidates = append(candidates, detectCandidate{name: name, host: "unix://" + sock, description: "Colima (profile " + profile + ")"})
+		}
+		lima, _ := filepath.Glob(filepath.Join(home, ".lima", "*", "sock", "docker.sock"))
+		for _, sock := range lima {
+			instance := filepath.Base(filepath.Dir(filepath.Dir(sock)))
+			candidates = append(candidates, detectCandidate{name: "lima-" + instance, host: "unix://" + sock, description: "Lima (instance " + instance + ")"})
+		}
+	}
+	return append(candidates, detectCandidate{name: "tcp", host: "tcp://localhost:2375", description: "Docker Engine on TCP port 2375"})
+}
+
+// detectResult is the result of probing a [detectCandidate].
+type detectResult struct {
+	detectCandidate
+	ping client.PingResult
+	err  error
+
+	// existing is the name of the context that already uses the endpoint.
+	existing string
+}
+
+func (r detectResult) status() string {
+	switch {
+	case r.err != nil:
+		return "unreachable: " + r.err.Error()
+	case r.existing != "":
+		return fmt.Sprintf("reachable, used by context %q", r.existing)
+	default:
+		return fmt.Sprintf("reachable (API %s, %s)", r.ping.APIVersion, r.ping.OSType)
+	}
+}
+
+// runDetect probes well-known daemon endpoints, and creates contexts for
+// the reachable endpoints that are not used by an existing context.
+func runDetect(ctx context.Context, dockerCLI command.Cli, opts detectOptions) error {
+	s := dockerCLI.ContextStore()
+	contexts, err := s.List()
+	if err != nil {
+		return err
+	}
+	existing := map[string]string{}
+	names := map[string]struct{}{}
+	for _, c := range contexts {
+		names[c.Name] = struct{}{}
+		if ep, err := docker.EndpointFromContext(c); err == nil && ep.Host != "" {
+			if _, ok := existing[ep.Host]; !ok {
+				existing[ep.Host] = c.Name
+			}
+		}
+	}
+
+	var results []detectResult
+	for _, c := range detectCandidates() {
+		if path, ok := strings.CutPrefix(c.host, "unix://"); ok {
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			// Report sockets that exist, even if the daemon is not reachable.
+			c.explicit = true
+		}
+		r := detectResult{detectCandidate: c, existing: existing[c.host]}
+		r.ping, r.err = pingEndpoint(ctx, c.host, opts.timeout)
+		if r.err != nil && !c.explicit {
+			continue
+		}
+		results = append(results, r)
+	}
+	if len(results) == 0 {
+		_, _ = fmt.Fprintln(dockerCLI.Err(), "No daemons detected")
+		return nil
+	}
+
+	var create []detectResult
+	for i, r := range results {
+		if r.err == nil && r.existing == "" {
+			results[i].name = uniqueContextName(names, r.name)
+			names[results[i].name] = struct{}{}
+			create = append(create, results[i])
+		}
+	}
+
+	tw := tabwriter.NewWriter(dockerCLI.Out(), 0, 1, 3, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NAME\tDESCRIPTION\tDOCKER ENDPOINT\tSTATUS")
+	for _, r := range results {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.name, r.description, r.host, r.status())
+	}
+	_ = tw.Flush()
+
+	if len(create) == 0 {
+		_, _ = fmt.Fprintln(dockerCLI.Err(), "No new contexts to create")
+		return nil
+	}
+	if !opts.yes {
+		newNames := make([]string, 0, len(create))
+		for _, r := range create {
+			newNames = append(newNames, r.name)
+		}
+		msg := fmt.Sprintf("Create contexts for the detected daemons (%s)?", strings.Join(newNames, ", "))
+		if len(create) == 1 {
+			msg = fmt.Sprintf("Create context %q for the detected daemon?", create[0].name)
+		}
+		ok, err := prompt.Confirm(ctx, dockerCLI.In(), dockerCLI.Out(), msg)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("context detection has been cancelled")
+		}
+	}
+	for _, r := range create {
+		err := createNewContext(s, r.name, createOptions{
+			description: r.description,
+			endpoint:    map[string]string{keyHost: r.host},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create context %q: %w", r.name, err)
+		}
+		_, _ = fmt.Fprintf(dockerCLI.Err(), "Successfully created context %q\n", r.name)
+	}
+	return nil
+}
+
+// uniqueContextName returns name if it's not in use, or the name with the
+// first numeric suffix that's not in use.
+func uniqueContextName(names map[string]struct{}, name string) string {
+	if _, ok := names[name]; !ok {
+		return name
+	}
+	for i := 2; ; i++ {
+		n := name + "-" + strconv.Itoa(i)
+		if _, ok := names[n]; !ok {
+			return n
+		}
+	}
+}
+
+// pingEndpoint pings the daemon at the given host, and returns an error if
+// it does not respond within the given timeout.
+func pingEndpoint(ctx context.Context, host string, timeout time.Duration) (client.PingResult, error) {
+	ep := docker.Endpoint{EndpointMeta: docker.EndpointMeta{Host: host}}
+	clientOpts, err := ep.ClientOpts()
+	if err != nil {
+		return client.PingResult{}, err
+	}
+	apiClient, err := client.New(clientOpts...)
+	if err != nil {
+		return client.PingResult{}, err
+	}
+	defer apiClient.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ping, err := apiClient.Ping(ctx, client.PingOptions{})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ping, fmt.Errorf("no response within %s", timeout)
+	}
+	return ping, err
+}
@@ -0,0 +1,137 @@
+package context
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// startDaemon starts a fake daemon that responds to pings on a unix socket,
+// and returns its host.
+func startDaemon(t *testing.T, dir string) string {
+	t.Helper()
+	sock := filepath.Join(dir, "docker.sock")
+	l, err := net.Listen("unix", sock)
+	assert.NilError(t, err)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", "1.51")
+		w.Header().Set("Ostype", "linux")
+		_, _ = w.Write([]byte("OK"))
+	}))
+	srv.Listener = l
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return "unix://" + sock
+}
+
+func setDetectCandidates(t *testing.T, candidates []detectCandidate) {
+	t.Helper()
+	orig := detectCandidates
+	detectCandidates = func() []detectCandidate { return candidates }
+	t.Cleanup(func() { detectCandidates = orig })
+}
+
+func contextHost(t *testing.T, cli *test.FakeCli, name string) string {
+	t.Helper()
+	m, err := cli.ContextStore().GetMetadata(name)
+	assert.NilError(t, err)
+	ep, err := docker.EndpointFromContext(m)
+	assert.NilError(t, err)
+	return ep.Host
+}
+
+func TestDetect(t *testing.T) {
+	rootless := startDaemon(t, t.TempDir())
+	podman := startDaemon(t, t.TempDir())
+	desktop := startDaemon(t, t.TempDir())
+	setDetectCandidates(t, []detectCandidate{
+		{name: "rootless", host: rootless, description: "Docker Engine (rootless)"},
+		{name: "podman", host: podman, description: "Podman (rootless)"},
+		{name: "desktop", host: desktop, description: "Docker Desktop"},
+		{name: "colima", host: "unix://" + filepath.Join(t.TempDir(), "docker.sock"), description: "Colima (profile default)"},
+	})
+
+	cli := makeFakeCli(t)
+	// Already has a context for the endpoint.
+	assert.NilError(t, createNewContext(cli.ContextStore(), "my-desktop", createOptions{endpoint: map[string]string{keyHost: desktop}}))
+	// Has a context with the same name, but for a different endpoint.
+	assert.NilError(t, createNewContext(cli.ContextStore(), "podman", createOptions{endpoint: map[string]string{keyHost: "tcp://podman.example.com:2375"}}))
+
+	cmd := newDetectCommand(cli)
+	cmd.SetArgs([]string{"--yes"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	out := cli.OutBuffer().String()
+	assert.Check(t, is.Contains(out, "rootless   Docker Engine (rootless)"))
+	assert.Check(t, is.Contains(out, "reachable (API 1.51, linux)"))
+	assert.Check(t, is.Contains(out, `reachable, used by context "my-desktop"`))
+	assert.Check(t, !strings.Contains(out, "colima"), "sockets that don't exist must not be reported")
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), `Successfully created context "rootless"`))
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), `Successfully created context "podman-2"`))
+
+	assert.Check(t, is.Equal(contextHost(t, cli, "rootless"), rootless))
+	assert.Check(t, is.Equal(contextHost(t, cli, "podman-2"), podman))
+	_, err := cli.ContextStore().GetMetadata("desktop")
+	assert.Check(t, err != nil, "must not create a context for an endpoint that's already used")
+}
+
+func TestDetectConfirm(t *testing.T) {
+	rootless := startDaemon(t, t.TempDir())
+	podman := startDaemon(t, t.TempDir())
+	setDetectCandidates(t, []detectCandidate{
+		{name: "rootless", host: rootless, description: "Docker Engine (rootless)"},
+		{name: "podman", host: podman, description: "Podman (rootless)"},
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		cli := makeFakeCli(t)
+		cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader("n\n"))))
+		cmd := newDetectCommand(cli)
+		cmd.SetArgs([]string{})
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		assert.Check(t, is.Error(cmd.Execute(), "context detection has been cancelled"))
+		assert.Check(t, is.Contains(cli.OutBuffer().String(), "Create contexts for the detected daemons (rootless, podman)? [y/N]"))
+		_, err := cli.ContextStore().GetMetadata("rootless")
+		assert.Check(t, err != nil)
+	})
+
+	t.Run("confirmed", func(t *testing.T) {
+		cli := makeFakeCli(t)
+		cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader("y\n"))))
+		cmd := newDetectCommand(cli)
+		cmd.SetArgs([]string{})
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		assert.NilError(t, cmd.Execute())
+		assert.Check(t, is.Equal(contextHost(t, cli, "rootless"), rootless))
+		assert.Check(t, is.Equal(contextHost(t, cli, "podman"), podman))
+	})
+}
+
+func TestDetectNothingFound(t *testing.T) {
+	setDetectCandidates(t, []detectCandidate{
+		{name: "rootless", host: "unix://" + filepath.Join(t.TempDir(), "docker.sock"), description: "Docker Engine (rootless)"},
+	})
+
+	cli := makeFakeCli(t)
+	cmd := newDetectCommand(cli)
+	cmd.SetArgs([]string{"--yes"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), ""))
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), "No daemons detected\n"))
+}
@@ -4,10 +4,12 @@
 package context
 
 import (
+	"context"
 	"errors"
 
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/inspect"
+	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/context/store"
 	flagsHelper "github.com/docker/cli/cli/flags"
 	"github.com/spf13/cobra"
@@ -33,7 +35,7 @@ func newInspectCommand(dockerCLI command.Cli) *cobra.Command {
 				}
 				opts.refs = []string{dockerCLI.CurrentContext()}
 			}
-			return runInspect(dockerCLI, opts)
+			return runInspect(cmd.Context(), dockerCLI, opts)
 		},
 		ValidArgsFunction:     completeContextNames(dockerCLI, -1, false),
 		DisableFlagsInUseLine: true,
@@ -44,7 +46,7 @@ func newInspectCommand(dockerCLI command.Cli) *cobra.Command {
 	return cmd
 }
 
-func runInspect(dockerCli command.Cli, opts inspectOptions) error {
+func runInspect(ctx context.Context, dockerCli command.Cli, opts inspectOptions) error {
 	getRefFunc := func(ref string) (any, []byte, error) {
 		c, err := dockerCli.ContextStore().GetMetadata(ref)
 		if err != nil {
@@ -54,17 +56,46 @@ func runInspect(dockerCli command.Cli, opts inspectOptions) error {
 		if err != nil {
 			return nil, nil, err
 		}
+		selected, err := selectedEndpoint(ctx, dockerCli.ContextStore(), c)
+		if err != nil {
+			return nil, nil, err
+		}
 		return contextWithTLSListing{
-			Metadata:    c,
-			TLSMaterial: tlsListing,
-			Storage:     dockerCli.ContextStore().GetStorageInfo(ref),
+			Metadata:         c,
+			TLSMaterial:      tlsListing,
+			Storage:          dockerCli.ContextStore().GetStorageInfo(ref),
+			SelectedEndpoint: selected,
 		}, nil, nil
 	}
 	return inspect.Inspect(dockerCli.Out(), opts.refs, opts.format, getRefFunc)
 }
 
+// selectedEndpoint returns the host of the endpoint that's selected for a
+// context that has failover endpoints. It returns an empty string for
+// contexts without failover endpoints. It doesn't record the selection,
+// so inspecting a context doesn't affect the round-robin policy.
+func selectedEndpoint(ctx context.Context, s store.Reader, c store.Metadata) (string, error) {
+	if _, ok, err := docker.FailoverFromContext(c); err != nil || !ok {
+		return "", err
+	}
+	epMeta, err := docker.EndpointFromContext(c)
+	if err != nil {
+		return "", err
+	}
+	ep, err := docker.WithTLSData(s, c.Name, epMeta)
+	if err != nil {
+		return "", err
+	}
+	selected, err := ep.Peek(ctx)
+	if err != nil {
+		return "", err
+	}
+	return selected.Host, nil
+}
+
 type contextWithTLSListing struct {
 	store.Metadata
-	TLSMaterial map[string]store.EndpointFiles
-	Storage     store.StorageInfo
+	TLSMaterial      map[string]store.EndpointFiles
+	Storage          store.StorageInfo
+	SelectedEndpoint string `json:",omitempty"`
 }
@@ -1,6 +1,7 @@
 package context
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -14,7 +15,7 @@ func TestInspect(t *testing.T) {
 		"MyCustomMetadata": "MyCustomMetadataValue",
 	})
 	cli.OutBuffer().Reset()
-	assert.NilError(t, runInspect(cli, inspectOptions{
+	assert.NilError(t, runInspect(context.Background(), cli, inspectOptions{
 		refs: []string{"current"},
 	}))
 	expected := string(golden.Get(t, "inspect.golden"))
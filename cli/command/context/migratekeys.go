@@ -0,0 +1,88 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/spf13/cobra"
+)
+
+const (
+	keysToCredentialStore = "credential-store"
+	keysToFile            = "file"
+)
+
+// migrateKeysOptions are the options used to migrate the TLS keys of contexts.
+type migrateKeysOptions struct {
+	to  string
+	all bool
+}
+
+func newMigrateKeysCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts migrateKeysOptions
+	cmd := &cobra.Command{
+		Use:   "migrate-keys [OPTIONS] [CONTEXT...]",
+		Short: "Move the TLS keys of contexts into or out of the credentials store",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if opts.all && len(args) > 0 {
+				return errors.New("cannot specify contexts when using the --all flag")
+			}
+			if !opts.all {
+				return cli.RequiresMinArgs(1)(cmd, args)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.all {
+				names, err := store.Names(dockerCLI.ContextStore())
+				if err != nil {
+					return err
+				}
+				for _, name := range names {
+					if name != command.DefaultContextName {
+						args = append(args, name)
+					}
+				}
+			}
+			return runMigrateKeys(dockerCLI, opts, args)
+		},
+		ValidArgsFunction:     completeContextNames(dockerCLI, -1, false),
+		DisableFlagsInUseLine: true,
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.to, "to", keysToCredentialStore, `Where to store the TLS keys ("credential-store", "file")`)
+	flags.BoolVarP(&opts.all, "all", "a", false, "Migrate the TLS keys of all contexts")
+	_ = cmd.RegisterFlagCompletionFunc("to", cobra.FixedCompletions([]string{keysToCredentialStore, keysToFile}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// runMigrateKeys moves the TLS keys of one or more contexts into or out of
+// the credentials store.
+func runMigrateKeys(dockerCLI command.Cli, opts migrateKeysOptions, names []string) error {
+	var toKeyStore bool
+	switch opts.to {
+	case keysToCredentialStore:
+		toKeyStore = true
+	case keysToFile:
+	default:
+		return fmt.Errorf("invalid value for --to: %q: must be %q or %q", opts.to, keysToCredentialStore, keysToFile)
+	}
+	migrator, ok := dockerCLI.ContextStore().(store.KeyMigrator)
+	if !ok {
+		return errors.New("context store does not support storing TLS keys in the credentials store")
+	}
+	var errs []error
+	for _, name := range names {
+		if name == command.DefaultContextName {
+			errs = append(errs, errors.New(`context "default" cannot be migrated`))
+		} else if err := migrator.MigrateTLSKeys(name, toKeyStore); err != nil {
+			errs = append(errs, err)
+		} else {
+			_, _ = fmt.Fprintln(dockerCLI.Out(), name)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,102 @@
+package context
+
+import (
+	"io"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/store"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type fakeKeyStore map[string][]byte
+
+func (s fakeKeyStore) Get(ref string) ([]byte, error) {
+	key, ok := s[ref]
+	if !ok {
+		return nil, errdefs.ErrNotFound
+	}
+	return key, nil
+}
+
+func (s fakeKeyStore) Store(ref string, key []byte) error {
+	s[ref] = key
+	return nil
+}
+
+func (s fakeKeyStore) Erase(ref string) error {
+	delete(s, ref)
+	return nil
+}
+
+func TestMigrateKeys(t *testing.T) {
+	cli := makeFakeCli(t)
+	keys := fakeKeyStore{}
+	cli.ContextStore().(*command.ContextStoreWithDefault).Store.(*store.ContextStore).SetKeyStore(keys, false)
+	assert.NilError(t, cli.ContextStore().CreateOrUpdate(store.Metadata{
+		Name:      "test",
+		Endpoints: map[string]any{docker.DockerEndpoint: docker.EndpointMeta{Host: "tcp://example.com:2376"}},
+		Metadata:  command.DockerContext{},
+	}))
+	assert.NilError(t, cli.ContextStore().ResetTLSMaterial("test", &store.ContextTLSData{
+		Endpoints: map[string]store.EndpointTLSData{
+			docker.DockerEndpoint: {Files: map[string][]byte{"key.pem": []byte("key")}},
+		},
+	}))
+
+	cmd := newMigrateKeysCommand(cli)
+	cmd.SetArgs([]string{"--all"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "test\n"))
+	assert.Check(t, is.DeepEqual(keys, fakeKeyStore{"docker-context://test/docker": []byte("key")}))
+
+	cli.OutBuffer().Reset()
+	cmd = newMigrateKeysCommand(cli)
+	cmd.SetArgs([]string{"--to", "file", "test"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Len(keys, 0))
+	data, err := cli.ContextStore().GetTLSData("test", docker.DockerEndpoint, "key.pem")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "key"))
+}
+
+func TestMigrateKeysErrors(t *testing.T) {
+	tests := []struct {
+		doc         string
+		args        []string
+		expectedErr string
+	}{
+		{
+			doc:         "no contexts",
+			args:        []string{},
+			expectedErr: "requires at least 1 argument",
+		},
+		{
+			doc:         "contexts with --all",
+			args:        []string{"--all", "test"},
+			expectedErr: "cannot specify contexts when using the --all flag",
+		},
+		{
+			doc:         "invalid destination",
+			args:        []string{"--to", "somewhere", "test"},
+			expectedErr: `invalid value for --to: "somewhere": must be "credential-store" or "file"`,
+		},
+		{
+			doc:         "default context",
+			args:        []string{"default"},
+			expectedErr: `context "default" cannot be migrated`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			cmd := newMigrateKeysCommand(makeFakeCli(t))
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedErr))
+		})
+	}
+}
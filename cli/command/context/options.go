@@ -1,10 +1,20 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
 package context
 
 import (
+	"encoding/csv"
 	"errors"
 	"fmt"
+	"maps"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
 
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/types"
 	"github.com/docker/cli/cli/context"
 	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/context/store"
@@ -18,6 +28,7 @@
 	keyCert          = "cert"
 	keyKey           = "key"
 	keySkipTLSVerify = "skip-tls-verify"
+	keyProxy         = "proxy"
 )
 
 type configKeyDescription struct {
@@ -33,6 +44,7 @@ type configKeyDescription struct {
 		keyCert:          {},
 		keyKey:           {},
 		keySkipTLSVerify: {},
+		keyProxy:         {},
 	}
 	dockerConfigKeysDescriptions = []configKeyDescription{
 		{
@@ -59,6 +71,10 @@ type configKeyDescription struct {
 			name:        keySkipTLSVerify,
 			description: "Skip TLS certificate validation",
 		},
+		{
+			name:        keyProxy,
+			description: "URL of the HTTP or SOCKS5 proxy to connect through",
+		},
 	}
 )
 
@@ -98,7 +114,11 @@ func getDockerEndpoint(contextStore store.Reader, config map[string]string) (doc
 			return docker.Endpoint{}, err
 		}
 		if ep, ok := metadata.Endpoints[docker.DockerEndpoint].(docker.EndpointMeta); ok {
-			return docker.Endpoint{EndpointMeta: ep}, nil
+			proxies, err := docker.ProxyFromContext(metadata)
+			if err != nil {
+				return docker.Endpoint{}, err
+			}
+			return docker.Endpoint{EndpointMeta: ep, Proxy: proxies.Proxy}, nil
 		}
 		return docker.Endpoint{}, fmt.Errorf("unable to get endpoint from context %q", contextName)
 	}
@@ -115,6 +135,7 @@ func getDockerEndpoint(contextStore store.Reader, config map[string]string) (doc
 			Host:          config[keyHost],
 			SkipTLSVerify: skipTLSVerify,
 		},
+		Proxy:   config[keyProxy],
 		TLSData: tlsData,
 	}
 	// try to resolve a docker client, validating the configuration
@@ -129,10 +150,153 @@ func getDockerEndpoint(contextStore store.Reader, config map[string]string) (doc
 	return ep, nil
 }
 
-func getDockerEndpointMetadataAndTLS(contextStore store.Reader, config map[string]string) (docker.EndpointMeta, *store.EndpointTLSData, error) {
+func getDockerEndpointAndTLS(contextStore store.Reader, config map[string]string) (docker.Endpoint, *store.EndpointTLSData, error) {
 	ep, err := getDockerEndpoint(contextStore, config)
 	if err != nil {
-		return docker.EndpointMeta{}, nil, err
+		return docker.Endpoint{}, nil, err
+	}
+	return ep, ep.TLSData.ToStoreTLSData(), nil
+}
+
+// setProxies sets the proxy endpoint of the context, or removes it if none
+// of the endpoints use a proxy.
+func setProxies(c *store.Metadata, proxies docker.ProxyMeta) {
+	if proxies.IsEmpty() {
+		delete(c.Endpoints, docker.ProxyEndpoint)
+		return
+	}
+	c.Endpoints[docker.ProxyEndpoint] = proxies
+}
+
+// parseEndpointConfig parses a comma-separated list of key=value pairs, in
+// the same format as the "--docker" flag.
+func parseEndpointConfig(value string) (map[string]string, error) {
+	r := csv.NewReader(strings.NewReader(value))
+	fields, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	config := make(map[string]string, len(fields))
+	for _, field := range fields {
+		k, v, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s must be formatted as key=value", field)
+		}
+		config[k] = v
+	}
+	return config, nil
+}
+
+// formatEndpointConfig formats the endpoint configuration as a comma-separated
+// list of key=value pairs; it's the reverse of [parseEndpointConfig].
+func formatEndpointConfig(config map[string]string) string {
+	fields := make([]string, 0, len(config))
+	for _, k := range slices.Sorted(maps.Keys(config)) {
+		fields = append(fields, k+"="+config[k])
+	}
+	var buf strings.Builder
+	w := csv.NewWriter(&buf)
+	_ = w.Write(fields)
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// extractProxyCredentials removes the password of the proxy from the
+// endpoint configuration, so that it's not kept in the metadata of the
+// context. It returns the credentials to store in the credentials store,
+// or nil if the proxy has no password.
+func extractProxyCredentials(config map[string]string) (*types.AuthConfig, error) {
+	if config[keyProxy] == "" {
+		return nil, nil
+	}
+	u, err := docker.ParseProxyURL(config[keyProxy])
+	if err != nil {
+		return nil, err
+	}
+	password, ok := u.User.Password()
+	if !ok {
+		return nil, nil
+	}
+	key := docker.ProxyCredentialsKey(u)
+	creds := &types.AuthConfig{
+		ServerAddress: key,
+		Username:      u.User.Username(),
+		Password:      password,
+	}
+	u.User = url.User(u.User.Username())
+	config[keyProxy] = u.String()
+	return creds, nil
+}
+
+// extractEndpointsProxyCredentials removes the passwords of the proxies of
+// the docker endpoint and the failover endpoints from their configuration.
+// It returns the credentials to store with [storeProxyCredentials] once the
+// context is written.
+func extractEndpointsProxyCredentials(endpoint map[string]string, failover []string) ([]types.AuthConfig, error) {
+	var creds []types.AuthConfig
+	c, err := extractProxyCredentials(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if c != nil {
+		creds = append(creds, *c)
+	}
+	for i, value := range failover {
+		if value == "" {
+			continue
+		}
+		config, err := parseEndpointConfig(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid failover endpoint %q: %w", value, err)
+		}
+		c, err := extractProxyCredentials(config)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			creds = append(creds, *c)
+			failover[i] = formatEndpointConfig(config)
+		}
+	}
+	return creds, nil
+}
+
+// storeProxyCredentials stores the credentials of proxies in the
+// credentials store.
+func storeProxyCredentials(configFile *configfile.ConfigFile, creds []types.AuthConfig) error {
+	for _, c := range creds {
+		if err := configFile.GetCredentialsStore(c.ServerAddress).Store(c); err != nil {
+			return fmt.Errorf("failed to store proxy credentials: %w", err)
+		}
+	}
+	return nil
+}
+
+// getFailoverMetadataAndTLS returns the metadata of the failover endpoint
+// for the given endpoint configurations, the proxies of the endpoints, in
+// the same order, and the TLS data of each endpoint, keyed by the endpoint
+// name under which it's stored.
+func getFailoverMetadataAndTLS(contextStore store.Reader, endpoints []string, policy string) (docker.FailoverMeta, []string, map[string]*store.EndpointTLSData, error) {
+	if err := docker.ValidatePolicy(policy); err != nil {
+		return docker.FailoverMeta{}, nil, nil, err
+	}
+	failover := docker.FailoverMeta{Policy: policy}
+	var proxies []string
+	tlsData := make(map[string]*store.EndpointTLSData)
+	for _, value := range endpoints {
+		config, err := parseEndpointConfig(value)
+		if err != nil {
+			return docker.FailoverMeta{}, nil, nil, fmt.Errorf("invalid failover endpoint %q: %w", value, err)
+		}
+		ep, epTLS, err := getDockerEndpointAndTLS(contextStore, config)
+		if err != nil {
+			return docker.FailoverMeta{}, nil, nil, fmt.Errorf("invalid failover endpoint %q: %w", value, err)
+		}
+		if epTLS != nil {
+			tlsData[docker.FailoverTLSEndpoint(len(failover.Endpoints))] = epTLS
+		}
+		failover.Endpoints = append(failover.Endpoints, ep.EndpointMeta)
+		proxies = append(proxies, ep.Proxy)
 	}
-	return ep.EndpointMeta, ep.TLSData.ToStoreTLSData(), nil
+	return failover, proxies, tlsData, nil
 }
@@ -10,20 +10,41 @@
 
 // newShowCommand creates a new cobra.Command for `docker context sow`
 func newShowCommand(dockerCLI command.Cli) *cobra.Command {
+	var verbose bool
 	cmd := &cobra.Command{
-		Use:   "show",
+		Use:   "show [OPTIONS]",
 		Short: "Print the name of the current context",
 		Args:  cli.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if verbose {
+				runShowVerbose(dockerCLI)
+				return nil
+			}
 			runShow(dockerCLI)
 			return nil
 		},
 		ValidArgsFunction:     cobra.NoFileCompletions,
 		DisableFlagsInUseLine: true,
 	}
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print the source that selected the current context")
 	return cmd
 }
 
 func runShow(dockerCli command.Cli) {
 	fmt.Fprintln(dockerCli.Out(), dockerCli.CurrentContext())
 }
+
+// contextSourcer is implemented by CLIs that can describe the source
+// that selected the current context.
+type contextSourcer interface {
+	CurrentContextSource() string
+}
+
+func runShowVerbose(dockerCLI command.Cli) {
+	runShow(dockerCLI)
+	source := "unknown"
+	if cs, ok := dockerCLI.(contextSourcer); ok && cs.CurrentContextSource() != "" {
+		source = cs.CurrentContextSource()
+	}
+	_, _ = fmt.Fprintln(dockerCLI.Out(), "Source:", source)
+}
@@ -1,8 +1,11 @@
 package context
 
 import (
+	"io"
 	"testing"
 
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
 )
 
@@ -15,3 +18,18 @@ func TestShow(t *testing.T) {
 	runShow(cli)
 	golden.Assert(t, cli.OutBuffer().String(), "show.golden")
 }
+
+func TestShowVerbose(t *testing.T) {
+	cli := makeFakeCli(t)
+	createTestContext(t, cli, "current", nil)
+	cli.SetCurrentContext("current")
+	cli.SetCurrentContextSource("/home/me/project/.docker-context")
+
+	cli.OutBuffer().Reset()
+	cmd := newShowCommand(cli)
+	cmd.SetArgs([]string{"--verbose"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "current\nSource: /home/me/project/.docker-context\n"))
+}
@@ -1,8 +1,12 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
 package context
 
 import (
 	"bytes"
 	"fmt"
+	"slices"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -14,8 +18,10 @@
 
 // updateOptions are the options used to update a context.
 type updateOptions struct {
-	description string
-	endpoint    map[string]string
+	description    string
+	endpoint       map[string]string
+	failover       []string
+	failoverPolicy string
 }
 
 func longUpdateDescription() string {
@@ -47,6 +53,9 @@ func newUpdateCommand(dockerCLI command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.StringVar(&opts.description, "description", "", "Description of the context")
 	flags.StringToStringVar(&opts.endpoint, "docker", nil, "set the docker endpoint")
+	flags.StringArrayVar(&opts.failover, "failover", nil, "Set the docker endpoints to fail over to, in the same format as --docker; an empty value removes them")
+	flags.StringVar(&opts.failoverPolicy, "failover-policy", "", `Policy to select an endpoint ("first", "latency", "round-robin")`)
+	_ = cmd.RegisterFlagCompletionFunc("failover-policy", cobra.FixedCompletions(docker.Policies, cobra.ShellCompDirectiveNoFileComp))
 	return cmd
 }
 
@@ -60,6 +69,10 @@ func runUpdate(dockerCLI command.Cli, name string, opts updateOptions) error {
 	if err != nil {
 		return err
 	}
+	proxyCreds, err := extractEndpointsProxyCredentials(opts.endpoint, opts.failover)
+	if err != nil {
+		return err
+	}
 	dockerContext, err := command.GetDockerContext(c)
 	if err != nil {
 		return err
@@ -73,13 +86,22 @@ func runUpdate(dockerCLI command.Cli, name string, opts updateOptions) error {
 	tlsDataToReset := make(map[string]*store.EndpointTLSData)
 
 	if opts.endpoint != nil {
-		dockerEP, dockerTLS, err := getDockerEndpointMetadataAndTLS(s, opts.endpoint)
+		dockerEP, dockerTLS, err := getDockerEndpointAndTLS(s, opts.endpoint)
 		if err != nil {
 			return fmt.Errorf("unable to create docker endpoint config: %w", err)
 		}
-		c.Endpoints[docker.DockerEndpoint] = dockerEP
+		proxies, err := docker.ProxyFromContext(c)
+		if err != nil {
+			return err
+		}
+		proxies.Proxy = dockerEP.Proxy
+		c.Endpoints[docker.DockerEndpoint] = dockerEP.EndpointMeta
+		setProxies(&c, proxies)
 		tlsDataToReset[docker.DockerEndpoint] = dockerTLS
 	}
+	if err := updateFailover(s, &c, opts, tlsDataToReset); err != nil {
+		return err
+	}
 	if err := validateEndpoints(c); err != nil {
 		return err
 	}
@@ -91,12 +113,76 @@ func runUpdate(dockerCLI command.Cli, name string, opts updateOptions) error {
 			return err
 		}
 	}
+	if err := storeProxyCredentials(dockerCLI.ConfigFile(), proxyCreds); err != nil {
+		return err
+	}
 
 	_, _ = fmt.Fprintln(dockerCLI.Out(), name)
 	_, _ = fmt.Fprintf(dockerCLI.Err(), "Successfully updated context %q\n", name)
 	return nil
 }
 
+// updateFailover updates the failover endpoints of the context, if set in
+// the options, and adds the TLS data to reset to tlsDataToReset.
+func updateFailover(s store.Reader, c *store.Metadata, opts updateOptions, tlsDataToReset map[string]*store.EndpointTLSData) error {
+	current, hasFailover, err := docker.FailoverFromContext(*c)
+	if err != nil {
+		return err
+	}
+	if opts.failover == nil {
+		if opts.failoverPolicy == "" {
+			return nil
+		}
+		if !hasFailover {
+			return fmt.Errorf("context %q has no failover endpoints", c.Name)
+		}
+		if err := docker.ValidatePolicy(opts.failoverPolicy); err != nil {
+			return err
+		}
+		current.Policy = opts.failoverPolicy
+		c.Endpoints[docker.FailoverEndpoint] = current
+		return nil
+	}
+
+	// Remove the TLS data of the current failover endpoints.
+	tlsFiles, err := s.ListTLSFiles(c.Name)
+	if err != nil {
+		return err
+	}
+	for ep := range tlsFiles {
+		if docker.IsFailoverTLSEndpoint(ep) {
+			tlsDataToReset[ep] = nil
+		}
+	}
+
+	proxies, err := docker.ProxyFromContext(*c)
+	if err != nil {
+		return err
+	}
+	endpoints := slices.DeleteFunc(slices.Clone(opts.failover), func(v string) bool { return v == "" })
+	if len(endpoints) == 0 {
+		delete(c.Endpoints, docker.FailoverEndpoint)
+		proxies.Failover = nil
+		setProxies(c, proxies)
+		return nil
+	}
+	policy := opts.failoverPolicy
+	if policy == "" {
+		policy = current.Policy
+	}
+	failover, failoverProxies, failoverTLS, err := getFailoverMetadataAndTLS(s, endpoints, policy)
+	if err != nil {
+		return fmt.Errorf("unable to create failover endpoint config: %w", err)
+	}
+	c.Endpoints[docker.FailoverEndpoint] = failover
+	proxies.Failover = failoverProxies
+	setProxies(c, proxies)
+	for ep, tlsData := range failoverTLS {
+		tlsDataToReset[ep] = tlsData
+	}
+	return nil
+}
+
 func validateEndpoints(c store.Metadata) error {
 	_, err := command.GetDockerContext(c)
 	return err
@@ -60,3 +60,77 @@ func TestUpdateInvalidDockerHost(t *testing.T) {
 	})
 	assert.ErrorContains(t, err, "unable to parse docker host")
 }
+
+func TestUpdateFailover(t *testing.T) {
+	cli := makeFakeCli(t)
+	createTestContext(t, cli, "test", nil)
+
+	err := runUpdate(cli, "test", updateOptions{failoverPolicy: docker.PolicyRoundRobin})
+	assert.Check(t, is.Error(err, `context "test" has no failover endpoints`))
+
+	assert.NilError(t, runUpdate(cli, "test", updateOptions{
+		failover: []string{"host=tcp://build-2:2375", "host=tcp://build-3:2375"},
+	}))
+	assert.NilError(t, runUpdate(cli, "test", updateOptions{failoverPolicy: docker.PolicyRoundRobin}))
+	c, err := cli.ContextStore().GetMetadata("test")
+	assert.NilError(t, err)
+	failover, ok, err := docker.FailoverFromContext(c)
+	assert.NilError(t, err)
+	assert.Check(t, ok)
+	assert.Check(t, is.Equal(failover.Policy, docker.PolicyRoundRobin))
+	assert.Check(t, is.Len(failover.Endpoints, 2))
+
+	// Replacing the endpoints preserves the policy.
+	assert.NilError(t, runUpdate(cli, "test", updateOptions{
+		failover: []string{"host=tcp://build-4:2375"},
+	}))
+	c, err = cli.ContextStore().GetMetadata("test")
+	assert.NilError(t, err)
+	failover, _, err = docker.FailoverFromContext(c)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(failover, docker.FailoverMeta{
+		Policy:    docker.PolicyRoundRobin,
+		Endpoints: []docker.EndpointMeta{{Host: "tcp://build-4:2375"}},
+	}))
+
+	assert.NilError(t, runUpdate(cli, "test", updateOptions{failover: []string{""}}))
+	c, err = cli.ContextStore().GetMetadata("test")
+	assert.NilError(t, err)
+	_, ok = c.Endpoints[docker.FailoverEndpoint]
+	assert.Check(t, !ok)
+}
+
+func TestUpdateProxy(t *testing.T) {
+	cli := makeFakeCli(t)
+	createTestContext(t, cli, "test", nil)
+
+	assert.NilError(t, runUpdate(cli, "test", updateOptions{
+		endpoint: map[string]string{keyHost: "tcp://build-1:2375", keyProxy: "http://proxy.example.com:3128"},
+		failover: []string{"host=tcp://build-2:2375,proxy=socks5://socks.example.com:1080"},
+	}))
+	c, err := cli.ContextStore().GetMetadata("test")
+	assert.NilError(t, err)
+	proxies, err := docker.ProxyFromContext(c)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(proxies, docker.ProxyMeta{
+		Proxy:    "http://proxy.example.com:3128",
+		Failover: []string{"socks5://socks.example.com:1080"},
+	}))
+
+	// Removing the failover endpoints removes their proxies.
+	assert.NilError(t, runUpdate(cli, "test", updateOptions{failover: []string{""}}))
+	c, err = cli.ContextStore().GetMetadata("test")
+	assert.NilError(t, err)
+	proxies, err = docker.ProxyFromContext(c)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(proxies, docker.ProxyMeta{Proxy: "http://proxy.example.com:3128"}))
+
+	// Setting an endpoint without a proxy removes the proxy endpoint.
+	assert.NilError(t, runUpdate(cli, "test", updateOptions{
+		endpoint: map[string]string{keyHost: "tcp://build-1:2375"},
+	}))
+	c, err = cli.ContextStore().GetMetadata("test")
+	assert.NilError(t, err)
+	_, ok := c.Endpoints[docker.ProxyEndpoint]
+	assert.Check(t, !ok)
+}
@@ -3,39 +3,65 @@
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/context/store"
 	"github.com/moby/moby/client"
 	"github.com/spf13/cobra"
 )
 
+// shellFormats are the formats supported by "docker context use --shell".
+var shellFormats = map[string]string{
+	"sh":         "export %s=%s\n",
+	"fish":       "set -gx %s %s\n",
+	"powershell": "$Env:%s = %s\n",
+}
+
 func newUseCommand(dockerCLI command.Cli) *cobra.Command {
+	var shell string
 	cmd := &cobra.Command{
-		Use:   "use CONTEXT",
+		Use:   "use [OPTIONS] CONTEXT",
 		Short: "Set the default docker context",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
+			if cmd.Flags().Changed("shell") {
+				return runUseShell(dockerCLI, name, shell)
+			}
 			return runUse(dockerCLI, name)
 		},
 		ValidArgsFunction:     completeContextNames(dockerCLI, 1, false),
 		DisableFlagsInUseLine: true,
 	}
+	flags := cmd.Flags()
+	flags.StringVar(&shell, "shell", "", `Print a command to set the context for the current shell only, instead of setting the default context ("sh", "fish", "powershell")`)
+	flags.Lookup("shell").NoOptDefVal = "sh"
+	_ = cmd.RegisterFlagCompletionFunc("shell", cobra.FixedCompletions([]string{"sh", "fish", "powershell"}, cobra.ShellCompDirectiveNoFileComp))
 	return cmd
 }
 
+// validateContext validates that the context with the given name exists.
+func validateContext(dockerCLI command.Cli, name string) error {
+	if name == command.DefaultContextName {
+		return nil
+	}
+	if err := store.ValidateContextName(name); err != nil {
+		return err
+	}
+	_, err := dockerCLI.ContextStore().GetMetadata(name)
+	return err
+}
+
 // runUse set the current Docker context
 func runUse(dockerCLI command.Cli, name string) error {
+	if err := validateContext(dockerCLI, name); err != nil {
+		return err
+	}
 	// configValue uses an empty string for "default"
 	var configValue string
 	if name != command.DefaultContextName {
-		if err := store.ValidateContextName(name); err != nil {
-			return err
-		}
-		if _, err := dockerCLI.ContextStore().GetMetadata(name); err != nil {
-			return err
-		}
 		configValue = name
 	}
 	dockerConfig := dockerCLI.ConfigFile()
@@ -53,6 +79,47 @@ func runUse(dockerCLI command.Cli, name string) error {
 	if name != command.DefaultContextName && os.Getenv(client.EnvOverrideHost) != "" {
 		_, _ = fmt.Fprintf(dockerCLI.Err(), "Warning: %[1]s environment variable overrides the active context. "+
 			"To use %[2]q, either set the global --context flag, or unset %[1]s environment variable.\n", client.EnvOverrideHost, name)
+	} else if os.Getenv(command.EnvOverrideContext) != "" {
+		_, _ = fmt.Fprintf(dockerCLI.Err(), "Warning: %[1]s environment variable overrides the active context. "+
+			"To use %[2]q, either set the global --context flag, or unset %[1]s environment variable.\n", command.EnvOverrideContext, name)
+	} else if fn := contextFileSource(dockerCLI); fn != "" && dockerCLI.CurrentContext() != name {
+		_, _ = fmt.Fprintf(dockerCLI.Err(), "Warning: context file %[1]s overrides the active context. "+
+			"To use %[2]q, either set the global --context flag, or remove the %[1]s file.\n", fn, name)
+	}
+	return nil
+}
+
+// contextFileSource returns the path of the context file if the current
+// context was selected by a context file ([config.ContextFileName]), or
+// an empty string otherwise.
+func contextFileSource(dockerCLI command.Cli) string {
+	cs, ok := dockerCLI.(contextSourcer)
+	if !ok {
+		return ""
+	}
+	if source := cs.CurrentContextSource(); filepath.Base(source) == config.ContextFileName {
+		return source
+	}
+	return ""
+}
+
+// runUseShell prints a command to set the DOCKER_CONTEXT environment variable
+// in the given shell, which can be evaluated to use the context for the
+// current shell session only. It does not update the CLI configuration file.
+func runUseShell(dockerCLI command.Cli, name string, shell string) error {
+	format, ok := shellFormats[shell]
+	if !ok {
+		return fmt.Errorf(`invalid value for --shell: %q: must be one of "sh", "fish", or "powershell"`, shell)
+	}
+	if err := validateContext(dockerCLI, name); err != nil {
+		return err
+	}
+	// Context names are validated by the context store, and are safe to
+	// use unquoted in POSIX shells and fish.
+	value := name
+	if shell == "powershell" {
+		value = `"` + name + `"`
 	}
+	_, _ = fmt.Fprintf(dockerCLI.Out(), format, command.EnvOverrideContext, value)
 	return nil
 }
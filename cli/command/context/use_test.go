@@ -18,6 +18,12 @@
 	is "gotest.tools/v3/assert/cmp"
 )
 
+// execUseCommand runs "docker context use" for the context with the given name.
+func execUseCommand(dockerCLI command.Cli, name string) error {
+	cmd := newUseCommand(dockerCLI)
+	return cmd.RunE(cmd, []string{name})
+}
+
 func TestUse(t *testing.T) {
 	configDir := t.TempDir()
 	configFilePath := filepath.Join(configDir, "config.json")
@@ -27,7 +33,7 @@ func TestUse(t *testing.T) {
 		endpoint: map[string]string{},
 	})
 	assert.NilError(t, err)
-	assert.NilError(t, newUseCommand(cli).RunE(nil, []string{"test"}))
+	assert.NilError(t, execUseCommand(cli, "test"))
 	reloadedConfig, err := config.Load(configDir)
 	assert.NilError(t, err)
 	assert.Equal(t, "test", reloadedConfig.CurrentContext)
@@ -35,7 +41,7 @@ func TestUse(t *testing.T) {
 	// switch back to default
 	cli.OutBuffer().Reset()
 	cli.ErrBuffer().Reset()
-	assert.NilError(t, newUseCommand(cli).RunE(nil, []string{"default"}))
+	assert.NilError(t, execUseCommand(cli, "default"))
 	reloadedConfig, err = config.Load(configDir)
 	assert.NilError(t, err)
 	assert.Equal(t, "", reloadedConfig.CurrentContext)
@@ -45,7 +51,7 @@ func TestUse(t *testing.T) {
 
 func TestUseNoExist(t *testing.T) {
 	cli := makeFakeCli(t)
-	err := newUseCommand(cli).RunE(nil, []string{"test"})
+	err := execUseCommand(cli, "test")
 	assert.Check(t, is.ErrorType(err, errdefs.IsNotFound))
 }
 
@@ -72,7 +78,7 @@ func TestUseDefaultWithoutConfigFile(t *testing.T) {
 
 	cli, err := command.NewDockerCli(command.WithCombinedStreams(io.Discard))
 	assert.NilError(t, err)
-	assert.NilError(t, newUseCommand(cli).RunE(nil, []string{"default"}))
+	assert.NilError(t, execUseCommand(cli, "default"))
 
 	// Verify config-dir and -file don't exist after
 	_, err = os.Stat(configDir)
@@ -94,7 +100,7 @@ func TestUseHostOverride(t *testing.T) {
 	assert.NilError(t, err)
 
 	cli.ResetOutputBuffers()
-	err = newUseCommand(cli).RunE(nil, []string{"test"})
+	err = execUseCommand(cli, "test")
 	assert.NilError(t, err)
 	assert.Assert(t, is.Contains(
 		cli.ErrBuffer().String(),
@@ -105,12 +111,38 @@ func TestUseHostOverride(t *testing.T) {
 
 	// setting DOCKER_HOST with the default context should not print a warning
 	cli.ResetOutputBuffers()
-	err = newUseCommand(cli).RunE(nil, []string{"default"})
+	err = execUseCommand(cli, "default")
 	assert.NilError(t, err)
 	assert.Assert(t, is.Contains(cli.ErrBuffer().String(), `Current context is now "default"`))
 	assert.Equal(t, cli.OutBuffer().String(), "default\n")
 }
 
+func TestUseContextFileOverride(t *testing.T) {
+	configDir := t.TempDir()
+	testCfg := configfile.New(filepath.Join(configDir, "config.json"))
+	cli := makeFakeCli(t, withCliConfig(testCfg))
+	for _, name := range []string{"test", "from-file"} {
+		assert.NilError(t, runCreate(cli, name, createOptions{
+			endpoint: map[string]string{},
+		}))
+	}
+	contextFile := filepath.Join(t.TempDir(), config.ContextFileName)
+	cli.SetCurrentContext("from-file")
+	cli.SetCurrentContextSource(contextFile)
+
+	cli.ResetOutputBuffers()
+	assert.NilError(t, execUseCommand(cli, "test"))
+	assert.Check(t, is.Contains(
+		cli.ErrBuffer().String(),
+		`Warning: context file `+contextFile+` overrides the active context.`,
+	))
+
+	// using the context that's selected by the context file should not print a warning
+	cli.ResetOutputBuffers()
+	assert.NilError(t, execUseCommand(cli, "from-file"))
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), "Current context is now \"from-file\"\n"))
+}
+
 // An empty DOCKER_HOST used to break the 'context use' flow.
 // So we have a test with fewer fakes that tests this flow holistically.
 // https://github.com/docker/cli/issues/3667
@@ -139,7 +171,7 @@ func TestUseHostOverrideEmpty(t *testing.T) {
 	})
 	assert.NilError(t, err)
 
-	err = newUseCommand(cli).RunE(nil, []string{"test"})
+	err = execUseCommand(cli, "test")
 	assert.NilError(t, err)
 	assert.Assert(t, !is.Contains(out.String(), "Warning")().Success())
 	assert.Assert(t, is.Contains(out.String(), `Current context is now "test"`))
@@ -152,3 +184,59 @@ func TestUseHostOverrideEmpty(t *testing.T) {
 	apiclient := cli.Client()
 	assert.Equal(t, apiclient.DaemonHost(), socketPath)
 }
+
+func TestUseShell(t *testing.T) {
+	configDir := t.TempDir()
+	configFilePath := filepath.Join(configDir, "config.json")
+	testCfg := configfile.New(configFilePath)
+	cli := makeFakeCli(t, withCliConfig(testCfg))
+	err := runCreate(cli, "test", createOptions{
+		endpoint: map[string]string{},
+	})
+	assert.NilError(t, err)
+
+	tests := []struct {
+		shell    string
+		expected string
+	}{
+		{shell: "", expected: "export DOCKER_CONTEXT=test\n"},
+		{shell: "sh", expected: "export DOCKER_CONTEXT=test\n"},
+		{shell: "fish", expected: "set -gx DOCKER_CONTEXT test\n"},
+		{shell: "powershell", expected: "$Env:DOCKER_CONTEXT = \"test\"\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.shell, func(t *testing.T) {
+			cli.OutBuffer().Reset()
+			cmd := newUseCommand(cli)
+			args := []string{"--shell", "test"}
+			if tc.shell != "" {
+				args = []string{"--shell=" + tc.shell, "test"}
+			}
+			cmd.SetArgs(args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.NilError(t, cmd.Execute())
+			assert.Check(t, is.Equal(cli.OutBuffer().String(), tc.expected))
+		})
+	}
+
+	// The configuration file must not be updated.
+	_, err = os.Stat(configFilePath)
+	assert.Check(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestUseShellErrors(t *testing.T) {
+	cli := makeFakeCli(t)
+
+	cmd := newUseCommand(cli)
+	cmd.SetArgs([]string{"--shell=csh", "default"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), `invalid value for --shell: "csh": must be one of "sh", "fish", or "powershell"`))
+
+	cmd = newUseCommand(cli)
+	cmd.SetArgs([]string{"--shell", "nosuchcontext"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.ErrorType(cmd.Execute(), errdefs.IsNotFound))
+}
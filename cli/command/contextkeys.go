@@ -0,0 +1,66 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/types"
+	"github.com/docker/cli/cli/context/store"
+)
+
+// contextKeysFeature is the name of the feature in the CLI's config file
+// that enables storing the TLS private keys of new and updated contexts in
+// the configured credentials store.
+const contextKeysFeature = "context-keys-in-credential-store"
+
+// contextKeysUsername is the username under which the TLS private keys of
+// contexts are stored in the credentials store.
+const contextKeysUsername = "<context-tls-key>"
+
+// newContextStore creates a context store, that keeps TLS private keys of
+// contexts in the credentials store that's configured in configFile.
+func newContextStore(dir string, cfg store.Config, configFile *configfile.ConfigFile) *store.ContextStore {
+	s := store.New(dir, cfg)
+	if configFile != nil {
+		enabled, _ := strconv.ParseBool(configFile.Features[contextKeysFeature])
+		s.SetKeyStore(&credentialsKeyStore{configFile: configFile}, enabled)
+	}
+	return s
+}
+
+// credentialsKeyStore implements store.KeyStore using the credentials store
+// that's configured in the CLI's config file. Keys are stored as the secret
+// of credentials with the reference to the key as server address.
+type credentialsKeyStore struct {
+	configFile *configfile.ConfigFile
+}
+
+func (s *credentialsKeyStore) Get(ref string) ([]byte, error) {
+	creds, err := s.configFile.GetCredentialsStore(ref).Get(ref)
+	if err != nil {
+		return nil, err
+	}
+	if creds.Username != contextKeysUsername || creds.Password == "" {
+		return nil, fmt.Errorf("no TLS key found in credentials store for %s", ref)
+	}
+	return []byte(creds.Password), nil
+}
+
+func (s *credentialsKeyStore) Store(ref string, key []byte) error {
+	// Only store keys in a credential helper; the file store would write
+	// them to the CLI's config file.
+	if s.configFile.CredentialsStore == "" && s.configFile.CredentialHelpers[ref] == "" {
+		return errors.New("no credentials store is configured: set credsStore in the CLI's config file")
+	}
+	return s.configFile.GetCredentialsStore(ref).Store(types.AuthConfig{
+		ServerAddress: ref,
+		Username:      contextKeysUsername,
+		Password:      string(key),
+	})
+}
+
+func (s *credentialsKeyStore) Erase(ref string) error {
+	return s.configFile.GetCredentialsStore(ref).Erase(ref)
+}
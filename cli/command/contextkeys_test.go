@@ -0,0 +1,15 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestCredentialsKeyStoreRequiresHelper(t *testing.T) {
+	keys := &credentialsKeyStore{configFile: configfile.New("config.json")}
+	err := keys.Store("docker-context://test/docker", []byte("key"))
+	assert.Check(t, is.Error(err, "no credentials store is configured: set credsStore in the CLI's config file"))
+}
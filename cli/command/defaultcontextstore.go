@@ -194,6 +194,19 @@ func (s *ContextStoreWithDefault) GetTLSData(contextName, endpointName, fileName
 	return s.Store.GetTLSData(contextName, endpointName, fileName)
 }
 
+// MigrateTLSKeys implements store.KeyMigrator's MigrateTLSKeys. It is not
+// allowed for the default context and fails.
+func (s *ContextStoreWithDefault) MigrateTLSKeys(name string, toKeyStore bool) error {
+	if name == DefaultContextName {
+		return invalidParameter(errors.New("default context cannot be edited"))
+	}
+	m, ok := s.Store.(store.KeyMigrator)
+	if !ok {
+		return errors.New("context store does not support storing TLS keys in a key store")
+	}
+	return m.MigrateTLSKeys(name, toKeyStore)
+}
+
 // GetStorageInfo implements store.Store's GetStorageInfo
 func (s *ContextStoreWithDefault) GetStorageInfo(contextName string) store.StorageInfo {
 	if contextName == DefaultContextName {
@@ -25,11 +25,11 @@
 // NewBuildCacheFormat returns a Format for rendering using a Context
 func NewBuildCacheFormat(source string, quiet bool) Format {
 	switch source {
-	case TableFormatKey:
+	case TableFormatKey, CSVFormatKey:
 		if quiet {
 			return DefaultQuietFormat
 		}
-		return Format(defaultBuildCacheTableFormat)
+		return TableOrCSV(source, Format(defaultBuildCacheTableFormat))
 	case RawFormatKey:
 		if quiet {
 			return `build_cache_id: {{.ID}}`
@@ -43,7 +43,7 @@ func (p Platform) String() string {
 // NewContainerFormat returns a Format for rendering using a Context
 func NewContainerFormat(source string, quiet bool, size bool) Format {
 	switch source {
-	case TableFormatKey, "": // table formatting is the default if none is set.
+	case TableFormatKey, CSVFormatKey, "": // table formatting is the default if none is set.
 		if quiet {
 			return DefaultQuietFormat
 		}
@@ -51,7 +51,7 @@ func NewContainerFormat(source string, quiet bool, size bool) Format {
 		if size {
 			format += `\t{{.Size}}`
 		}
-		return Format(format)
+		return TableOrCSV(source, Format(format))
 	case RawFormatKey:
 		if quiet {
 			return `container_id: {{.ID}}`
@@ -239,6 +239,21 @@ func (c *ContainerContext) RunningFor() string {
 	return units.HumanDuration(time.Now().UTC().Sub(createdAt)) + " ago"
 }
 
+// SortValue implements [SortValuer] to sort by the container's creation
+// time, and size in bytes, instead of their human-readable representation.
+func (c *ContainerContext) SortValue(field string) (any, bool) {
+	switch field {
+	case "CreatedAt":
+		return time.Unix(c.c.Created, 0), true
+	case "RunningFor":
+		return time.Since(time.Unix(c.c.Created, 0)), true
+	case "Size":
+		return c.c.SizeRw, true
+	default:
+		return nil, false
+	}
+}
+
 // Platform returns a human-readable representation of the container's
 // platform if it is available.
 func (c *ContainerContext) Platform() *Platform {
@@ -293,6 +293,11 @@ func TestContainerContextWrite(t *testing.T) {
 			context:  Context{Format: NewContainerFormat("table {{.Image}}", false, false)},
 			expected: "IMAGE\nubuntu\nubuntu\n",
 		},
+		// CSV format uses the columns of the table format
+		{
+			context:  Context{Format: NewContainerFormat("csv", false, true)},
+			expected: string(golden.Get(t, "container-context-write-csv.golden")),
+		},
 		{
 			context:  Context{Format: NewContainerFormat("table {{.Image}}", false, true)},
 			expected: "IMAGE\nubuntu\nubuntu\n",
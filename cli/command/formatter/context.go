@@ -15,8 +15,8 @@ func NewClientContextFormat(source string, quiet bool) Format {
 	if quiet {
 		return quietContextFormat
 	}
-	if source == TableFormatKey {
-		return ClientContextTableFormat
+	if source == TableFormatKey || source == CSVFormatKey {
+		return TableOrCSV(source, ClientContextTableFormat)
 	}
 	return Format(source)
 }
@@ -20,9 +20,13 @@
 	RawFormatKey    = "raw"
 	PrettyFormatKey = "pretty"
 	JSONFormatKey   = "json"
+	YAMLFormatKey   = "yaml"
+	CSVFormatKey    = "csv"
+	NDJSONFormatKey = "ndjson"
 
 	DefaultQuietFormat = "{{.ID}}"
 	JSONFormat         = "{{json .}}"
+	YAMLFormat         = "{{toYaml .}}"
 )
 
 // Format is the format string rendered using the Context
@@ -38,6 +42,28 @@ func (f Format) IsJSON() bool {
 	return string(f) == JSONFormatKey
 }
 
+// IsYAML returns true if the format is the YAML format
+func (f Format) IsYAML() bool {
+	return string(f) == YAMLFormatKey
+}
+
+// IsCSV returns true if the format is a CSV-type format, either with, or
+// without a template ("csv {{.ID}}\t{{.Name}}").
+func (f Format) IsCSV() bool {
+	return string(f) == CSVFormatKey || strings.HasPrefix(string(f), CSVFormatKey+" ")
+}
+
+// TableOrCSV returns the given table format, or the CSV format with the
+// same columns if source is the CSV format without template. Formatters use
+// it for their default table format, so that "--format csv" prints the
+// columns of the table in the same order.
+func TableOrCSV(source string, tableFormat Format) Format {
+	if source != CSVFormatKey {
+		return tableFormat
+	}
+	return Format(CSVFormatKey + strings.TrimPrefix(string(tableFormat), TableFormatKey))
+}
+
 // Contains returns true if the format contains the substring
 func (f Format) Contains(sub string) bool {
 	return strings.Contains(string(f), sub)
@@ -53,14 +79,18 @@ func (f Format) templateString() string {
 		// hit this; a literal "table" here means a custom "table" format
 		// without template.
 		return ""
-	case JSONFormatKey:
+	case JSONFormatKey, NDJSONFormatKey, YAMLFormatKey, CSVFormatKey:
 		// "--format json" only; not JSON formats ("--format '{{json .Field}}'").
+		// YAML and CSV (without template) are produced from the JSON output.
 		return JSONFormat
 	}
 
 	// "--format 'table {{.Field}}\t{{.Field}}'" -> "{{.Field}}\t{{.Field}}"
 	if after, isTable := strings.CutPrefix(out, TableFormatKey); isTable {
 		out = after
+	} else if after, isCSV := strings.CutPrefix(out, CSVFormatKey+" "); isCSV {
+		// "--format 'csv {{.Field}}\t{{.Field}}'" -> "{{.Field}}\t{{.Field}}"
+		out = after
 	}
 
 	out = strings.Trim(out, " ") // trim spaces, but preserve other whitespace.
@@ -76,10 +106,16 @@ type Context struct {
 	Format Format
 	// Trunc when set to true will truncate the output of certain fields such as Container ID.
 	Trunc bool
+	// ListOptions holds the options to sort the output, and to select
+	// which columns to print.
+	ListOptions ListOptions
 
 	// internal element
 	header any
 	buffer *bytes.Buffer
+	// rows holds the rows for the CSV format without template, which are
+	// written when all rows are known.
+	rows []map[string]any
 }
 
 func (c *Context) parseFormat() (*template.Template, error) {
@@ -95,20 +131,35 @@ func (c *Context) postFormat(tmpl *template.Template, subContext SubContext) {
 	if out == nil {
 		out = io.Discard
 	}
-	if !c.Format.IsTable() {
+	switch {
+	case c.Format.IsCSV():
+		_ = c.postFormatCSV(out, tmpl, subContext)
+		return
+	case c.Format.IsYAML() && c.buffer.Len() == 0:
+		_, _ = io.WriteString(out, "[]\n")
+		return
+	case !c.Format.IsTable():
 		_, _ = c.buffer.WriteTo(out)
 		return
 	}
 
 	// Write column-headers and rows to the tab-writer buffer, then flush the output.
 	tw := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
-	_ = tmpl.Funcs(templates.HeaderFunctions).Execute(tw, subContext.FullHeader())
-	_, _ = tw.Write([]byte{'\n'})
+	if !c.ListOptions.NoHeaders {
+		_ = tmpl.Funcs(templates.HeaderFunctions).Execute(tw, subContext.FullHeader())
+		_, _ = tw.Write([]byte{'\n'})
+	}
 	_, _ = c.buffer.WriteTo(tw)
 	_ = tw.Flush()
 }
 
 func (c *Context) contextFormat(tmpl *template.Template, subContext SubContext) error {
+	switch {
+	case c.Format.IsCSV():
+		return c.contextFormatCSV(tmpl, subContext)
+	case c.Format.IsYAML():
+		return c.contextFormatYAML(tmpl, subContext)
+	}
 	if err := tmpl.Execute(c.buffer, subContext); err != nil {
 		return fmt.Errorf("template parsing error: %w", err)
 	}
@@ -122,9 +173,22 @@ func (c *Context) contextFormat(tmpl *template.Template, subContext SubContext)
 // SubFormat is a function type accepted by Write()
 type SubFormat func(func(SubContext) error) error
 
-// Write the template to the buffer using this Context
+// Write the template to the buffer using this Context. If the ListOptions
+// are set, rows are sorted, and only the selected columns are written.
 func (c *Context) Write(sub SubContext, f SubFormat) error {
 	c.buffer = &bytes.Buffer{}
+	c.rows = nil
+	if len(c.ListOptions.Columns) > 0 {
+		format, err := c.columnsFormat(sub)
+		if err != nil {
+			return err
+		}
+		c.Format = format
+	}
+	sortKeys, err := parseSortKeys(sub, c.ListOptions.Sort)
+	if err != nil {
+		return err
+	}
 	tmpl, err := c.parseFormat()
 	if err != nil {
 		return err
@@ -133,8 +197,24 @@ func (c *Context) Write(sub SubContext, f SubFormat) error {
 	subFormat := func(subContext SubContext) error {
 		return c.contextFormat(tmpl, subContext)
 	}
-	if err := f(subFormat); err != nil {
-		return err
+	if len(sortKeys) == 0 {
+		if err := f(subFormat); err != nil {
+			return err
+		}
+	} else {
+		var items []SubContext
+		if err := f(func(subContext SubContext) error {
+			items = append(items, subContext)
+			return nil
+		}); err != nil {
+			return err
+		}
+		sortSubContexts(items, sortKeys)
+		for _, item := range items {
+			if err := subFormat(item); err != nil {
+				return err
+			}
+		}
 	}
 
 	c.postFormat(tmpl, sub)
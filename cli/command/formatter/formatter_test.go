@@ -47,6 +47,26 @@ func TestFormat(t *testing.T) {
 			isTable:  true,
 			template: "{{.Field}}\t{{.Field2}}",
 		},
+		{
+			doc:      "yaml format",
+			f:        "yaml",
+			template: JSONFormat,
+		},
+		{
+			doc:      "ndjson format",
+			f:        "ndjson",
+			template: JSONFormat,
+		},
+		{
+			doc:      "csv format",
+			f:        "csv",
+			template: JSONFormat,
+		},
+		{
+			doc:      "csv with template",
+			f:        `csv {{.Field}}\t{{.Field2}}`,
+			template: "{{.Field}}\t{{.Field2}}",
+		},
 		{
 			doc:      "other format",
 			f:        "other",
@@ -104,6 +124,36 @@ func TestContext(t *testing.T) {
 			format: `table {{.Name}}`,
 			expected: `NAME
 test
+`,
+		},
+		{
+			name:   "ndjson format",
+			format: NDJSONFormatKey,
+			expected: `{"Name":"test"}
+{"Name":"test, with \"quotes\""}
+`,
+		},
+		{
+			name:   "yaml format",
+			format: YAMLFormatKey,
+			expected: `- Name: test
+- Name: test, with "quotes"
+`,
+		},
+		{
+			name:   "csv format",
+			format: CSVFormatKey,
+			expected: `NAME
+test
+"test, with ""quotes"""
+`,
+		},
+		{
+			name:   "csv format with template",
+			format: `csv {{.Name}}\t{{len .Name}}`,
+			expected: `NAME,4
+test,4
+"test, with ""quotes""",19
 `,
 		},
 	}
@@ -116,7 +166,13 @@ func TestContext(t *testing.T) {
 			}
 			subContext := fakeSubContext{Name: "test"}
 			subFormat := func(f func(sub SubContext) error) error {
-				return f(subContext)
+				if err := f(subContext); err != nil {
+					return err
+				}
+				if tc.format == JSONFormatKey || tc.format == `table {{.Name}}` {
+					return nil
+				}
+				return f(fakeSubContext{Name: `test, with "quotes"`})
 			}
 			err := ctx.Write(&subContext, subFormat)
 			assert.NilError(t, err)
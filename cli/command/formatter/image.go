@@ -35,14 +35,14 @@ func isDangling(img image.Summary) bool {
 // NewImageFormat returns a format for rendering an ImageContext
 func NewImageFormat(source string, quiet bool, digest bool) Format {
 	switch source {
-	case TableFormatKey:
+	case TableFormatKey, CSVFormatKey:
 		switch {
 		case quiet:
 			return DefaultQuietFormat
 		case digest:
-			return defaultImageTableFormatWithDigest
+			return TableOrCSV(source, defaultImageTableFormatWithDigest)
 		default:
-			return defaultImageTableFormat
+			return TableOrCSV(source, defaultImageTableFormat)
 		}
 	case RawFormatKey:
 		switch {
@@ -249,6 +249,21 @@ func (c *imageContext) Size() string {
 	return units.HumanSizeWithPrecision(float64(c.i.Size), 3)
 }
 
+// SortValue implements [SortValuer] to sort by the image's creation time,
+// and size in bytes, instead of their human-readable representation.
+func (c *imageContext) SortValue(field string) (any, bool) {
+	switch field {
+	case "CreatedAt":
+		return time.Unix(c.i.Created, 0), true
+	case "CreatedSince":
+		return time.Since(time.Unix(c.i.Created, 0)), true
+	case "Size":
+		return c.i.Size, true
+	default:
+		return nil, false
+	}
+}
+
 func (c *imageContext) Containers() string {
 	if c.i.Containers == -1 {
 		return "N/A"
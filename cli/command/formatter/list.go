@@ -0,0 +1,307 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package formatter
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/internal/formatting"
+	"github.com/docker/go-units"
+	"github.com/fvbommel/sortorder"
+	"github.com/spf13/pflag"
+)
+
+// ListOptions holds the options for list commands to sort the output, and
+// to select which columns to print.
+type ListOptions struct {
+	// Sort is the list of fields to sort by, in order of precedence. Fields
+	// can have a ":desc" suffix to sort in descending order.
+	Sort []string
+	// Columns is the list of columns to print. Columns are the fields of
+	// the table header, and can only be used for table and CSV formats.
+	Columns []string
+	// NoHeaders omits the header for table and CSV formats.
+	NoHeaders bool
+}
+
+// InstallFlags adds the "--sort", "--columns", and "--no-headers" flags.
+func (o *ListOptions) InstallFlags(flags *pflag.FlagSet) {
+	flags.StringSliceVar(&o.Sort, "sort", nil, `Sort output by the given fields ("FIELD[:desc]")`)
+	flags.StringSliceVar(&o.Columns, "columns", nil, "Only print the given columns (table and csv formats)")
+	flags.BoolVar(&o.NoHeaders, "no-headers", false, "Don't print headers (table and csv formats)")
+}
+
+// Uses returns true if the given field is used as column, or to sort the
+// output. Fields are matched case-insensitive.
+func (o ListOptions) Uses(field string) bool {
+	matches := func(s string) bool {
+		name, _, _ := strings.Cut(s, ":")
+		return strings.EqualFold(strings.TrimSpace(name), field)
+	}
+	return slices.ContainsFunc(o.Columns, matches) || slices.ContainsFunc(o.Sort, matches)
+}
+
+// SortValuer can be implemented by a SubContext to provide the value used
+// for sorting by a field, for example, if the formatted value is relative
+// ("5 minutes ago"), or otherwise cannot be compared. It returns false to
+// use the formatted value.
+type SortValuer interface {
+	SortValue(field string) (any, bool)
+}
+
+type sortKey struct {
+	field string
+	desc  bool
+}
+
+// columnsFormat returns the format for the columns to print. Columns must
+// be fields of the header of the subContext.
+func (c *Context) columnsFormat(sub SubContext) (Format, error) {
+	var prefix string
+	switch {
+	case c.Format.IsTable():
+		prefix = TableFormatKey
+	case c.Format.IsCSV():
+		prefix = CSVFormatKey
+	default:
+		return "", errors.New(`--columns can only be used with "table" or "csv" format`)
+	}
+	var fields []string
+	switch h := sub.FullHeader().(type) {
+	case SubHeaderContext:
+		fields = slices.Sorted(maps.Keys(h))
+	case map[string]string:
+		fields = slices.Sorted(maps.Keys(h))
+	}
+	columns := make([]string, 0, len(c.ListOptions.Columns))
+	for _, col := range c.ListOptions.Columns {
+		col = strings.TrimSpace(col)
+		i := slices.IndexFunc(fields, func(f string) bool {
+			return strings.EqualFold(f, col)
+		})
+		if i < 0 {
+			return "", fmt.Errorf("invalid column '%s': valid columns are: %s", col, strings.Join(fields, ", "))
+		}
+		columns = append(columns, "{{."+fields[i]+"}}")
+	}
+	return Format(prefix + " " + strings.Join(columns, `\t`)), nil
+}
+
+// parseSortKeys parses the fields to sort by. Fields must be fields of the
+// subContext, and are matched case-insensitive.
+func parseSortKeys(sub SubContext, fields []string) ([]sortKey, error) {
+	available := fieldNames(sub)
+	keys := make([]sortKey, 0, len(fields))
+	for _, f := range fields {
+		name, order, _ := strings.Cut(strings.TrimSpace(f), ":")
+		var desc bool
+		switch strings.ToLower(order) {
+		case "", "asc":
+		case "desc":
+			desc = true
+		default:
+			return nil, fmt.Errorf("invalid sort order '%s' for field '%s': must be 'asc' or 'desc'", order, name)
+		}
+		i := slices.IndexFunc(available, func(a string) bool {
+			return strings.EqualFold(a, name)
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("invalid sort field '%s': valid fields are: %s", name, strings.Join(available, ", "))
+		}
+		keys = append(keys, sortKey{field: available[i], desc: desc})
+	}
+	return keys, nil
+}
+
+// fieldNames returns the names of the fields of the subContext, which are
+// the methods that can be used in templates, and are included in the JSON
+// output.
+func fieldNames(sub SubContext) []string {
+	typ := reflect.TypeOf(sub)
+	var names []string
+	for i := range typ.NumMethod() {
+		m := typ.Method(i)
+		if _, ok := unmarshallableNames[m.Name]; ok {
+			continue
+		}
+		// the receiver is the first argument of the method.
+		if m.Type.NumIn() == 1 && m.Type.NumOut() == 1 {
+			names = append(names, m.Name)
+		}
+	}
+	return names
+}
+
+// sortSubContexts sorts the subContexts by the given keys. Fields are compared
+// chronologically if all values are times, numerically if all values are
+// numbers or sizes ("1.5MB"), and using natural ordering otherwise. Empty and
+// unknown ("N/A") values are sorted first.
+func sortSubContexts(items []SubContext, keys []sortKey) {
+	columns := make([][]any, len(keys))
+	for k, key := range keys {
+		values := make([]any, len(items))
+		for i, item := range items {
+			values[i] = sortValue(item, key.field)
+		}
+		columns[k] = typedValues(values)
+	}
+
+	idx := make([]int, len(items))
+	for i := range idx {
+		idx[i] = i
+	}
+	slices.SortStableFunc(idx, func(a, b int) int {
+		for k, key := range keys {
+			c := compareSortValues(columns[k][a], columns[k][b])
+			if key.desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+
+	sorted := make([]SubContext, len(items))
+	for i, n := range idx {
+		sorted[i] = items[n]
+	}
+	copy(items, sorted)
+}
+
+func sortValue(item SubContext, field string) any {
+	if sv, ok := item.(SortValuer); ok {
+		if v, ok := sv.SortValue(field); ok {
+			return v
+		}
+	}
+	m := reflect.ValueOf(item).MethodByName(field)
+	if !m.IsValid() {
+		return nil
+	}
+	return m.Call(nil)[0].Interface()
+}
+
+// typedValues converts the values of a column to times or numbers if all
+// (non-empty) values can be converted, and to strings otherwise. Empty
+// values are converted to nil.
+func typedValues(values []any) []any {
+	out := make([]any, len(values))
+	for _, conv := range []func(any) (any, bool){toSortTime, toSortNumber, toSortSize} {
+		ok := true
+		for i, v := range values {
+			if isEmptySortValue(v) {
+				out[i] = nil
+				continue
+			}
+			if out[i], ok = conv(v); !ok {
+				break
+			}
+		}
+		if ok {
+			return out
+		}
+	}
+	for i, v := range values {
+		if isEmptySortValue(v) {
+			out[i] = nil
+		} else {
+			out[i] = fmt.Sprint(v)
+		}
+	}
+	return out
+}
+
+func isEmptySortValue(v any) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		return val == "" || val == "N/A" || val == "<none>"
+	}
+	return false
+}
+
+func toSortTime(v any) (any, bool) {
+	switch val := v.(type) {
+	case time.Time:
+		return val, true
+	case string:
+		if t, err := formatting.ParseTime(val); err == nil {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+func toSortNumber(v any) (any, bool) {
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.CanInt():
+		return float64(rv.Int()), true
+	case rv.CanUint():
+		return float64(rv.Uint()), true
+	case rv.CanFloat():
+		return rv.Float(), true
+	case rv.Kind() == reflect.String:
+		if f, err := strconv.ParseFloat(rv.String(), 64); err == nil {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// toSortSize converts a human-readable size ("1.5MB") to a number. Only the
+// first word is used, to account for sizes with additional information,
+// such as the size of containers ("2B (virtual 187MB)").
+func toSortSize(v any) (any, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, false
+	}
+	s, _, _ = strings.Cut(s, " ")
+	if !strings.HasSuffix(strings.ToUpper(s), "B") {
+		return nil, false
+	}
+	size, err := units.FromHumanSize(s)
+	if err != nil {
+		return nil, false
+	}
+	return float64(size), true
+}
+
+func compareSortValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	switch va := a.(type) {
+	case time.Time:
+		return va.Compare(b.(time.Time))
+	case float64:
+		return cmp.Compare(va, b.(float64))
+	}
+	sa, sb := a.(string), b.(string)
+	switch {
+	case sa == sb:
+		return 0
+	case sortorder.NaturalLess(sa, sb):
+		return -1
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,212 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package formatter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/api/types/volume"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestListOptionsVolumes(t *testing.T) {
+	volumes := []volume.Volume{
+		{Name: "vol10", Driver: "local", UsageData: &volume.UsageData{Size: 2_000_000}},
+		{Name: "vol2", Driver: "other", UsageData: &volume.UsageData{Size: 300_000}},
+		{Name: "vol1", Driver: "local"},
+		{Name: "vol3", Driver: "local", UsageData: &volume.UsageData{Size: 1_500_000_000}},
+	}
+
+	tests := []struct {
+		doc      string
+		format   Format
+		opts     ListOptions
+		expected string
+	}{
+		{
+			doc:    "sort natural",
+			format: "{{.Name}}",
+			opts:   ListOptions{Sort: []string{"name"}},
+			expected: `vol1
+vol2
+vol3
+vol10
+`,
+		},
+		{
+			doc:    "sort desc",
+			format: "{{.Name}}",
+			opts:   ListOptions{Sort: []string{"Name:desc"}},
+			expected: `vol10
+vol3
+vol2
+vol1
+`,
+		},
+		{
+			doc:    "sort size",
+			format: "{{.Name}} {{.Size}}",
+			opts:   ListOptions{Sort: []string{"size"}},
+			expected: `vol1 N/A
+vol2 300kB
+vol10 2MB
+vol3 1.5GB
+`,
+		},
+		{
+			doc:    "multiple keys",
+			format: "{{.Driver}} {{.Name}}",
+			opts:   ListOptions{Sort: []string{"driver", "size:desc"}},
+			expected: `local vol3
+local vol10
+local vol1
+other vol2
+`,
+		},
+		{
+			doc:    "columns",
+			format: NewVolumeFormat("table", false),
+			opts:   ListOptions{Columns: []string{"name", "Size"}, Sort: []string{"name"}},
+			expected: `VOLUME NAME   SIZE
+vol1          N/A
+vol2          300kB
+vol3          1.5GB
+vol10         2MB
+`,
+		},
+		{
+			doc:    "no headers",
+			format: NewVolumeFormat("table", false),
+			opts:   ListOptions{NoHeaders: true, Sort: []string{"name"}},
+			expected: `local     vol1
+other     vol2
+local     vol3
+local     vol10
+`,
+		},
+		{
+			doc:    "csv columns no headers",
+			format: "csv",
+			opts:   ListOptions{Columns: []string{"Name", "Driver"}, NoHeaders: true, Sort: []string{"name"}},
+			expected: `vol1,local
+vol2,other
+vol3,local
+vol10,local
+`,
+		},
+		{
+			doc:    "csv columns",
+			format: "csv",
+			opts:   ListOptions{Columns: []string{"Name"}, Sort: []string{"name"}},
+			expected: `VOLUME NAME
+vol1
+vol2
+vol3
+vol10
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			out := bytes.NewBufferString("")
+			err := VolumeWrite(Context{Format: tc.format, Output: out, ListOptions: tc.opts}, volumes)
+			assert.NilError(t, err)
+			assert.Equal(t, out.String(), tc.expected)
+		})
+	}
+}
+
+func TestListOptionsErrors(t *testing.T) {
+	tests := []struct {
+		format   Format
+		opts     ListOptions
+		expected string
+	}{
+		{
+			format:   "table",
+			opts:     ListOptions{Columns: []string{"nosuchcolumn"}},
+			expected: "invalid column 'nosuchcolumn': valid columns are: Availability, Driver, Group, ID, Labels, Links, Mountpoint, Name, Scope, Size, Status",
+		},
+		{
+			format:   "{{.Name}}",
+			opts:     ListOptions{Columns: []string{"Name"}},
+			expected: `--columns can only be used with "table" or "csv" format`,
+		},
+		{
+			format:   "table",
+			opts:     ListOptions{Sort: []string{"nosuchfield"}},
+			expected: "invalid sort field 'nosuchfield': valid fields are: Availability, Driver, Group, Labels, Links, Mountpoint, Name, Scope, Size, Status",
+		},
+		{
+			format:   "table",
+			opts:     ListOptions{Sort: []string{"name:up"}},
+			expected: "invalid sort order 'up' for field 'name': must be 'asc' or 'desc'",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expected, func(t *testing.T) {
+			err := VolumeWrite(Context{Format: tc.format, Output: &bytes.Buffer{}, ListOptions: tc.opts}, nil)
+			assert.Check(t, is.Error(err, tc.expected))
+		})
+	}
+}
+
+func TestListOptionsTypedSortValues(t *testing.T) {
+	now := time.Now()
+	containers := []container.Summary{
+		{ID: "c1", Created: now.Add(-2 * time.Hour).Unix(), SizeRw: 20_000},
+		{ID: "c2", Created: now.Add(-5 * time.Minute).Unix(), SizeRw: 3_000_000},
+		{ID: "c3", Created: now.Add(-48 * time.Hour).Unix(), SizeRw: 100},
+	}
+	images := []image.Summary{
+		{ID: "i1", Created: now.Add(-2 * time.Hour).Unix(), Size: 20_000},
+		{ID: "i2", Created: now.Add(-5 * time.Minute).Unix(), Size: 3_000_000},
+		{ID: "i3", Created: now.Add(-48 * time.Hour).Unix(), Size: 100},
+	}
+
+	tests := []struct {
+		containerSort string
+		imageSort     string
+		expected      []string
+	}{
+		{containerSort: "RunningFor", imageSort: "CreatedSince", expected: []string{"2", "1", "3"}},
+		{containerSort: "CreatedAt", imageSort: "CreatedAt", expected: []string{"3", "1", "2"}},
+		{containerSort: "size:desc", imageSort: "size:desc", expected: []string{"2", "1", "3"}},
+	}
+	for _, tc := range tests {
+		t.Run("container "+tc.containerSort, func(t *testing.T) {
+			out := bytes.NewBufferString("")
+			ctx := Context{Format: "{{.ID}}", Output: out, ListOptions: ListOptions{Sort: []string{tc.containerSort}}}
+			assert.NilError(t, ContainerWrite(ctx, containers))
+			assert.Check(t, is.DeepEqual(strings.Fields(out.String()), prefixed("c", tc.expected)))
+		})
+		t.Run("image "+tc.imageSort, func(t *testing.T) {
+			out := bytes.NewBufferString("")
+			ctx := ImageContext{Context: Context{Format: "{{.ID}}", Output: out, ListOptions: ListOptions{Sort: []string{tc.imageSort}}}}
+			assert.NilError(t, ImageWrite(ctx, images))
+			assert.Check(t, is.DeepEqual(strings.Fields(out.String()), prefixed("i", tc.expected)))
+		})
+	}
+}
+
+func prefixed(prefix string, ids []string) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = prefix + id
+	}
+	return out
+}
+
+func TestListOptionsUses(t *testing.T) {
+	opts := ListOptions{Columns: []string{"ID", "names"}, Sort: []string{"size:desc"}}
+	assert.Check(t, opts.Uses("Size"))
+	assert.Check(t, opts.Uses("Names"))
+	assert.Check(t, !opts.Uses("Image"))
+}
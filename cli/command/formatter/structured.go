@@ -0,0 +1,154 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package formatter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/docker/cli/internal/formatting"
+	"github.com/docker/cli/templates"
+	"go.yaml.in/yaml/v3"
+)
+
+// executeJSON executes the template, which is expected to produce JSON, and
+// decodes the result.
+func executeJSON(tmpl *template.Template, subContext SubContext) (any, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, subContext); err != nil {
+		return nil, fmt.Errorf("template parsing error: %w", err)
+	}
+	dec := json.NewDecoder(&buf)
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// contextFormatYAML writes the subContext to the buffer as an item in a
+// YAML sequence.
+func (c *Context) contextFormatYAML(tmpl *template.Template, subContext SubContext) error {
+	v, err := executeJSON(tmpl, subContext)
+	if err != nil {
+		return err
+	}
+	out, err := yaml.Marshal([]any{formatting.YAMLValue(v)})
+	if err != nil {
+		return err
+	}
+	c.buffer.Write(out)
+	return nil
+}
+
+// contextFormatCSV writes the subContext to the buffer as a CSV record if
+// a template is used. Formatters use the columns of their table format as
+// template for the CSV format (see [TableOrCSV]). Without template, all
+// fields of the subContext are collected, and written in postFormatCSV.
+func (c *Context) contextFormatCSV(tmpl *template.Template, subContext SubContext) error {
+	if c.Format == CSVFormatKey {
+		v, err := executeJSON(tmpl, subContext)
+		if err != nil {
+			return err
+		}
+		row, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("cannot format %T as csv", v)
+		}
+		c.rows = append(c.rows, row)
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, subContext); err != nil {
+		return fmt.Errorf("template parsing error: %w", err)
+	}
+	w := csv.NewWriter(c.buffer)
+	_ = w.Write(strings.Split(buf.String(), "\t"))
+	w.Flush()
+	return w.Error()
+}
+
+// postFormatCSV writes the header row and records. Column-headers for the
+// CSV format without template are taken from the subContext's table headers
+// if present, and the field-name otherwise.
+func (c *Context) postFormatCSV(out io.Writer, tmpl *template.Template, subContext SubContext) error {
+	w := csv.NewWriter(out)
+	if c.Format != CSVFormatKey {
+		if !c.ListOptions.NoHeaders {
+			var buf bytes.Buffer
+			if err := tmpl.Funcs(templates.HeaderFunctions).Execute(&buf, subContext.FullHeader()); err == nil {
+				_ = w.Write(strings.Split(buf.String(), "\t"))
+			}
+		}
+		w.Flush()
+		_, _ = c.buffer.WriteTo(out)
+		return w.Error()
+	}
+
+	if len(c.rows) == 0 {
+		return nil
+	}
+	columns := map[string]struct{}{}
+	for _, row := range c.rows {
+		for k := range row {
+			columns[k] = struct{}{}
+		}
+	}
+	fields := slices.Sorted(maps.Keys(columns))
+
+	var headers map[string]string
+	switch h := subContext.FullHeader().(type) {
+	case SubHeaderContext:
+		headers = h
+	case map[string]string:
+		headers = h
+	}
+	record := make([]string, len(fields))
+	for i, f := range fields {
+		record[i] = f
+		if h, ok := headers[f]; ok {
+			record[i] = h
+		}
+	}
+	if !c.ListOptions.NoHeaders {
+		_ = w.Write(record)
+	}
+
+	for _, row := range c.rows {
+		for i, f := range fields {
+			record[i] = csvValue(row[f])
+		}
+		_ = w.Write(record)
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// csvValue formats a value for use in a CSV field. Strings, numbers, and
+// booleans are written as-is; other values are written as JSON.
+func csvValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case json.Number, bool:
+		return fmt.Sprint(val)
+	default:
+		out, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(out)
+	}
+}
@@ -29,11 +29,11 @@
 // NewVolumeFormat returns a format for use with a volume Context
 func NewVolumeFormat(source string, quiet bool) Format {
 	switch source {
-	case TableFormatKey:
+	case TableFormatKey, CSVFormatKey:
 		if quiet {
 			return defaultVolumeQuietFormat
 		}
-		return defaultVolumeTableFormat
+		return TableOrCSV(source, defaultVolumeTableFormat)
 	case RawFormatKey:
 		if quiet {
 			return `name: {{.Name}}`
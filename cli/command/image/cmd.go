@@ -33,6 +33,7 @@ func newImageCommand(dockerCli command.Cli) *cobra.Command {
 	}
 	cmd.AddCommand(
 		newBuildCommand(dockerCli),
+		newDiffCommand(dockerCli),
 		newHistoryCommand(dockerCli),
 		newImportCommand(dockerCli),
 		newLoadCommand(dockerCli),
@@ -40,6 +41,7 @@ func newImageCommand(dockerCli command.Cli) *cobra.Command {
 		newPushCommand(dockerCli),
 		newSaveCommand(dockerCli),
 		newTagCommand(dockerCli),
+		newTransferCommand(dockerCli),
 		newListCommand(dockerCli),
 		newImageRemoveCommand(dockerCli),
 		newInspectCommand(dockerCli),
@@ -0,0 +1,332 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/containerd/platforms"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	"github.com/docker/cli/templates"
+	"github.com/moby/moby/client"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+)
+
+type diffOptions struct {
+	images   [2]string
+	platform string
+	format   string
+	noFiles  bool
+	noTrunc  bool
+}
+
+// Change types used for config, layer, and file changes. These match the
+// symbols used by "docker container diff".
+const (
+	changeAdded    = "A"
+	changeDeleted  = "D"
+	changeModified = "C"
+	changeShared   = "="
+)
+
+// imageDiff is the result of comparing two images.
+type imageDiff struct {
+	Images [2]string
+	Config []configChange
+	Layers []layerChange
+	Files  []fileChange `json:",omitempty"`
+}
+
+// configChange describes a difference in the image config. Key is set for
+// fields that hold multiple values (such as Env and Labels), and identifies
+// the value that was changed.
+type configChange struct {
+	Type  string
+	Field string
+	Key   string `json:",omitempty"`
+	Old   string `json:",omitempty"`
+	New   string `json:",omitempty"`
+}
+
+// layerChange describes a layer that is either shared between both images,
+// only present in the first image (deleted), or only in the second (added).
+type layerChange struct {
+	Type   string
+	Digest string
+}
+
+// fileChange describes a change to a file or directory in the image's
+// filesystem.
+type fileChange struct {
+	Type string
+	Path string
+}
+
+// newDiffCommand creates a new "docker image diff" command.
+func newDiffCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts diffOptions
+
+	cmd := &cobra.Command{
+		Use:   "diff [OPTIONS] IMAGE IMAGE",
+		Short: "Show differences between two images",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.images = [2]string{args[0], args[1]}
+			return runDiff(cmd.Context(), dockerCLI, opts)
+		},
+		ValidArgsFunction:     completion.ImageNames(dockerCLI, 2),
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.format, "format", "f", "", `Format output using a custom template:
+'json':             Print in JSON format
+'TEMPLATE':         Print output using the given Go template.
+Refer to https://docs.docker.com/go/formatting/ for more information about formatting output with templates`)
+	flags.BoolVar(&opts.noFiles, "no-files", false, "Don't compare the filesystem of layers that differ")
+	flags.BoolVar(&opts.noTrunc, "no-trunc", false, "Don't truncate output")
+	flags.StringVar(&opts.platform, "platform", "", `Compare a specific platform of multi-platform images. Formatted as "os[/arch[/variant]]" (e.g., "linux/amd64")`)
+	_ = flags.SetAnnotation("platform", "version", []string{"1.49"})
+
+	_ = cmd.RegisterFlagCompletionFunc("platform", completion.Platforms())
+	return cmd
+}
+
+func runDiff(ctx context.Context, dockerCLI command.Cli, opts diffOptions) error {
+	var platform *ocispec.Platform
+	if opts.platform != "" {
+		p, err := platforms.Parse(opts.platform)
+		if err != nil {
+			return fmt.Errorf("invalid platform: %w", err)
+		}
+		platform = &p
+	}
+
+	apiClient := dockerCLI.Client()
+	var imgs [2]client.ImageInspectResult
+	for i, ref := range opts.images {
+		var err error
+		imgs[i], err = apiClient.ImageInspect(ctx, ref, client.ImageInspectWithPlatform(platform))
+		if err != nil {
+			return err
+		}
+	}
+
+	result := imageDiff{
+		Images: opts.images,
+		Config: diffConfig(imgs[0], imgs[1]),
+		Layers: diffLayers(imgs[0].RootFS.Layers, imgs[1].RootFS.Layers),
+	}
+
+	if !opts.noFiles && hasLayerChanges(result.Layers) {
+		trees, err := loadFileTrees(ctx, apiClient, opts.images[:], [][]string{imgs[0].RootFS.Layers, imgs[1].RootFS.Layers}, platform)
+		if err != nil {
+			return err
+		}
+		result.Files = diffFileTrees(trees[0], trees[1])
+	}
+
+	return writeImageDiff(dockerCLI.Out(), opts, result)
+}
+
+// diffConfig compares the configuration of two images.
+func diffConfig(a, b client.ImageInspectResult) []configChange {
+	var changes []configChange
+	addChange := func(field, oldVal, newVal string) {
+		if c, ok := compareValues(field, oldVal, newVal); ok {
+			changes = append(changes, c)
+		}
+	}
+
+	addChange("Platform", imagePlatform(a), imagePlatform(b))
+
+	var cfgA, cfgB ocispec.ImageConfig
+	if a.Config != nil {
+		cfgA = a.Config.ImageConfig
+	}
+	if b.Config != nil {
+		cfgB = b.Config.ImageConfig
+	}
+	addChange("User", cfgA.User, cfgB.User)
+	addChange("WorkingDir", cfgA.WorkingDir, cfgB.WorkingDir)
+	addChange("Entrypoint", formatCommand(cfgA.Entrypoint), formatCommand(cfgB.Entrypoint))
+	addChange("Cmd", formatCommand(cfgA.Cmd), formatCommand(cfgB.Cmd))
+	changes = append(changes, compareMaps("Env", envToMap(cfgA.Env), envToMap(cfgB.Env))...)
+	changes = append(changes, compareMaps("ExposedPorts", setToMap(cfgA.ExposedPorts), setToMap(cfgB.ExposedPorts))...)
+	changes = append(changes, compareMaps("Labels", cfgA.Labels, cfgB.Labels)...)
+	return changes
+}
+
+// compareValues returns a configChange if oldVal and newVal differ.
+func compareValues(field, oldVal, newVal string) (configChange, bool) {
+	switch {
+	case oldVal == newVal:
+		return configChange{}, false
+	case oldVal == "":
+		return configChange{Type: changeAdded, Field: field, New: newVal}, true
+	case newVal == "":
+		return configChange{Type: changeDeleted, Field: field, Old: oldVal}, true
+	default:
+		return configChange{Type: changeModified, Field: field, Old: oldVal, New: newVal}, true
+	}
+}
+
+// compareMaps compares two maps, and returns changes sorted by key.
+func compareMaps(field string, a, b map[string]string) []configChange {
+	keys := slices.Sorted(maps.Keys(a))
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	var changes []configChange
+	for _, k := range keys {
+		oldVal, inA := a[k]
+		newVal, inB := b[k]
+		switch {
+		case !inA:
+			changes = append(changes, configChange{Type: changeAdded, Field: field, Key: k, New: newVal})
+		case !inB:
+			changes = append(changes, configChange{Type: changeDeleted, Field: field, Key: k, Old: oldVal})
+		case oldVal != newVal:
+			changes = append(changes, configChange{Type: changeModified, Field: field, Key: k, Old: oldVal, New: newVal})
+		}
+	}
+	return changes
+}
+
+func imagePlatform(img client.ImageInspectResult) string {
+	return platforms.FormatAll(ocispec.Platform{
+		OS:           img.Os,
+		Architecture: img.Architecture,
+		Variant:      img.Variant,
+		OSVersion:    img.OsVersion,
+	})
+}
+
+// formatCommand formats an Entrypoint or Cmd in JSON (exec) form.
+func formatCommand(cmd []string) string {
+	if len(cmd) == 0 {
+		return ""
+	}
+	out, _ := json.Marshal(cmd)
+	return string(out)
+}
+
+func envToMap(env []string) map[string]string {
+	m := make(map[string]string, len(env))
+	for _, e := range env {
+		k, v, _ := strings.Cut(e, "=")
+		m[k] = v
+	}
+	return m
+}
+
+func setToMap[T any](set map[string]T) map[string]string {
+	m := make(map[string]string, len(set))
+	for k := range set {
+		m[k] = k
+	}
+	return m
+}
+
+// diffLayers compares the layer stacks of two images. Layers are only
+// considered shared if all layers below them are shared as well, as the
+// content of a layer depends on the layers it is applied to.
+func diffLayers(a, b []string) []layerChange {
+	var changes []layerChange
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		changes = append(changes, layerChange{Type: changeShared, Digest: a[n]})
+		n++
+	}
+	for _, l := range a[n:] {
+		changes = append(changes, layerChange{Type: changeDeleted, Digest: l})
+	}
+	for _, l := range b[n:] {
+		changes = append(changes, layerChange{Type: changeAdded, Digest: l})
+	}
+	return changes
+}
+
+func hasLayerChanges(layers []layerChange) bool {
+	for _, l := range layers {
+		if l.Type != changeShared {
+			return true
+		}
+	}
+	return false
+}
+
+func writeImageDiff(out io.Writer, opts diffOptions, result imageDiff) error {
+	switch opts.format {
+	case "":
+		return writeImageDiffTable(out, opts, result)
+	case formatter.JSONFormatKey:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "    ")
+		return enc.Encode(result)
+	default:
+		tmpl, err := templates.Parse(opts.format)
+		if err != nil {
+			return cli.StatusError{StatusCode: 64, Status: "template parsing error: " + err.Error()}
+		}
+		if err := tmpl.Execute(out, result); err != nil {
+			return fmt.Errorf("template parsing error: %w", err)
+		}
+		_, err = fmt.Fprintln(out)
+		return err
+	}
+}
+
+func writeImageDiffTable(out io.Writer, opts diffOptions, result imageDiff) error {
+	w := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+	_, _ = fmt.Fprintf(w, "%s\tFIELD\t%s\t%s\n", changeTypeHeader, opts.images[0], opts.images[1])
+	for _, c := range result.Config {
+		field := c.Field
+		if c.Key != "" {
+			field += "[" + c.Key + "]"
+		}
+		oldVal, newVal := c.Old, c.New
+		if !opts.noTrunc {
+			oldVal, newVal = formatter.Ellipsis(oldVal, 40), formatter.Ellipsis(newVal, 40)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Type, field, oldVal, newVal)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+	_, _ = fmt.Fprintf(w, "%s\tLAYER\n", changeTypeHeader)
+	for _, l := range result.Layers {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", l.Type, l.Digest)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(result.Files) == 0 {
+		return nil
+	}
+	_, _ = fmt.Fprintln(out)
+	return diffFormatWrite(formatter.Context{
+		Output: out,
+		Format: newDiffFormat(formatter.TableFormatKey),
+	}, result.Files)
+}
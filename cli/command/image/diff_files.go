@@ -0,0 +1,215 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package image
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/moby/go-archive"
+	"github.com/moby/go-archive/compression"
+	"github.com/moby/moby/client"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// fileEntry holds the metadata of a file in an image's filesystem that is
+// used to detect changes.
+type fileEntry struct {
+	typeflag byte
+	mode     int64
+	uid, gid int
+	size     int64
+	linkname string
+	digest   string
+}
+
+// fileTree is the flattened filesystem of an image, keyed by absolute path.
+type fileTree map[string]fileEntry
+
+// layerEntry is an entry in a single layer's archive, including whiteouts.
+type layerEntry struct {
+	path  string
+	entry fileEntry
+}
+
+// savedLayers holds the entries of the layers in an image archive, keyed by
+// the diff ID of the layer.
+type savedLayers map[string][]layerEntry
+
+// loadFileTrees reads the images from the daemon using the save API, and
+// returns their flattened filesystems, using the diff IDs of the layers of
+// each image. The images are saved with a single call, so that layers that
+// are shared between the images are only read once.
+func loadFileTrees(ctx context.Context, apiClient client.ImageAPIClient, refs []string, diffIDs [][]string, platform *ocispec.Platform) ([]fileTree, error) {
+	var options []client.ImageSaveOption
+	if platform != nil {
+		options = append(options, client.ImageSaveWithPlatforms(*platform))
+	}
+	rc, err := apiClient.ImageSave(ctx, slices.Compact(slices.Clone(refs)), options...)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	layers, err := readSavedLayers(rc)
+	if err != nil {
+		return nil, err
+	}
+	trees := make([]fileTree, len(refs))
+	for i, ref := range refs {
+		trees[i], err = layers.fileTree(diffIDs[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read filesystem of image %s: %w", ref, err)
+		}
+	}
+	return trees, nil
+}
+
+// readSavedLayers reads an image archive as produced by "docker save" in a
+// single pass, and returns the entries of each blob that looks like a layer.
+func readSavedLayers(r io.Reader) (savedLayers, error) {
+	layers := savedLayers{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return layers, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if entries, diffID, ok := readLayer(tr); ok {
+			layers[diffID.String()] = entries
+		}
+	}
+}
+
+// fileTree returns the flattened filesystem of the layers with the given
+// diff IDs, applied in order.
+func (l savedLayers) fileTree(diffIDs []string) (fileTree, error) {
+	tree := fileTree{}
+	for _, diffID := range diffIDs {
+		entries, ok := l[diffID]
+		if !ok {
+			return nil, fmt.Errorf("layer %s not found in archive", diffID)
+		}
+		tree.apply(entries)
+	}
+	return tree, nil
+}
+
+// readLayer attempts to read r as a (possibly compressed) layer archive, and
+// returns its entries and its diff ID, which is the digest of the
+// uncompressed archive. It returns false if r is not a tar archive, such as
+// an image config or index.
+func readLayer(r io.Reader) ([]layerEntry, digest.Digest, bool) {
+	rc, err := compression.DecompressStream(r)
+	if err != nil {
+		return nil, "", false
+	}
+	defer rc.Close()
+
+	digester := digest.Canonical.Digester()
+	entries := []layerEntry{}
+	tr := tar.NewReader(io.TeeReader(rc, digester.Hash()))
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			// Include the padding after the end of the archive in the
+			// digest.
+			if _, err := io.Copy(digester.Hash(), rc); err != nil {
+				return nil, "", false
+			}
+			return entries, digester.Digest(), true
+		}
+		if err != nil {
+			return nil, "", false
+		}
+		e := fileEntry{
+			typeflag: hdr.Typeflag,
+			mode:     hdr.Mode,
+			uid:      hdr.Uid,
+			gid:      hdr.Gid,
+			size:     hdr.Size,
+			linkname: hdr.Linkname,
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			h := sha256.New()
+			if _, err := io.Copy(h, tr); err != nil {
+				return nil, "", false
+			}
+			e.digest = fmt.Sprintf("%x", h.Sum(nil))
+		}
+		entries = append(entries, layerEntry{path: path.Join("/", hdr.Name), entry: e})
+	}
+}
+
+// apply applies the entries of a layer to the tree. Whiteouts and opaque
+// directories only affect lower layers, so they are handled before adding
+// the layer's own entries.
+func (t fileTree) apply(entries []layerEntry) {
+	for _, le := range entries {
+		dir, base := path.Split(le.path)
+		switch {
+		case base == archive.WhiteoutOpaqueDir:
+			t.removeChildren(path.Clean(dir))
+		case strings.HasPrefix(base, archive.WhiteoutPrefix):
+			p := path.Join(dir, strings.TrimPrefix(base, archive.WhiteoutPrefix))
+			delete(t, p)
+			t.removeChildren(p)
+		}
+	}
+	for _, le := range entries {
+		if strings.HasPrefix(path.Base(le.path), archive.WhiteoutPrefix) {
+			continue
+		}
+		if prev, ok := t[le.path]; ok && prev.typeflag == tar.TypeDir && le.entry.typeflag != tar.TypeDir {
+			t.removeChildren(le.path)
+		}
+		t[le.path] = le.entry
+	}
+}
+
+func (t fileTree) removeChildren(dir string) {
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	for p := range t {
+		if strings.HasPrefix(p, prefix) {
+			delete(t, p)
+		}
+	}
+}
+
+// diffFileTrees compares two filesystems, and returns the changes sorted by
+// path.
+func diffFileTrees(a, b fileTree) []fileChange {
+	var changes []fileChange
+	for p, ea := range a {
+		eb, ok := b[p]
+		switch {
+		case !ok:
+			changes = append(changes, fileChange{Type: changeDeleted, Path: p})
+		case ea != eb:
+			changes = append(changes, fileChange{Type: changeModified, Path: p})
+		}
+	}
+	for p := range b {
+		if _, ok := a[p]; !ok {
+			changes = append(changes, fileChange{Type: changeAdded, Path: p})
+		}
+	}
+	slices.SortFunc(changes, func(x, y fileChange) int {
+		return strings.Compare(x.Path, y.Path)
+	})
+	return changes
+}
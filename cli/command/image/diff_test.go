@@ -0,0 +1,265 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/client"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+type tarFile struct {
+	name     string
+	typeflag byte
+	content  string
+}
+
+func makeTar(t *testing.T, files ...tarFile) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range files {
+		hdr := &tar.Header{Name: f.name, Typeflag: f.typeflag, Mode: 0o644, Size: int64(len(f.content))}
+		if f.typeflag == 0 {
+			hdr.Typeflag = tar.TypeReg
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			hdr.Mode, hdr.Size = 0o755, 0
+		}
+		assert.NilError(t, tw.WriteHeader(hdr))
+		_, err := tw.Write([]byte(f.content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return buf.Bytes()
+}
+
+// makeSavedImages produces an archive in the format produced by "docker save"
+// with the given layers.
+func makeSavedImages(t *testing.T, layers ...[]byte) []byte {
+	t.Helper()
+	files := []tarFile{{name: "blobs/sha256/config", content: `{"architecture":"amd64","os":"linux"}`}}
+	for _, l := range layers {
+		files = append(files, tarFile{name: "blobs/sha256/" + digest.FromBytes(l).Encoded(), content: string(l)})
+	}
+	files = append(files, tarFile{name: "manifest.json", content: "[]"})
+	return makeTar(t, files...)
+}
+
+func TestDiffConfig(t *testing.T) {
+	a := client.ImageInspectResult{InspectResponse: image.InspectResponse{
+		Os:           "linux",
+		Architecture: "amd64",
+		Config: &dockerspec.DockerOCIImageConfig{ImageConfig: ocispec.ImageConfig{
+			User:         "root",
+			Env:          []string{"PATH=/usr/bin", "FOO=bar"},
+			Cmd:          []string{"sh"},
+			ExposedPorts: map[string]struct{}{"80/tcp": {}},
+			Labels:       map[string]string{"version": "1"},
+		}},
+	}}
+	b := client.ImageInspectResult{InspectResponse: image.InspectResponse{
+		Os:           "linux",
+		Architecture: "arm64",
+		Config: &dockerspec.DockerOCIImageConfig{ImageConfig: ocispec.ImageConfig{
+			User:         "root",
+			Env:          []string{"PATH=/usr/local/bin:/usr/bin", "BAZ=qux"},
+			Entrypoint:   []string{"/entrypoint.sh"},
+			Cmd:          []string{"sh"},
+			ExposedPorts: map[string]struct{}{"80/tcp": {}, "443/tcp": {}},
+			Labels:       map[string]string{"version": "2"},
+		}},
+	}}
+
+	assert.Check(t, is.DeepEqual(diffConfig(a, b), []configChange{
+		{Type: changeModified, Field: "Platform", Old: "linux/amd64", New: "linux/arm64"},
+		{Type: changeAdded, Field: "Entrypoint", New: `["/entrypoint.sh"]`},
+		{Type: changeAdded, Field: "Env", Key: "BAZ", New: "qux"},
+		{Type: changeDeleted, Field: "Env", Key: "FOO", Old: "bar"},
+		{Type: changeModified, Field: "Env", Key: "PATH", Old: "/usr/bin", New: "/usr/local/bin:/usr/bin"},
+		{Type: changeAdded, Field: "ExposedPorts", Key: "443/tcp", New: "443/tcp"},
+		{Type: changeModified, Field: "Labels", Key: "version", Old: "1", New: "2"},
+	}))
+	assert.Check(t, is.Len(diffConfig(a, a), 0))
+}
+
+func TestDiffLayers(t *testing.T) {
+	changes := diffLayers([]string{"sha256:1", "sha256:2", "sha256:3"}, []string{"sha256:1", "sha256:4", "sha256:3"})
+	assert.Check(t, is.DeepEqual(changes, []layerChange{
+		{Type: changeShared, Digest: "sha256:1"},
+		{Type: changeDeleted, Digest: "sha256:2"},
+		{Type: changeDeleted, Digest: "sha256:3"},
+		{Type: changeAdded, Digest: "sha256:4"},
+		{Type: changeAdded, Digest: "sha256:3"},
+	}))
+	assert.Check(t, hasLayerChanges(changes))
+	assert.Check(t, !hasLayerChanges(diffLayers([]string{"sha256:1"}, []string{"sha256:1"})))
+}
+
+func TestDiffFileTrees(t *testing.T) {
+	base := makeTar(t,
+		tarFile{name: "etc/", typeflag: tar.TypeDir},
+		tarFile{name: "etc/hostname", content: "base"},
+		tarFile{name: "etc/passwd", content: "root"},
+		tarFile{name: "var/", typeflag: tar.TypeDir},
+		tarFile{name: "var/cache/", typeflag: tar.TypeDir},
+		tarFile{name: "var/cache/apk", content: "cache"},
+	)
+	upper := makeTar(t,
+		tarFile{name: "etc/.wh.hostname"},
+		tarFile{name: "etc/passwd", content: "root\nuser"},
+		tarFile{name: "var/cache/", typeflag: tar.TypeDir},
+		tarFile{name: "var/cache/.wh..wh..opq"},
+		tarFile{name: "var/cache/new", content: "new"},
+	)
+	layers, err := readSavedLayers(bytes.NewReader(makeSavedImages(t, base, upper)))
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(layers, 2))
+	a, err := layers.fileTree([]string{digest.FromBytes(base).String()})
+	assert.NilError(t, err)
+	b, err := layers.fileTree([]string{digest.FromBytes(base).String(), digest.FromBytes(upper).String()})
+	assert.NilError(t, err)
+
+	assert.Check(t, is.DeepEqual(diffFileTrees(a, b), []fileChange{
+		{Type: changeDeleted, Path: "/etc/hostname"},
+		{Type: changeModified, Path: "/etc/passwd"},
+		{Type: changeDeleted, Path: "/var/cache/apk"},
+		{Type: changeAdded, Path: "/var/cache/new"},
+	}))
+	assert.Check(t, is.Len(diffFileTrees(b, b), 0))
+}
+
+func TestReadSavedLayersMissingLayer(t *testing.T) {
+	layers, err := readSavedLayers(bytes.NewReader(makeSavedImages(t)))
+	assert.NilError(t, err)
+	_, err = layers.fileTree([]string{"sha256:missing"})
+	assert.Check(t, is.Error(err, "layer sha256:missing not found in archive"))
+}
+
+func fakeDiffImages(t *testing.T) *fakeClient {
+	t.Helper()
+	base := makeTar(t, tarFile{name: "bin/sh", content: "shell"})
+	app := makeTar(t, tarFile{name: "app/", typeflag: tar.TypeDir}, tarFile{name: "app/main", content: "main"})
+	baseID, appID := digest.FromBytes(base).String(), digest.FromBytes(app).String()
+	images := map[string]client.ImageInspectResult{
+		"image:1": {InspectResponse: image.InspectResponse{
+			Os: "linux", Architecture: "amd64",
+			Config: &dockerspec.DockerOCIImageConfig{ImageConfig: ocispec.ImageConfig{
+				Env: []string{"VERSION=1"},
+				Cmd: []string{"sh"},
+			}},
+			RootFS: image.RootFS{Layers: []string{baseID}},
+		}},
+		"image:2": {InspectResponse: image.InspectResponse{
+			Os: "linux", Architecture: "amd64",
+			Config: &dockerspec.DockerOCIImageConfig{ImageConfig: ocispec.ImageConfig{
+				Env:    []string{"VERSION=2"},
+				Cmd:    []string{"sh", "-c", "echo hello"},
+				Labels: map[string]string{"maintainer": "me"},
+			}},
+			RootFS: image.RootFS{Layers: []string{baseID, appID}},
+		}},
+	}
+	return &fakeClient{
+		imageInspectFunc: func(img string) (client.ImageInspectResult, error) {
+			res, ok := images[img]
+			if !ok {
+				return client.ImageInspectResult{}, errors.New("no such image: " + img)
+			}
+			return res, nil
+		},
+		imageSaveFunc: func(images []string, _ ...client.ImageSaveOption) (client.ImageSaveResult, error) {
+			// Both images are saved with a single call, which includes the
+			// shared base layer once.
+			assert.Check(t, is.DeepEqual(images, []string{"image:1", "image:2"}))
+			return io.NopCloser(bytes.NewReader(makeSavedImages(t, base, app))), nil
+		},
+	}
+}
+
+func TestNewDiffCommandErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "wrong-args",
+			args:          []string{"image:1"},
+			expectedError: "requires 2 arguments",
+		},
+		{
+			name:          "client-error",
+			args:          []string{"image:1", "image:3"},
+			expectedError: "no such image: image:3",
+		},
+		{
+			name:          "invalid platform",
+			args:          []string{"--platform", "<invalid>", "image:1", "image:2"},
+			expectedError: "invalid platform",
+		},
+		{
+			name:          "invalid format",
+			args:          []string{"--format", "{{invalid", "image:1", "image:2"},
+			expectedError: "template parsing error",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newDiffCommand(test.NewFakeCli(fakeDiffImages(t)))
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
+
+func TestNewDiffCommandSuccess(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+	}{
+		{
+			name: "default",
+			args: []string{"image:1", "image:2"},
+		},
+		{
+			name: "no-files",
+			args: []string{"--no-files", "image:1", "image:2"},
+		},
+		{
+			name: "json",
+			args: []string{"--format", "json", "image:1", "image:2"},
+		},
+		{
+			name: "template",
+			args: []string{"--format", "{{range .Files}}{{.Type}} {{.Path}}\n{{end}}", "image:1", "image:2"},
+		},
+		{
+			name: "same-image",
+			args: []string{"image:1", "image:1"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(fakeDiffImages(t))
+			cmd := newDiffCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), "diff-command-success."+tc.name+".golden")
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package image
+
+import (
+	"github.com/docker/cli/cli/command/formatter"
+)
+
+const (
+	defaultDiffTableFormat = "table {{.Type}}\t{{.Path}}"
+
+	changeTypeHeader = "CHANGE TYPE"
+	pathHeader       = "PATH"
+)
+
+// newDiffFormat returns a format for use with a diff [formatter.Context].
+func newDiffFormat(source string) formatter.Format {
+	if source == formatter.TableFormatKey || source == formatter.CSVFormatKey {
+		return formatter.TableOrCSV(source, defaultDiffTableFormat)
+	}
+	return formatter.Format(source)
+}
+
+// diffFormatWrite writes formatted file changes using the [formatter.Context].
+func diffFormatWrite(fmtCtx formatter.Context, changes []fileChange) error {
+	return fmtCtx.Write(newDiffContext(), func(format func(subContext formatter.SubContext) error) error {
+		for _, change := range changes {
+			if err := format(&diffContext{c: change}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type diffContext struct {
+	formatter.HeaderContext
+	c fileChange
+}
+
+func newDiffContext() *diffContext {
+	return &diffContext{
+		HeaderContext: formatter.HeaderContext{
+			Header: formatter.SubHeaderContext{
+				"Type": changeTypeHeader,
+				"Path": pathHeader,
+			},
+		},
+	}
+}
+
+func (d *diffContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(d)
+}
+
+func (d *diffContext) Type() string {
+	return d.c.Type
+}
+
+func (d *diffContext) Path() string {
+	return d.c.Path
+}
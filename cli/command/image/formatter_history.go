@@ -22,14 +22,14 @@
 
 // newHistoryFormat returns a format for rendering a historyContext.
 func newHistoryFormat(source string, quiet bool, human bool) formatter.Format {
-	if source == formatter.TableFormatKey {
+	if source == formatter.TableFormatKey || source == formatter.CSVFormatKey {
 		switch {
 		case quiet:
 			return formatter.DefaultQuietFormat
 		case !human:
-			return nonHumanHistoryTableFormat
+			return formatter.TableOrCSV(source, nonHumanHistoryTableFormat)
 		default:
-			return defaultHistoryTableFormat
+			return formatter.TableOrCSV(source, defaultHistoryTableFormat)
 		}
 	}
 
@@ -31,6 +31,7 @@ type imagesOptions struct {
 	format      string
 	filter      opts.FilterOpt
 	tree        bool
+	list        formatter.ListOptions
 }
 
 // newImagesCommand creates a new `docker images` command
@@ -70,6 +71,7 @@ func newImagesCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVar(&options.showDigests, "digests", false, "Show digests")
 	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
+	options.list.InstallFlags(flags)
 
 	flags.BoolVar(&options.tree, "tree", false, "List multi-platform images as a tree (EXPERIMENTAL)")
 	flags.SetAnnotation("tree", "version", []string{"1.47"})
@@ -137,6 +139,8 @@ func runImages(ctx context.Context, dockerCLI command.Cli, options imagesOptions
 			Output: dockerCLI.Out(),
 			Format: formatter.NewImageFormat(format, options.quiet, options.showDigests),
 			Trunc:  !options.noTrunc,
+
+			ListOptions: options.list,
 		},
 		Digest: options.showDigests,
 	}
@@ -171,6 +175,12 @@ func shouldUseTree(options imagesOptions) (bool, error) {
 		}
 		return false, nil
 	}
+	if len(options.list.Sort) > 0 || len(options.list.Columns) > 0 || options.list.NoHeaders {
+		if options.tree {
+			return false, errors.New("--sort, --columns, and --no-headers are not yet supported with --tree")
+		}
+		return false, nil
+	}
 	return true, nil
 }
 
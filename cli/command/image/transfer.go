@@ -0,0 +1,393 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package image
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/platforms"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/jsonstream"
+	"github.com/moby/moby/client"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxArchiveJSONSize is the maximum size of the JSON blobs, such as
+// manifests and image configs, that are read from an image archive.
+const maxArchiveJSONSize = 4 << 20
+
+type transferOptions struct {
+	image    string
+	to       string
+	platform []string
+	quiet    bool
+}
+
+// newTransferCommand creates a new "docker image transfer" command.
+func newTransferCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts transferOptions
+
+	cmd := &cobra.Command{
+		Use:   "transfer [OPTIONS] IMAGE",
+		Short: "Transfer an image to the daemon of another context",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.image = args[0]
+			if opts.to == dockerCLI.CurrentContext() {
+				return fmt.Errorf("cannot transfer image to context %q: context is the current context", opts.to)
+			}
+			dst, err := command.NewAPIClientFromContext(cmd.Context(), dockerCLI, opts.to)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = dst.Close() }()
+			return runTransfer(cmd.Context(), dockerCLI, dst, opts)
+		},
+		ValidArgsFunction:     completion.ImageNames(dockerCLI, 1),
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.to, "to", "", "Name of the context to transfer the image to")
+	flags.StringSliceVar(&opts.platform, "platform", []string{}, `Transfer only the given platform(s). Formatted as a comma-separated list of "os[/arch[/variant]]" (e.g., "linux/amd64,linux/arm64/v8")`)
+	_ = flags.SetAnnotation("platform", "version", []string{"1.48"})
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress the transfer output")
+	_ = cmd.MarkFlagRequired("to")
+
+	_ = cmd.RegisterFlagCompletionFunc("to", completion.ContextNames(dockerCLI))
+	_ = cmd.RegisterFlagCompletionFunc("platform", completion.Platforms())
+	return cmd
+}
+
+// TransferImage transfers the image from the daemon of the current context
+// to the dst daemon. It's used by commands that move other objects between
+// daemons, such as "docker container migrate".
+func TransferImage(ctx context.Context, dockerCLI command.Cli, dst client.APIClient, img string, quiet bool) error {
+	return runTransfer(ctx, dockerCLI, dst, transferOptions{image: img, quiet: quiet})
+}
+
+// runTransfer streams the image from the daemon of the current context to
+// the dst daemon. If both daemons use the containerd image store, layers
+// that the dst daemon already has are left out of the stream.
+func runTransfer(ctx context.Context, dockerCLI command.Cli, dst client.APIClient, opts transferOptions) error {
+	platformList := []ocispec.Platform{}
+	for _, p := range opts.platform {
+		pp, err := platforms.Parse(p)
+		if err != nil {
+			return fmt.Errorf("invalid platform: %w", err)
+		}
+		platformList = append(platformList, pp)
+	}
+
+	src := dockerCLI.Client()
+	dstLayers, err := sharedLayers(ctx, src, dst, opts.image)
+	if err != nil {
+		return err
+	}
+	quiet := opts.quiet || !dockerCLI.Out().IsTerminal()
+	skipped, err := transferImage(ctx, dockerCLI.Out(), src, dst, opts.image, platformList, dstLayers, quiet)
+	if err != nil && skipped && ctx.Err() == nil {
+		// The destination may not be able to use the layers it has; retry
+		// with all layers.
+		logrus.WithError(err).Debug("failed to transfer image without shared layers, retrying with all layers")
+		_, err = transferImage(ctx, dockerCLI.Out(), src, dst, opts.image, platformList, nil, quiet)
+	}
+	return err
+}
+
+// transferImage streams the save API of src into the load API of dst,
+// leaving out the blobs of the layers in dstLayers, and displays the
+// progress. It returns whether any blobs were left out.
+func transferImage(ctx context.Context, out *streams.Out, src, dst client.APIClient, img string, platformList []ocispec.Platform, dstLayers map[digest.Digest]bool, quiet bool) (bool, error) {
+	var saveOpts []client.ImageSaveOption
+	var loadOpts []client.ImageLoadOption
+	if len(platformList) > 0 {
+		saveOpts = append(saveOpts, client.ImageSaveWithPlatforms(platformList...))
+		loadOpts = append(loadOpts, client.ImageLoadWithPlatforms(platformList...))
+	}
+	if quiet {
+		loadOpts = append(loadOpts, client.ImageLoadWithQuiet(true))
+	}
+
+	archive, err := src.ImageSave(ctx, []string{img}, saveOpts...)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = archive.Close() }()
+
+	msgReader, msgWriter := io.Pipe()
+	progress := &transferProgress{id: img, enc: json.NewEncoder(msgWriter), quiet: quiet}
+
+	archiveReader, archiveWriter := io.Pipe()
+	go func() {
+		_ = archiveWriter.CloseWithError(filterArchive(archive, archiveWriter, dstLayers, progress.skipped))
+	}()
+	go func() {
+		_ = msgWriter.CloseWithError(func() error {
+			defer func() { _ = archiveReader.Close() }()
+			res, err := dst.ImageLoad(ctx, &countingReader{r: archiveReader, progress: progress}, loadOpts...)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = res.Close() }()
+			dec := json.NewDecoder(res)
+			for {
+				var msg jsonstream.JSONMessage
+				if err := dec.Decode(&msg); err != nil {
+					if errors.Is(err, io.EOF) {
+						return nil
+					}
+					return err
+				}
+				progress.write(msg)
+			}
+		}())
+	}()
+	err = jsonstream.Display(ctx, msgReader, out)
+	return progress.skippedAny(), err
+}
+
+// transferProgress writes progress messages of a transfer.
+type transferProgress struct {
+	mu           sync.Mutex
+	id           string
+	enc          *json.Encoder
+	quiet        bool
+	last         time.Time
+	skippedBlobs int
+}
+
+func (p *transferProgress) write(msg jsonstream.JSONMessage) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_ = p.enc.Encode(msg)
+}
+
+// transferred reports the number of bytes that were transferred; progress
+// is reported at most every 100ms, unless done is set.
+func (p *transferProgress) transferred(n int64, done bool) {
+	if p.quiet || (!done && time.Since(p.last) < 100*time.Millisecond) {
+		return
+	}
+	p.last = time.Now()
+	status := "Transferring"
+	if done {
+		status = "Transferred"
+	}
+	p.write(jsonstream.JSONMessage{ID: p.id, Status: status, Progress: &jsonstream.JSONProgress{Current: n}})
+}
+
+func (p *transferProgress) skipped(dgst digest.Digest) {
+	p.mu.Lock()
+	p.skippedBlobs++
+	p.mu.Unlock()
+	if p.quiet {
+		return
+	}
+	p.write(jsonstream.JSONMessage{ID: dgst.Encoded()[:12], Status: "Already exists"})
+}
+
+// skippedAny returns whether any blobs were left out of the transfer.
+func (p *transferProgress) skippedAny() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.skippedBlobs > 0
+}
+
+// countingReader reports the progress of reading from r.
+type countingReader struct {
+	r        io.Reader
+	n        int64
+	progress *transferProgress
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	c.progress.transferred(c.n, errors.Is(err, io.EOF))
+	return n, err
+}
+
+// filterArchive copies the image archive from r to w, leaving out the blobs
+// of the layers in dstLayers. The manifests and image configs are read from
+// the archive while it's copied, so a layer blob can only be left out if the
+// manifest and image config that refer to it precede it in the archive.
+func filterArchive(r io.Reader, w io.Writer, dstLayers map[digest.Digest]bool, skipped func(digest.Digest)) error {
+	if len(dstLayers) == 0 {
+		_, err := io.Copy(w, r)
+		return err
+	}
+	var layers archiveLayers
+	tr := tar.NewReader(r)
+	tw := tar.NewWriter(w)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		dgst, isBlob := blobDigest(hdr.Name)
+		if isBlob {
+			if diffID, ok := layers.diffID(dgst); ok && dstLayers[diffID] {
+				skipped(dgst)
+				continue
+			}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !isBlob || hdr.Size > maxArchiveJSONSize {
+			if _, err := io.Copy(tw, tr); err != nil {
+				return err
+			}
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+		layers.add(dgst, data)
+	}
+	return tw.Close()
+}
+
+// blobDigest returns the digest of the blob at the given path in an OCI
+// image layout ("blobs/<algorithm>/<encoded>").
+func blobDigest(name string) (digest.Digest, bool) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 3 || parts[0] != ocispec.ImageBlobsDir {
+		return "", false
+	}
+	dgst := digest.NewDigestFromEncoded(digest.Algorithm(parts[1]), parts[2])
+	return dgst, dgst.Validate() == nil
+}
+
+// sharedLayers returns the diff IDs of the layers on the dst daemon if it
+// has any of the layers of the image. It returns no layers unless both
+// daemons use the containerd image store, which allows an image to be
+// loaded without the blobs of layers that are present.
+func sharedLayers(ctx context.Context, src, dst client.APIClient, img string) (map[digest.Digest]bool, error) {
+	srcImage, err := src.ImageInspect(ctx, img)
+	if err != nil {
+		return nil, err
+	}
+	if srcImage.Descriptor == nil {
+		return nil, nil
+	}
+	dstLayers, err := destinationLayers(ctx, dst)
+	if err != nil {
+		logrus.WithError(err).Debug("failed to get layers of destination, transferring all layers")
+		return nil, nil
+	}
+	for _, l := range srcImage.RootFS.Layers {
+		if dstLayers[digest.Digest(l)] {
+			return dstLayers, nil
+		}
+	}
+	return nil, nil
+}
+
+// destinationLayers returns the diff IDs of the layers of the images on
+// the daemon, if it uses the containerd image store.
+func destinationLayers(ctx context.Context, apiClient client.APIClient) (map[digest.Digest]bool, error) {
+	images, err := apiClient.ImageList(ctx, client.ImageListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var mu sync.Mutex
+	layers := make(map[digest.Digest]bool)
+	containerd := true
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(8)
+	for _, img := range images.Items {
+		eg.Go(func() error {
+			res, err := apiClient.ImageInspect(ctx, img.ID)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if res.Descriptor == nil {
+				containerd = false
+			}
+			for _, l := range res.RootFS.Layers {
+				layers[digest.Digest(l)] = true
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	if !containerd {
+		return nil, nil
+	}
+	return layers, nil
+}
+
+// archiveLayers maps the digests of the layer blobs in an image archive in
+// the OCI image layout to their diff IDs, using the manifests and image
+// configs that were added while reading the archive.
+type archiveLayers struct {
+	blobs   map[digest.Digest][]byte
+	diffIDs map[digest.Digest]digest.Digest
+	stale   bool
+}
+
+// add adds a blob of the archive if it's a JSON document, such as a
+// manifest or an image config.
+func (a *archiveLayers) add(dgst digest.Digest, data []byte) {
+	if !json.Valid(data) {
+		return
+	}
+	if a.blobs == nil {
+		a.blobs = make(map[digest.Digest][]byte)
+	}
+	a.blobs[dgst] = data
+	a.stale = true
+}
+
+// diffID returns the diff ID of the layer blob with the given digest, if
+// the manifest and image config that refer to it were added.
+func (a *archiveLayers) diffID(dgst digest.Digest) (digest.Digest, bool) {
+	if a.stale {
+		a.stale = false
+		a.diffIDs = make(map[digest.Digest]digest.Digest)
+		for _, data := range a.blobs {
+			var m ocispec.Manifest
+			if json.Unmarshal(data, &m) != nil || a.blobs[m.Config.Digest] == nil {
+				continue
+			}
+			var config ocispec.Image
+			if json.Unmarshal(a.blobs[m.Config.Digest], &config) != nil || len(config.RootFS.DiffIDs) != len(m.Layers) {
+				continue
+			}
+			for i, l := range m.Layers {
+				a.diffIDs[l.Digest] = config.RootFS.DiffIDs[i]
+			}
+		}
+	}
+	diffID, ok := a.diffIDs[dgst]
+	return diffID, ok
+}
@@ -0,0 +1,228 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/client"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// testImageArchive returns an image archive in the OCI image layout with
+// the given layers, and the diff IDs of the layers. The layer blobs are
+// written after the manifest and image config if layersLast is set.
+func testImageArchive(t *testing.T, layersLast bool, layers ...string) ([]byte, []string) {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	writeFile := func(name string, data []byte) {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data))}))
+		_, err := tw.Write(data)
+		assert.NilError(t, err)
+	}
+	writeBlob := func(data []byte) ocispec.Descriptor {
+		dgst := digest.FromBytes(data)
+		writeFile("blobs/sha256/"+dgst.Encoded(), data)
+		return ocispec.Descriptor{Digest: dgst, Size: int64(len(data))}
+	}
+	writeLayer := writeBlob
+	var layerBlobs [][]byte
+	if layersLast {
+		writeLayer = func(data []byte) ocispec.Descriptor {
+			layerBlobs = append(layerBlobs, data)
+			return ocispec.Descriptor{Digest: digest.FromBytes(data), Size: int64(len(data))}
+		}
+	}
+
+	var config ocispec.Image
+	var manifest ocispec.Manifest
+	var diffIDs []string
+	for _, l := range layers {
+		// The diff ID is the digest of the uncompressed layer, which is
+		// different from the digest of the blob.
+		diffID := digest.FromString("uncompressed " + l)
+		config.RootFS.DiffIDs = append(config.RootFS.DiffIDs, diffID)
+		diffIDs = append(diffIDs, diffID.String())
+		manifest.Layers = append(manifest.Layers, writeLayer([]byte(l)))
+	}
+	configJSON, err := json.Marshal(config)
+	assert.NilError(t, err)
+	manifest.Config = writeBlob(configJSON)
+	manifestJSON, err := json.Marshal(manifest)
+	assert.NilError(t, err)
+	index, err := json.Marshal(ocispec.Index{Manifests: []ocispec.Descriptor{writeBlob(manifestJSON)}})
+	assert.NilError(t, err)
+	for _, data := range layerBlobs {
+		writeBlob(data)
+	}
+	writeFile("index.json", index)
+	assert.NilError(t, tw.Close())
+	return buf.Bytes(), diffIDs
+}
+
+func archiveEntries(t *testing.T, r io.Reader) []string {
+	t.Helper()
+	var names []string
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return names
+		}
+		assert.NilError(t, err)
+		names = append(names, hdr.Name)
+	}
+}
+
+func TestRunTransfer(t *testing.T) {
+	archive, diffIDs := testImageArchive(t, true, "layer-a", "layer-b")
+	allEntries := archiveEntries(t, bytes.NewReader(archive))
+	sharedBlob := "blobs/sha256/" + digest.FromString("layer-a").Encoded()
+	layersFirst, _ := testImageArchive(t, false, "layer-a", "layer-b")
+
+	tests := []struct {
+		doc             string
+		archive         []byte
+		srcDescriptor   *ocispec.Descriptor
+		dstDescriptor   *ocispec.Descriptor
+		failSkipped     bool
+		expectedEntries []string
+		expectedSaves   int
+	}{
+		{
+			doc:             "classic image store",
+			expectedEntries: allEntries,
+			expectedSaves:   1,
+		},
+		{
+			doc:             "containerd image store",
+			srcDescriptor:   &ocispec.Descriptor{},
+			dstDescriptor:   &ocispec.Descriptor{},
+			expectedEntries: without(allEntries, sharedBlob),
+			expectedSaves:   1,
+		},
+		{
+			doc:             "layers before manifest",
+			archive:         layersFirst,
+			srcDescriptor:   &ocispec.Descriptor{},
+			dstDescriptor:   &ocispec.Descriptor{},
+			expectedEntries: archiveEntries(t, bytes.NewReader(layersFirst)),
+			expectedSaves:   1,
+		},
+		{
+			doc:             "classic image store on destination",
+			srcDescriptor:   &ocispec.Descriptor{},
+			expectedEntries: allEntries,
+			expectedSaves:   1,
+		},
+		{
+			doc:             "retry with all layers",
+			srcDescriptor:   &ocispec.Descriptor{},
+			dstDescriptor:   &ocispec.Descriptor{},
+			failSkipped:     true,
+			expectedEntries: allEntries,
+			expectedSaves:   2,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			if tc.archive == nil {
+				tc.archive = archive
+			}
+			var saves int
+			src := &fakeClient{
+				imageInspectFunc: func(string) (client.ImageInspectResult, error) {
+					return client.ImageInspectResult{InspectResponse: image.InspectResponse{
+						Descriptor: tc.srcDescriptor,
+						RootFS:     image.RootFS{Layers: diffIDs},
+					}}, nil
+				},
+				imageSaveFunc: func(images []string, _ ...client.ImageSaveOption) (client.ImageSaveResult, error) {
+					assert.Check(t, is.DeepEqual(images, []string{"foo:latest"}))
+					saves++
+					return io.NopCloser(bytes.NewReader(tc.archive)), nil
+				},
+			}
+			var loaded []string
+			dst := &fakeClient{
+				imageListFunc: func(client.ImageListOptions) (client.ImageListResult, error) {
+					return client.ImageListResult{Items: []image.Summary{{ID: "base"}}}, nil
+				},
+				imageInspectFunc: func(string) (client.ImageInspectResult, error) {
+					return client.ImageInspectResult{InspectResponse: image.InspectResponse{
+						Descriptor: tc.dstDescriptor,
+						RootFS:     image.RootFS{Layers: diffIDs[:1]},
+					}}, nil
+				},
+				imageLoadFunc: func(input io.Reader, _ ...client.ImageLoadOption) (client.ImageLoadResult, error) {
+					loaded = archiveEntries(t, input)
+					if tc.failSkipped && len(loaded) < len(allEntries) {
+						return nil, errors.New("content digest not found")
+					}
+					return io.NopCloser(strings.NewReader(`{"stream":"Loaded image: foo:latest\n"}`)), nil
+				},
+			}
+			cli := test.NewFakeCli(src)
+			err := runTransfer(context.Background(), cli, dst, transferOptions{image: "foo:latest", to: "remote"})
+			assert.NilError(t, err)
+			assert.Check(t, is.DeepEqual(loaded, tc.expectedEntries))
+			assert.Check(t, is.Equal(saves, tc.expectedSaves))
+			assert.Check(t, is.Equal(cli.OutBuffer().String(), "Loaded image: foo:latest\n"))
+		})
+	}
+}
+
+func without(items []string, item string) []string {
+	var out []string
+	for _, i := range items {
+		if i != item {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func TestNewTransferCommandErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "wrong-args",
+			args:          []string{"--to", "remote"},
+			expectedError: "requires 1 argument",
+		},
+		{
+			name:          "missing-to",
+			args:          []string{"foo"},
+			expectedError: `required flag(s) "to" not set`,
+		},
+		{
+			name:          "current-context",
+			args:          []string{"--to", "default", "foo"},
+			expectedError: `cannot transfer image to context "default": context is the current context`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{})
+			cmd := newTransferCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
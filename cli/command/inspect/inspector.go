@@ -53,8 +53,15 @@ func NewTemplateInspectorFromString(out io.Writer, tmplStr string) (Inspector, e
 		return NewIndentedInspector(out), nil
 	}
 
-	if tmplStr == "json" {
+	switch tmplStr {
+	case "json":
 		return NewJSONInspector(out), nil
+	case "ndjson":
+		return NewNDJSONInspector(out), nil
+	case "yaml":
+		return NewYAMLInspector(out), nil
+	case "csv":
+		return NewCSVInspector(out), nil
 	}
 
 	tmpl, err := templates.Parse(tmplStr)
@@ -2,6 +2,7 @@
 
 import (
 	"bytes"
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -307,3 +308,97 @@ func TestNewTemplateInspectorFromString(t *testing.T) {
 		})
 	}
 }
+
+func TestStructuredInspectors(t *testing.T) {
+	type state struct {
+		Status  string
+		Running bool
+	}
+	type element struct {
+		Name   string
+		State  state
+		Labels map[string]string
+		Ports  []int
+	}
+	testCases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "yaml",
+			template: "yaml",
+			expected: `- Labels:
+    com.example: value
+  Name: web
+  Ports:
+    - 80
+    - 443
+  State:
+    Running: true
+    Status: running
+- Labels: {}
+  Name: db, "primary"
+  Ports: []
+  State:
+    Running: false
+    Status: exited
+`,
+		},
+		{
+			name:     "ndjson",
+			template: "ndjson",
+			expected: `{"Labels":{"com.example":"value"},"Name":"web","Ports":[80,443],"State":{"Running":true,"Status":"running"}}
+{"Labels":{},"Name":"db, \"primary\"","Ports":[],"State":{"Running":false,"Status":"exited"}}
+`,
+		},
+		{
+			name:     "csv",
+			template: "csv",
+			expected: `Labels,Labels.com.example,Name,Ports,State.Running,State.Status
+,value,web,"[80,443]",true,running
+,,"db, ""primary""",[],false,exited
+`,
+		},
+	}
+	elements := []element{
+		{Name: "web", State: state{Status: "running", Running: true}, Labels: map[string]string{"com.example": "value"}, Ports: []int{80, 443}},
+		{Name: `db, "primary"`, State: state{Status: "exited"}, Labels: map[string]string{}, Ports: []int{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := new(bytes.Buffer)
+			i, err := NewTemplateInspectorFromString(b, tc.template)
+			assert.NilError(t, err)
+
+			// The second element is passed in its raw form.
+			assert.NilError(t, i.Inspect(elements[0], nil))
+			raw, err := json.Marshal(elements[1])
+			assert.NilError(t, err)
+			assert.NilError(t, i.Inspect(nil, raw))
+			assert.NilError(t, i.Flush())
+
+			assert.Equal(t, b.String(), tc.expected)
+		})
+	}
+}
+
+func TestStructuredInspectorsEmpty(t *testing.T) {
+	for format, expected := range map[string]string{"yaml": "[]\n", "ndjson": "", "csv": ""} {
+		b := new(bytes.Buffer)
+		i, err := NewTemplateInspectorFromString(b, format)
+		assert.NilError(t, err)
+		assert.NilError(t, i.Flush())
+		assert.Check(t, is.Equal(b.String(), expected), format)
+	}
+}
+
+func TestNDJSONInspectorStreams(t *testing.T) {
+	b := new(bytes.Buffer)
+	i := NewNDJSONInspector(b)
+	assert.NilError(t, i.Inspect(map[string]string{"Name": "web"}, nil))
+	assert.Check(t, is.Equal(b.String(), `{"Name":"web"}`+"\n"))
+	assert.NilError(t, i.Inspect(nil, []byte(`{"Name":"db"}`)))
+	assert.Check(t, is.Equal(b.String(), `{"Name":"web"}`+"\n"+`{"Name":"db"}`+"\n"))
+	assert.NilError(t, i.Flush())
+}
@@ -0,0 +1,179 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package inspect
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/docker/cli/internal/formatting"
+	"go.yaml.in/yaml/v3"
+)
+
+// structuredInspector collects elements as generic values, and writes them
+// using the given function on Flush.
+type structuredInspector struct {
+	out      io.Writer
+	elements []any
+	write    func(out io.Writer, elements []any) error
+}
+
+// NewYAMLInspector generates a new inspector that writes elements as a
+// YAML sequence.
+func NewYAMLInspector(out io.Writer) Inspector {
+	return newStructuredInspector(out, writeYAML)
+}
+
+// NewNDJSONInspector generates a new inspector that writes each element
+// as compact JSON on a separate line (newline-delimited JSON). Elements are
+// written as they're inspected.
+func NewNDJSONInspector(out io.Writer) Inspector {
+	if out == nil {
+		out = io.Discard
+	}
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	return &ndjsonInspector{enc: enc}
+}
+
+// NewCSVInspector generates a new inspector that writes elements as CSV,
+// with a header row. Nested fields are flattened into separate columns,
+// using dot-separated names (for example, "State.Status"). Lists are
+// written as JSON.
+func NewCSVInspector(out io.Writer) Inspector {
+	return newStructuredInspector(out, writeCSV)
+}
+
+func newStructuredInspector(out io.Writer, write func(io.Writer, []any) error) *structuredInspector {
+	if out == nil {
+		out = io.Discard
+	}
+	return &structuredInspector{out: out, write: write}
+}
+
+func (s *structuredInspector) Inspect(typedElement any, rawElement []byte) error {
+	v, err := decodeElement(typedElement, rawElement)
+	if err != nil {
+		return err
+	}
+	s.elements = append(s.elements, v)
+	return nil
+}
+
+func (s *structuredInspector) Flush() error {
+	return s.write(s.out, s.elements)
+}
+
+func writeYAML(out io.Writer, elements []any) error {
+	if len(elements) == 0 {
+		_, err := io.WriteString(out, "[]\n")
+		return err
+	}
+	enc := yaml.NewEncoder(out)
+	enc.SetIndent(4)
+	if err := enc.Encode(formatting.YAMLValue(elements)); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// ndjsonInspector writes each element as compact JSON on a separate line.
+type ndjsonInspector struct {
+	enc *json.Encoder
+}
+
+func (s *ndjsonInspector) Inspect(typedElement any, rawElement []byte) error {
+	v, err := decodeElement(typedElement, rawElement)
+	if err != nil {
+		return err
+	}
+	return s.enc.Encode(v)
+}
+
+func (*ndjsonInspector) Flush() error {
+	return nil
+}
+
+// decodeElement returns the element as a generic value, decoded from its raw
+// JSON if available. Numbers are decoded as [json.Number] to preserve their
+// precision.
+func decodeElement(typedElement any, rawElement []byte) (any, error) {
+	if rawElement == nil {
+		var err error
+		rawElement, err = json.Marshal(typedElement)
+		if err != nil {
+			return nil, err
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(rawElement))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("unable to read inspect data: %w", err)
+	}
+	return v, nil
+}
+
+func writeCSV(out io.Writer, elements []any) error {
+	rows := make([]map[string]string, 0, len(elements))
+	columns := map[string]struct{}{}
+	for _, el := range elements {
+		row := map[string]string{}
+		flatten(row, "", el)
+		for k := range row {
+			columns[k] = struct{}{}
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	fields := slices.Sorted(maps.Keys(columns))
+
+	w := csv.NewWriter(out)
+	_ = w.Write(fields)
+	record := make([]string, len(fields))
+	for _, row := range rows {
+		for i, f := range fields {
+			record[i] = row[f]
+		}
+		_ = w.Write(record)
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// flatten adds the value to row, using dot-separated keys for nested objects.
+func flatten(row map[string]string, prefix string, v any) {
+	switch val := v.(type) {
+	case map[string]any:
+		if len(val) == 0 && prefix != "" {
+			row[prefix] = ""
+			return
+		}
+		for k, child := range val {
+			if prefix != "" {
+				k = prefix + "." + k
+			}
+			flatten(row, k, child)
+		}
+	case nil:
+		row[prefix] = ""
+	case string:
+		row[prefix] = val
+	case json.Number, bool:
+		row[prefix] = fmt.Sprint(val)
+	default:
+		out, err := json.Marshal(val)
+		if err != nil {
+			out = []byte(fmt.Sprint(val))
+		}
+		row[prefix] = string(out)
+	}
+}
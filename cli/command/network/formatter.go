@@ -25,11 +25,11 @@
 // newFormat returns a [formatter.Format] for rendering a networkContext.
 func newFormat(source string, quiet bool) formatter.Format {
 	switch source {
-	case formatter.TableFormatKey:
+	case formatter.TableFormatKey, formatter.CSVFormatKey:
 		if quiet {
 			return formatter.DefaultQuietFormat
 		}
-		return defaultNetworkTableFormat
+		return formatter.TableOrCSV(source, defaultNetworkTableFormat)
 	case formatter.RawFormatKey:
 		if quiet {
 			return `network_id: {{.ID}}`
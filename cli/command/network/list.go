@@ -19,6 +19,7 @@ type listOptions struct {
 	noTrunc bool
 	format  string
 	filter  opts.FilterOpt
+	list    formatter.ListOptions
 }
 
 func newListCommand(dockerCLI command.Cli) *cobra.Command {
@@ -41,6 +42,7 @@ func newListCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVar(&options.noTrunc, "no-trunc", false, "Do not truncate the output")
 	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
 	flags.VarP(&options.filter, "filter", "f", `Provide filter values (e.g. "driver=bridge")`)
+	options.list.InstallFlags(flags)
 
 	return cmd
 }
@@ -69,6 +71,8 @@ func runList(ctx context.Context, dockerCLI command.Cli, options listOptions) er
 		Output: dockerCLI.Out(),
 		Format: newFormat(format, options.quiet),
 		Trunc:  !options.noTrunc,
+
+		ListOptions: options.list,
 	}
 	return formatWrite(networksCtx, res)
 }
@@ -84,11 +84,11 @@ func newFormat(source string, quiet bool) formatter.Format {
 	switch source {
 	case formatter.PrettyFormatKey:
 		return nodeInspectPrettyTemplate
-	case formatter.TableFormatKey:
+	case formatter.TableFormatKey, formatter.CSVFormatKey:
 		if quiet {
 			return formatter.DefaultQuietFormat
 		}
-		return defaultNodeTableFormat
+		return formatter.TableOrCSV(source, defaultNodeTableFormat)
 	case formatter.RawFormatKey:
 		if quiet {
 			return `node_id: {{.ID}}`
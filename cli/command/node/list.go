@@ -18,6 +18,7 @@ type listOptions struct {
 	quiet  bool
 	format string
 	filter opts.FilterOpt
+	list   formatter.ListOptions
 }
 
 func newListCommand(dockerCLI command.Cli) *cobra.Command {
@@ -38,6 +39,7 @@ func newListCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display IDs")
 	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
+	options.list.InstallFlags(flags)
 
 	return cmd
 }
@@ -72,6 +74,8 @@ func runList(ctx context.Context, dockerCLI command.Cli, options listOptions) er
 	nodesCtx := formatter.Context{
 		Output: dockerCLI.Out(),
 		Format: newFormat(format, options.quiet),
+
+		ListOptions: options.list,
 	}
 	sort.Slice(res.Items, func(i, j int) bool {
 		return sortorder.NaturalLess(res.Items[i].Description.Hostname, res.Items[j].Description.Hostname)
@@ -24,11 +24,11 @@
 // newFormat returns a Format for rendering using a pluginContext.
 func newFormat(source string, quiet bool) formatter.Format {
 	switch source {
-	case formatter.TableFormatKey:
+	case formatter.TableFormatKey, formatter.CSVFormatKey:
 		if quiet {
 			return formatter.DefaultQuietFormat
 		}
-		return defaultPluginTableFormat
+		return formatter.TableOrCSV(source, defaultPluginTableFormat)
 	case formatter.RawFormatKey:
 		if quiet {
 			return `plugin_id: {{.ID}}`
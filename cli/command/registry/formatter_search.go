@@ -20,8 +20,8 @@
 // newFormat returns a Format for rendering using a searchContext.
 func newFormat(source string) formatter.Format {
 	switch source {
-	case "", formatter.TableFormatKey:
-		return defaultSearchTableFormat
+	case "", formatter.TableFormatKey, formatter.CSVFormatKey:
+		return formatter.TableOrCSV(source, defaultSearchTableFormat)
 	}
 	return formatter.Format(source)
 }
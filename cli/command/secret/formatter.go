@@ -38,11 +38,11 @@ func newFormat(source string, quiet bool) formatter.Format {
 	switch source {
 	case formatter.PrettyFormatKey:
 		return secretInspectPrettyTemplate
-	case formatter.TableFormatKey:
+	case formatter.TableFormatKey, formatter.CSVFormatKey:
 		if quiet {
 			return formatter.DefaultQuietFormat
 		}
-		return defaultSecretTableFormat
+		return formatter.TableOrCSV(source, defaultSecretTableFormat)
 	}
 	return formatter.Format(source)
 }
@@ -8,6 +8,7 @@
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	cliopts "github.com/docker/cli/opts"
+	"github.com/docker/cli/pkg/kvfile"
 	"github.com/moby/moby/api/types/swarm"
 	"github.com/moby/moby/client"
 	"github.com/spf13/cobra"
@@ -41,6 +42,7 @@ func newCreateCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.Var(&opts.containerLabels, flagContainerLabel, "Container labels")
 	flags.VarP(&opts.env, flagEnv, "e", "Set environment variables")
 	flags.Var(&opts.envFile, flagEnvFile, "Read in a file of environment variables")
+	flags.StringVar(&opts.envFileFormat, flagEnvFileFormat, string(kvfile.FormatPlain), `Format of env-files ("plain", "dotenv")`)
 	flags.Var(&opts.mounts, flagMount, "Attach a filesystem mount to the service")
 	flags.Var(&opts.constraints, flagConstraint, "Placement constraints")
 	flags.Var(&opts.placementPrefs, flagPlacementPref, "Add a placement preference")
@@ -83,6 +85,7 @@ func newCreateCommand(dockerCLI command.Cli) *cobra.Command {
 	_ = cmd.RegisterFlagCompletionFunc(flagMode, completion.FromList("replicated", "global", "replicated-job", "global-job"))
 	_ = cmd.RegisterFlagCompletionFunc(flagEnv, completion.EnvVarNames()) // TODO(thaJeztah): flagEnvRemove (needs to read current env-vars on the service)
 	_ = cmd.RegisterFlagCompletionFunc(flagEnvFile, completion.FileNames())
+	_ = cmd.RegisterFlagCompletionFunc(flagEnvFileFormat, completion.FromList(string(kvfile.FormatPlain), string(kvfile.FormatDotenv)))
 	_ = cmd.RegisterFlagCompletionFunc(flagNetwork, completion.NetworkNames(dockerCLI))
 	_ = cmd.RegisterFlagCompletionFunc(flagRestartCondition, completion.FromList("none", "on-failure", "any"))
 	_ = cmd.RegisterFlagCompletionFunc(flagRollbackOrder, completion.FromList("start-first", "stop-first"))
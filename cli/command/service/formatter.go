@@ -598,11 +598,11 @@ func (ctx *serviceInspectContext) CapabilityDrop() string {
 // NewListFormat returns a Format for rendering using a service Context
 func NewListFormat(source string, quiet bool) formatter.Format {
 	switch source {
-	case formatter.TableFormatKey:
+	case formatter.TableFormatKey, formatter.CSVFormatKey:
 		if quiet {
 			return formatter.DefaultQuietFormat
 		}
-		return defaultServiceTableFormat
+		return formatter.TableOrCSV(source, defaultServiceTableFormat)
 	case formatter.RawFormatKey:
 		if quiet {
 			return `id: {{.ID}}`
@@ -16,6 +16,7 @@ type listOptions struct {
 	quiet  bool
 	format string
 	filter opts.FilterOpt
+	list   formatter.ListOptions
 }
 
 func newListCommand(dockerCLI command.Cli) *cobra.Command {
@@ -37,6 +38,7 @@ func newListCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display IDs")
 	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
+	options.list.InstallFlags(flags)
 
 	_ = cmd.RegisterFlagCompletionFunc("filter", completeServiceListFilters(dockerCLI))
 
@@ -69,6 +71,8 @@ func runList(ctx context.Context, dockerCLI command.Cli, options listOptions) er
 	servicesCtx := formatter.Context{
 		Output: dockerCLI.Out(),
 		Format: NewListFormat(format, options.quiet),
+
+		ListOptions: options.list,
 	}
 	return ListFormatWrite(servicesCtx, res)
 }
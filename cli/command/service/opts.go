@@ -16,6 +16,7 @@
 
 	"github.com/docker/cli/opts"
 	"github.com/docker/cli/opts/swarmopts"
+	"github.com/docker/cli/pkg/kvfile"
 	gogotypes "github.com/gogo/protobuf/types"
 	"github.com/google/shlex"
 	"github.com/moby/moby/api/types/container"
@@ -555,6 +556,7 @@ type serviceOptions struct {
 	hostname        string
 	env             opts.ListOpts
 	envFile         opts.ListOpts
+	envFileFormat   string
 	workdir         string
 	user            string
 	groups          opts.ListOpts
@@ -682,7 +684,7 @@ func (options *serviceOptions) ToStopGracePeriod(flags *pflag.FlagSet) *time.Dur
 // makeEnv gets the environment variables from the command line options and
 // returns a slice of strings to use in the service spec when doing ToService
 func (options *serviceOptions) makeEnv() ([]string, error) {
-	envVariables, err := opts.ReadKVEnvStrings(options.envFile.GetSlice(), options.env.GetSlice())
+	envVariables, err := opts.ReadKVEnvStringsWithFormat(options.envFile.GetSlice(), options.env.GetSlice(), kvfile.Format(options.envFileFormat))
 	if err != nil {
 		return nil, err
 	}
@@ -1006,6 +1008,7 @@ func addServiceFlags(flags *pflag.FlagSet, options *serviceOptions, defaultFlagV
 	flagEntrypoint              = "entrypoint"
 	flagEnv                     = "env"
 	flagEnvFile                 = "env-file"
+	flagEnvFileFormat           = "env-file-format"
 	flagEnvRemove               = "env-rm"
 	flagEnvAdd                  = "env-add"
 	flagGenericResourcesRemove  = "generic-resource-rm"
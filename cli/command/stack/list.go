@@ -45,8 +45,8 @@ func runList(ctx context.Context, dockerCLI command.Cli, opts listOptions) error
 	}
 
 	format := formatter.Format(opts.format)
-	if format == "" || format == formatter.TableFormatKey {
-		format = stackTableFormat
+	if format == "" || format == formatter.TableFormatKey || format == formatter.CSVFormatKey {
+		format = formatter.TableOrCSV(opts.format, stackTableFormat)
 	}
 	stackCtx := formatter.Context{
 		Output: dockerCLI.Out(),
@@ -31,6 +31,7 @@ func newSystemCommand(dockerCLI command.Cli) *cobra.Command {
 		newDiskUsageCommand(dockerCLI),
 		newPruneCommand(dockerCLI),
 		newDialStdioCommand(dockerCLI),
+		newConnectionBrokerCommand(dockerCLI),
 	)
 
 	return cmd
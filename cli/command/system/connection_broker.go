@@ -0,0 +1,67 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/connhelper"
+	"github.com/docker/cli/cli/connhelper/broker"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type connectionBrokerOptions struct {
+	host        string
+	socket      string
+	idleTimeout time.Duration
+}
+
+// newConnectionBrokerCommand creates a new cobra.Command for `docker system connection-broker`
+func newConnectionBrokerCommand(command.Cli) *cobra.Command {
+	var opts connectionBrokerOptions
+	cmd := &cobra.Command{
+		Use:    "connection-broker",
+		Short:  "Run a connection broker that keeps connections to the daemon open. Should not be invoked manually.",
+		Args:   cli.NoArgs,
+		Hidden: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConnectionBroker(cmd.Context(), opts)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.host, "host", "", "Daemon host to connect to")
+	flags.StringVar(&opts.socket, "socket", "", "Path of the socket to listen on")
+	flags.DurationVar(&opts.idleTimeout, "idle-timeout", 10*time.Minute, "Exit after the broker was not used for this duration")
+	return cmd
+}
+
+func runConnectionBroker(ctx context.Context, opts connectionBrokerOptions) error {
+	if opts.host == "" || opts.socket == "" {
+		return errors.New("both --host and --socket must be set")
+	}
+	helper, err := connhelper.GetConnectionHelper(opts.host)
+	if err != nil {
+		return err
+	}
+	if helper == nil {
+		return fmt.Errorf("host %q does not use a connection helper", opts.host)
+	}
+	l, err := broker.Listen(opts.socket)
+	if err != nil {
+		if errors.Is(err, broker.ErrRunning) {
+			logrus.Debugf("connection broker is already running on %s", opts.socket)
+			return nil
+		}
+		return err
+	}
+	return broker.Serve(ctx, l, func(ctx context.Context) (net.Conn, error) {
+		return helper.Dialer(ctx, "tcp", "")
+	}, opts.idleTimeout)
+}
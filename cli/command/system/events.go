@@ -57,6 +57,9 @@ func newEventsCommand(dockerCLI command.Cli) *cobra.Command {
 }
 
 func runEvents(ctx context.Context, dockerCLI command.Cli, options *eventsOptions) error {
+	if options.format == formatter.CSVFormatKey {
+		return errCSVNotSupported
+	}
 	tmpl, err := makeTemplate(options.format)
 	if err != nil {
 		return cli.StatusError{
@@ -104,8 +107,11 @@ func makeTemplate(format string) (*template.Template, error) {
 	switch format {
 	case "":
 		return nil, nil
-	case formatter.JSONFormatKey:
+	case formatter.JSONFormatKey, formatter.NDJSONFormatKey:
 		format = formatter.JSONFormat
+	case formatter.YAMLFormatKey:
+		// events are streamed, so print each event as a separate document.
+		format = "---\n" + formatter.YAMLFormat
 	}
 	tmpl, err := templates.Parse(format)
 	if err != nil {
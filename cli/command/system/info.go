@@ -83,6 +83,9 @@ func newInfoCommand(dockerCLI command.Cli) *cobra.Command {
 }
 
 func runInfo(ctx context.Context, cmd *cobra.Command, dockerCli command.Cli, opts *infoOptions) error {
+	if opts.format == formatter.CSVFormatKey {
+		return errCSVNotSupported
+	}
 	info := dockerInfo{
 		ClientInfo: &clientInfo{
 			// Don't pass a dockerCLI to newClientVersion(), because we currently
@@ -151,8 +154,8 @@ func addServerInfo(ctx context.Context, dockerCli command.Cli, format string, in
 // information, without also making a (potentially expensive) API call.
 func needsServerInfo(template string, info dockerInfo) bool {
 	switch template {
-	case "", formatter.JSONFormat, formatter.JSONFormatKey:
-		// No format (default) and full JSON output need server-side info.
+	case "", formatter.JSONFormat, formatter.JSONFormatKey, formatter.NDJSONFormatKey, formatter.YAMLFormatKey:
+		// No format (default) and full JSON or YAML output need server-side info.
 		return true
 	default:
 		if placeHolders.FindString(template) == "" {
@@ -480,9 +483,16 @@ func printSwarmInfo(output io.Writer, info system.Info) {
 	}
 }
 
+// errCSVNotSupported is returned by the commands in this package that don't
+// support the "csv" format, as their output is not a list.
+var errCSVNotSupported = cli.StatusError{StatusCode: 64, Status: "csv format is not supported"}
+
 func formatInfo(output io.Writer, info dockerInfo, format string) error {
-	if format == formatter.JSONFormatKey {
+	switch format {
+	case formatter.JSONFormatKey, formatter.NDJSONFormatKey:
 		format = formatter.JSONFormat
+	case formatter.YAMLFormatKey:
+		format = formatter.YAMLFormat
 	}
 
 	// Ensure slice/array fields render as `[]` not `null`
@@ -210,6 +210,9 @@ func arch() string {
 }
 
 func runVersion(ctx context.Context, dockerCLI command.Cli, opts *versionOptions) error {
+	if opts.format == formatter.CSVFormatKey {
+		return errCSVNotSupported
+	}
 	var err error
 	tmpl, err := newVersionTemplate(opts.format)
 	if err != nil {
@@ -241,8 +244,10 @@ func newVersionTemplate(templateFormat string) (*template.Template, error) {
 	switch templateFormat {
 	case "":
 		templateFormat = defaultVersionTemplate
-	case formatter.JSONFormatKey:
+	case formatter.JSONFormatKey, formatter.NDJSONFormatKey:
 		templateFormat = formatter.JSONFormat
+	case formatter.YAMLFormatKey:
+		templateFormat = formatter.YAMLFormat
 	}
 	tmpl, err := templates.New("version").Funcs(template.FuncMap{"getDetailsOrder": getDetailsOrder}).Parse(templateFormat)
 	if err != nil {
@@ -8,9 +8,12 @@
 	"strings"
 	"testing"
 
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/internal/test"
 	"github.com/moby/moby/api/types/system"
 	"github.com/moby/moby/client"
+	"github.com/spf13/cobra"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
@@ -143,3 +146,19 @@ func TestVersionFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestCSVFormatNotSupported(t *testing.T) {
+	for _, newCmd := range []func(command.Cli) *cobra.Command{newEventsCommand, newInfoCommand, newVersionCommand} {
+		cmd := newCmd(test.NewFakeCli(&fakeClient{}))
+		t.Run(cmd.Name(), func(t *testing.T) {
+			cmd.SetArgs([]string{"--format", "csv"})
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			err := cmd.Execute()
+			assert.Check(t, is.Equal(err, errCSVNotSupported))
+			var statusErr cli.StatusError
+			assert.Check(t, errors.As(err, &statusErr))
+			assert.Check(t, is.Equal(statusErr.StatusCode, 64))
+		})
+	}
+}
@@ -26,11 +26,11 @@
 // newTaskFormat returns a Format for rendering using a taskContext.
 func newTaskFormat(source string, quiet bool) formatter.Format {
 	switch source {
-	case formatter.TableFormatKey:
+	case formatter.TableFormatKey, formatter.CSVFormatKey:
 		if quiet {
 			return formatter.DefaultQuietFormat
 		}
-		return defaultTaskTableFormat
+		return formatter.TableOrCSV(source, defaultTaskTableFormat)
 	case formatter.RawFormatKey:
 		if quiet {
 			return `id: {{.ID}}`
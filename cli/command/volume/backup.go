@@ -0,0 +1,207 @@
+package volume
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/internal/volumehelper"
+	"github.com/klauspost/compress/zstd"
+	"github.com/moby/moby/client"
+	"github.com/spf13/cobra"
+)
+
+// Backup archives are tar archives that contain the metadata of the volume,
+// the content of the volume in the data directory, and the SHA256 checksums
+// of the files in the data directory, in that order.
+const (
+	backupVersion       = 1
+	backupMetadataFile  = "metadata.json"
+	backupChecksumsFile = "SHA256SUMS"
+	backupDataDir       = "data"
+)
+
+// backupMetadata is the metadata of the volume in a backup archive, which
+// is used to create the volume on restore.
+type backupMetadata struct {
+	Version   int
+	Name      string
+	Driver    string
+	Labels    map[string]string `json:",omitempty"`
+	Options   map[string]string `json:",omitempty"`
+	CreatedAt time.Time
+}
+
+type backupOptions struct {
+	volume string
+	output string
+}
+
+func newBackupCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts backupOptions
+
+	cmd := &cobra.Command{
+		Use:   "backup [OPTIONS] VOLUME",
+		Short: "Back up the content of a volume to a tar archive (streamed to STDOUT by default)",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.volume = args[0]
+			return runBackup(cmd.Context(), dockerCLI, opts)
+		},
+		ValidArgsFunction:     completion.VolumeNames(dockerCLI),
+		DisableFlagsInUseLine: true,
+	}
+
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", `Write to a file, instead of STDOUT. The archive is compressed with zstd if the file name ends with ".zst"`)
+	return cmd
+}
+
+func runBackup(ctx context.Context, dockerCLI command.Cli, opts backupOptions) (retErr error) {
+	var output io.Writer
+	if opts.output == "" {
+		if dockerCLI.Out().IsTerminal() {
+			return errors.New("cowardly refusing to save to a terminal. Use the -o flag or redirect")
+		}
+		output = dockerCLI.Out()
+	} else {
+		// Write to a temporary file, so that an incomplete backup never
+		// replaces an existing file.
+		f, err := os.CreateTemp(filepath.Dir(opts.output), ".docker-volume-backup-*")
+		if err != nil {
+			return fmt.Errorf("failed to back up volume: %w", err)
+		}
+		defer func() {
+			if retErr != nil {
+				_ = f.Close()
+				_ = os.Remove(f.Name())
+				return
+			}
+			if retErr = f.Close(); retErr == nil {
+				retErr = os.Rename(f.Name(), opts.output)
+			}
+		}()
+		output = f
+		if strings.HasSuffix(opts.output, ".zst") {
+			enc, err := zstd.NewWriter(f)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := enc.Close(); retErr == nil {
+					retErr = err
+				}
+			}()
+			output = enc
+		}
+	}
+
+	apiClient := dockerCLI.Client()
+	res, err := apiClient.VolumeInspect(ctx, opts.volume, client.VolumeInspectOptions{})
+	if err != nil {
+		return err
+	}
+	helper, cleanup, err := volumehelper.Create(ctx, apiClient, res.Volume.Name, true)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	content, err := apiClient.CopyFromContainer(ctx, helper, client.CopyFromContainerOptions{SourcePath: volumehelper.MountPath})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = content.Content.Close() }()
+
+	return writeBackup(output, backupMetadata{
+		Version:   backupVersion,
+		Name:      res.Volume.Name,
+		Driver:    res.Volume.Driver,
+		Labels:    res.Volume.Labels,
+		Options:   res.Volume.Options,
+		CreatedAt: time.Now().UTC(),
+	}, content.Content)
+}
+
+// writeBackup writes a backup archive with the content of the volume, which
+// is read from an archive that's produced by the archive copy API.
+func writeBackup(w io.Writer, meta backupMetadata, content io.Reader) error {
+	tw := tar.NewWriter(w)
+	metaJSON, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, backupMetadataFile, metaJSON, meta.CreatedAt); err != nil {
+		return err
+	}
+
+	var checksums bytes.Buffer
+	tr := tar.NewReader(content)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		name, ok := rebaseEntry(hdr.Name, strings.TrimPrefix(volumehelper.MountPath, "/"), backupDataDir)
+		if !ok {
+			return fmt.Errorf("unexpected file in volume archive: %s", hdr.Name)
+		}
+		hdr.Name = name
+		if hdr.Typeflag == tar.TypeLink {
+			hdr.Linkname, _ = rebaseEntry(hdr.Linkname, strings.TrimPrefix(volumehelper.MountPath, "/"), backupDataDir)
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		h := sha256.New()
+		if _, err := io.Copy(io.MultiWriter(tw, h), tr); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(&checksums, "%x  %s\n", h.Sum(nil), name)
+	}
+	if err := writeTarFile(tw, backupChecksumsFile, checksums.Bytes(), meta.CreatedAt); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o644,
+		Size:     int64(len(data)),
+		ModTime:  modTime,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// rebaseEntry replaces the top-level directory of the path of an archive
+// entry, and returns false if the entry isn't in the directory.
+func rebaseEntry(name, from, to string) (string, bool) {
+	rest, ok := strings.CutPrefix(name, from)
+	if !ok || (rest != "" && rest[0] != '/') {
+		return "", false
+	}
+	return to + rest, true
+}
@@ -0,0 +1,215 @@
+package volume
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/internal/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/moby/go-archive/compression"
+	"github.com/moby/moby/api/types/volume"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type testEntry struct {
+	name    string
+	content string
+}
+
+var cmpTestEntry = cmp.AllowUnexported(testEntry{})
+
+func writeTestArchive(t *testing.T, entries ...testEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0o644, Typeflag: tar.TypeReg, Size: int64(len(e.content))}
+		if e.name[len(e.name)-1] == '/' {
+			hdr.Typeflag, hdr.Mode = tar.TypeDir, 0o755
+		}
+		assert.NilError(t, tw.WriteHeader(hdr))
+		_, err := tw.Write([]byte(e.content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func readTestArchive(t *testing.T, r io.Reader) []testEntry {
+	t.Helper()
+	var entries []testEntry
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return entries
+		}
+		assert.NilError(t, err)
+		content, err := io.ReadAll(tr)
+		assert.NilError(t, err)
+		entries = append(entries, testEntry{name: hdr.Name, content: string(content)})
+	}
+}
+
+func TestVolumeBackupRestore(t *testing.T) {
+	volumeContent := writeTestArchive(t,
+		testEntry{name: "volume/"},
+		testEntry{name: "volume/config.yml", content: "debug: true\n"},
+		testEntry{name: "volume/db/"},
+		testEntry{name: "volume/db/data.db", content: "some data"},
+	)
+	backupClient := &fakeClient{
+		volumeInspectFunc: func(volumeID string) (client.VolumeInspectResult, error) {
+			return client.VolumeInspectResult{Volume: volume.Volume{
+				Name:    volumeID,
+				Driver:  "local",
+				Labels:  map[string]string{"app": "web"},
+				Options: map[string]string{"type": "tmpfs"},
+			}}, nil
+		},
+		copyFromFunc: func(containerID string, options client.CopyFromContainerOptions) (client.CopyFromContainerResult, error) {
+			assert.Check(t, is.Equal(containerID, "helper"))
+			assert.Check(t, is.Equal(options.SourcePath, "/volume"))
+			return client.CopyFromContainerResult{Content: io.NopCloser(bytes.NewReader(volumeContent))}, nil
+		},
+	}
+	backupFile := filepath.Join(t.TempDir(), "data.tar.zst")
+	cmd := newBackupCommand(test.NewFakeCli(backupClient))
+	cmd.SetArgs([]string{"-o", backupFile, "data"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Len(backupClient.containers, 0))
+
+	f, err := os.Open(backupFile)
+	assert.NilError(t, err)
+	defer f.Close()
+	r, err := compression.DecompressStream(f)
+	assert.NilError(t, err)
+	entries := readTestArchive(t, r)
+	assert.Assert(t, is.Len(entries, 6))
+	assert.Check(t, is.Equal(entries[0].name, "metadata.json"))
+	assert.Check(t, is.Contains(entries[0].content, `"Driver": "local"`))
+	assert.Check(t, is.DeepEqual(entries[1:5], []testEntry{
+		{name: "data/"},
+		{name: "data/config.yml", content: "debug: true\n"},
+		{name: "data/db/"},
+		{name: "data/db/data.db", content: "some data"},
+	}, cmpTestEntry))
+	assert.Check(t, is.Equal(entries[5].name, "SHA256SUMS"))
+	assert.Check(t, is.Equal(entries[5].content, ""+
+		"f867fe538171ee003592210869c10c6cec11e4e479b1c90c78738c1678e5786d  data/config.yml\n"+
+		"1307990e6ba5ca145eb35e99182a9bec46531bc54ddf656a602c780fa0240dee  data/db/data.db\n"))
+
+	var created client.VolumeCreateOptions
+	var restored []testEntry
+	restoreClient := &fakeClient{
+		volumeInspectFunc: func(string) (client.VolumeInspectResult, error) {
+			return client.VolumeInspectResult{}, errdefs.ErrNotFound
+		},
+		volumeCreateFunc: func(options client.VolumeCreateOptions) (client.VolumeCreateResult, error) {
+			created = options
+			return client.VolumeCreateResult{}, nil
+		},
+		copyToFunc: func(containerID string, options client.CopyToContainerOptions) (client.CopyToContainerResult, error) {
+			assert.Check(t, is.Equal(containerID, "helper"))
+			assert.Check(t, is.Equal(options.DestinationPath, "/"))
+			restored = readTestArchive(t, options.Content)
+			return client.CopyToContainerResult{}, nil
+		},
+	}
+	cli := test.NewFakeCli(restoreClient)
+	cmd = newRestoreCommand(cli)
+	cmd.SetArgs([]string{"-i", backupFile, "data-copy"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "data-copy\n"))
+	assert.Check(t, is.DeepEqual(created, client.VolumeCreateOptions{
+		Name:       "data-copy",
+		Driver:     "local",
+		DriverOpts: map[string]string{"type": "tmpfs"},
+		Labels:     map[string]string{"app": "web"},
+	}))
+	assert.Check(t, is.DeepEqual(restored, []testEntry{
+		{name: "volume/"},
+		{name: "volume/config.yml", content: "debug: true\n"},
+		{name: "volume/db/"},
+		{name: "volume/db/data.db", content: "some data"},
+	}, cmpTestEntry))
+	assert.Check(t, is.Len(restoreClient.containers, 0))
+}
+
+func TestVolumeRestoreErrors(t *testing.T) {
+	metadata := testEntry{name: "metadata.json", content: `{"Version":1,"Name":"data","Driver":"local"}`}
+	dataFile := testEntry{name: "data/file", content: "content"}
+	testCases := []struct {
+		name          string
+		entries       []testEntry
+		expectedError string
+	}{
+		{
+			name: "checksum mismatch",
+			entries: []testEntry{metadata, dataFile, {
+				name:    "SHA256SUMS",
+				content: "0000000000000000000000000000000000000000000000000000000000000000  data/file\n",
+			}},
+			expectedError: "backup archive is corrupt: checksum mismatch for data/file",
+		},
+		{
+			name:          "missing checksum",
+			entries:       []testEntry{metadata, dataFile, {name: "SHA256SUMS"}},
+			expectedError: "backup archive is corrupt: 1 file(s) have no checksum",
+		},
+		{
+			name:          "missing checksums",
+			entries:       []testEntry{metadata, dataFile},
+			expectedError: "invalid backup archive: no SHA256SUMS",
+		},
+		{
+			name:          "missing metadata",
+			entries:       []testEntry{{name: "SHA256SUMS"}},
+			expectedError: "invalid backup archive: no metadata.json",
+		},
+		{
+			name:          "unsupported version",
+			entries:       []testEntry{{name: "metadata.json", content: `{"Version":2}`}, {name: "SHA256SUMS"}},
+			expectedError: "unsupported backup archive version: 2",
+		},
+		{
+			name:          "unexpected file",
+			entries:       []testEntry{metadata, {name: "etc/passwd"}},
+			expectedError: "invalid backup archive: unexpected file etc/passwd",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			backupFile := filepath.Join(t.TempDir(), "backup.tar")
+			assert.NilError(t, os.WriteFile(backupFile, writeTestArchive(t, tc.entries...), 0o644))
+			apiClient := &fakeClient{
+				volumeCreateFunc: func(client.VolumeCreateOptions) (client.VolumeCreateResult, error) {
+					return client.VolumeCreateResult{}, errors.New("volume should not be created")
+				},
+			}
+			cmd := newRestoreCommand(test.NewFakeCli(apiClient))
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs([]string{"-i", backupFile, "data"})
+			assert.Check(t, is.Error(cmd.Execute(), tc.expectedError))
+		})
+	}
+}
+
+func TestVolumeBackupToTerminal(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.Out().SetIsTerminal(true)
+	cmd := newBackupCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"data"})
+	assert.Check(t, is.Error(cmd.Execute(), "cowardly refusing to save to a terminal. Use the -o flag or redirect"))
+}
@@ -2,6 +2,9 @@
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"strings"
 
 	"github.com/moby/moby/client"
 )
@@ -13,6 +16,9 @@ type fakeClient struct {
 	volumeListFunc    func(client.VolumeListOptions) (client.VolumeListResult, error)
 	volumeRemoveFunc  func(volumeID string, force bool) error
 	volumePruneFunc   func(opts client.VolumePruneOptions) (client.VolumePruneResult, error)
+	copyFromFunc      func(containerID string, options client.CopyFromContainerOptions) (client.CopyFromContainerResult, error)
+	copyToFunc        func(containerID string, options client.CopyToContainerOptions) (client.CopyToContainerResult, error)
+	containers        []string
 }
 
 func (c *fakeClient) VolumeCreate(_ context.Context, options client.VolumeCreateOptions) (client.VolumeCreateResult, error) {
@@ -49,3 +55,38 @@ func (c *fakeClient) VolumeRemove(_ context.Context, volumeID string, options cl
 	}
 	return client.VolumeRemoveResult{}, nil
 }
+
+// ImageImport returns the ID of the helper image that's imported from an
+// empty archive.
+func (*fakeClient) ImageImport(_ context.Context, source client.ImageImportSource, _ string, _ client.ImageImportOptions) (client.ImageImportResult, error) {
+	_, _ = io.Copy(io.Discard, source.Source)
+	return io.NopCloser(strings.NewReader(`{"status":"sha256:7f4b1e2a9c3d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f"}` + "\r\n")), nil
+}
+
+func (*fakeClient) ImageRemove(context.Context, string, client.ImageRemoveOptions) (client.ImageRemoveResult, error) {
+	return client.ImageRemoveResult{}, nil
+}
+
+func (c *fakeClient) ContainerCreate(_ context.Context, options client.ContainerCreateOptions) (client.ContainerCreateResult, error) {
+	c.containers = append(c.containers, options.HostConfig.Mounts[0].Source)
+	return client.ContainerCreateResult{ID: "helper"}, nil
+}
+
+func (c *fakeClient) ContainerRemove(context.Context, string, client.ContainerRemoveOptions) (client.ContainerRemoveResult, error) {
+	c.containers = c.containers[:len(c.containers)-1]
+	return client.ContainerRemoveResult{}, nil
+}
+
+func (c *fakeClient) CopyFromContainer(_ context.Context, containerID string, options client.CopyFromContainerOptions) (client.CopyFromContainerResult, error) {
+	if c.copyFromFunc != nil {
+		return c.copyFromFunc(containerID, options)
+	}
+	return client.CopyFromContainerResult{Content: http.NoBody}, nil
+}
+
+func (c *fakeClient) CopyToContainer(_ context.Context, containerID string, options client.CopyToContainerOptions) (client.CopyToContainerResult, error) {
+	if c.copyToFunc != nil {
+		return c.copyToFunc(containerID, options)
+	}
+	return client.CopyToContainerResult{}, nil
+}
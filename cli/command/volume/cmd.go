@@ -23,10 +23,12 @@ func newVolumeCommand(dockerCLI command.Cli) *cobra.Command {
 		DisableFlagsInUseLine: true,
 	}
 	cmd.AddCommand(
+		newBackupCommand(dockerCLI),
 		newCreateCommand(dockerCLI),
 		newInspectCommand(dockerCLI),
 		newListCommand(dockerCLI),
 		newRemoveCommand(dockerCLI),
+		newRestoreCommand(dockerCLI),
 		newPruneCommand(dockerCLI),
 		newUpdateCommand(dockerCLI),
 	)
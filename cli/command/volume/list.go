@@ -23,6 +23,7 @@ type listOptions struct {
 	format  string
 	cluster bool
 	filter  opts.FilterOpt
+	list    formatter.ListOptions
 }
 
 func newListCommand(dockerCLI command.Cli) *cobra.Command {
@@ -47,6 +48,7 @@ func newListCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVar(&options.cluster, "cluster", false, "Display only cluster volumes, and use cluster volume list formatting")
 	_ = flags.SetAnnotation("cluster", "version", []string{"1.42"})
 	_ = flags.SetAnnotation("cluster", "swarm", []string{"manager"})
+	options.list.InstallFlags(flags)
 
 	return cmd
 }
@@ -92,6 +94,8 @@ func runList(ctx context.Context, dockerCLI command.Cli, options listOptions) er
 	volumeCtx := formatter.Context{
 		Output: dockerCLI.Out(),
 		Format: formatter.NewVolumeFormat(format, options.quiet),
+
+		ListOptions: options.list,
 	}
 	return formatter.VolumeWrite(volumeCtx, res.Items)
 }
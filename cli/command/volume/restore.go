@@ -0,0 +1,215 @@
+package volume
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/internal/volumehelper"
+	"github.com/moby/go-archive/compression"
+	"github.com/moby/moby/client"
+	"github.com/spf13/cobra"
+)
+
+type restoreOptions struct {
+	volume string
+	input  string
+}
+
+func newRestoreCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts restoreOptions
+
+	cmd := &cobra.Command{
+		Use:   "restore [OPTIONS] VOLUME",
+		Short: "Restore the content of a volume from a backup archive",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.volume = args[0]
+			return runRestore(cmd.Context(), dockerCLI, opts)
+		},
+		ValidArgsFunction:     completion.VolumeNames(dockerCLI),
+		DisableFlagsInUseLine: true,
+	}
+
+	cmd.Flags().StringVarP(&opts.input, "input", "i", "", "Read from a backup archive file")
+	_ = cmd.MarkFlagRequired("input")
+	return cmd
+}
+
+// runRestore verifies the checksums in the backup archive, and copies its
+// content into the volume. The volume is created with the driver, labels,
+// and options of the backed up volume if it doesn't exist.
+func runRestore(ctx context.Context, dockerCLI command.Cli, opts restoreOptions) error {
+	var meta *backupMetadata
+	err := readBackup(opts.input, func(r io.Reader) (err error) {
+		meta, err = verifyBackup(r)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	apiClient := dockerCLI.Client()
+	if _, err := apiClient.VolumeInspect(ctx, opts.volume, client.VolumeInspectOptions{}); errdefs.IsNotFound(err) {
+		if _, err := apiClient.VolumeCreate(ctx, client.VolumeCreateOptions{
+			Name:       opts.volume,
+			Driver:     meta.Driver,
+			DriverOpts: meta.Options,
+			Labels:     meta.Labels,
+		}); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	helper, cleanup, err := volumehelper.Create(ctx, apiClient, opts.volume, false)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	err = readBackup(opts.input, func(r io.Reader) error {
+		pr, pw := io.Pipe()
+		go func() {
+			_ = pw.CloseWithError(writeVolumeContent(r, pw))
+		}()
+		_, err := apiClient.CopyToContainer(ctx, helper, client.CopyToContainerOptions{
+			DestinationPath: "/",
+			Content:         pr,
+			CopyUIDGID:      true,
+		})
+		_ = pr.CloseWithError(err)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(dockerCLI.Out(), opts.volume)
+	return nil
+}
+
+// readBackup opens the (optionally compressed) backup archive, and reads it
+// with the given function.
+func readBackup(fileName string, read func(io.Reader) error) error {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	r, err := compression.DecompressStream(f)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r.Close() }()
+	return read(r)
+}
+
+// verifyBackup reads the backup archive, and verifies the checksums of the
+// files in it. It returns the metadata of the backed up volume.
+func verifyBackup(r io.Reader) (*backupMetadata, error) {
+	var meta *backupMetadata
+	var checksums []byte
+	actual := make(map[string]string)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid backup archive: %w", err)
+		}
+		switch hdr.Name {
+		case backupMetadataFile:
+			meta = &backupMetadata{}
+			if err := json.NewDecoder(tr).Decode(meta); err != nil {
+				return nil, fmt.Errorf("invalid backup archive: invalid metadata: %w", err)
+			}
+		case backupChecksumsFile:
+			if checksums, err = io.ReadAll(tr); err != nil {
+				return nil, err
+			}
+		default:
+			if _, ok := rebaseEntry(hdr.Name, backupDataDir, backupDataDir); !ok {
+				return nil, fmt.Errorf("invalid backup archive: unexpected file %s", hdr.Name)
+			}
+			if hdr.Typeflag == tar.TypeReg {
+				h := sha256.New()
+				if _, err := io.Copy(h, tr); err != nil {
+					return nil, err
+				}
+				actual[hdr.Name] = hex.EncodeToString(h.Sum(nil))
+			}
+		}
+	}
+	switch {
+	case meta == nil:
+		return nil, fmt.Errorf("invalid backup archive: no %s", backupMetadataFile)
+	case meta.Version > backupVersion:
+		return nil, fmt.Errorf("unsupported backup archive version: %d", meta.Version)
+	case checksums == nil:
+		return nil, fmt.Errorf("invalid backup archive: no %s", backupChecksumsFile)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		sum, name, ok := strings.Cut(scanner.Text(), "  ")
+		if !ok {
+			return nil, fmt.Errorf("invalid backup archive: invalid checksum: %s", scanner.Text())
+		}
+		if actual[name] != sum {
+			return nil, fmt.Errorf("backup archive is corrupt: checksum mismatch for %s", name)
+		}
+		delete(actual, name)
+	}
+	if len(actual) > 0 {
+		return nil, fmt.Errorf("backup archive is corrupt: %d file(s) have no checksum", len(actual))
+	}
+	return meta, nil
+}
+
+// writeVolumeContent writes the data directory of the backup archive as an
+// archive for the archive copy API, which extracts it into the volume.
+func writeVolumeContent(r io.Reader, w io.Writer) error {
+	dir := strings.TrimPrefix(volumehelper.MountPath, "/")
+	tr := tar.NewReader(r)
+	tw := tar.NewWriter(w)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		name, ok := rebaseEntry(hdr.Name, backupDataDir, dir)
+		if !ok {
+			continue
+		}
+		hdr.Name = name
+		if hdr.Typeflag == tar.TypeLink {
+			hdr.Linkname, _ = rebaseEntry(hdr.Linkname, backupDataDir, dir)
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
@@ -28,11 +28,27 @@
 	ConfigFileName = "config.json"
 	configFileDir  = ".docker"
 	contextsDir    = "contexts"
+
+	// ProjectConfigFileName is the name of the project configuration file,
+	// which is looked up in the current working directory and its parent
+	// directories. Project configuration files can only set a limited set
+	// of options; see [configfile.ConfigFile.LoadLayers].
+	ProjectConfigFileName = ".docker-cli.json"
+
+	// ContextFileName is the name of the file that selects the context to use
+	// for the directory it is in, and its subdirectories. Like the project
+	// configuration file, it is looked up in the current working directory
+	// and its parent directories.
+	ContextFileName = ".docker-context"
 )
 
 var (
 	initConfigDir = new(sync.Once)
 	configDir     string
+
+	// systemConfigFile and getWorkingDir are variables for testing.
+	systemConfigFile = defaultSystemConfigFile()
+	getWorkingDir    = os.Getwd
 )
 
 // resetConfigDir is used in testing to reset the "configDir" package variable
@@ -84,6 +100,49 @@ func Dir() string {
 	return configDir
 }
 
+// SystemConfigFile returns the location of the system-wide configuration
+// file, which provides defaults for all users. Options in the user's
+// configuration file take precedence over the system configuration.
+func SystemConfigFile() string {
+	return systemConfigFile
+}
+
+// ProjectConfigFile returns the location of the project configuration file
+// ([ProjectConfigFileName]), looking for it in the current working directory
+// and its parent directories. It returns an empty string if no project
+// configuration file was found.
+func ProjectConfigFile() string {
+	return findInParents(ProjectConfigFileName)
+}
+
+// ContextFile returns the location of the context file ([ContextFileName]),
+// looking for it in the current working directory and its parent directories.
+// It returns an empty string if no context file was found.
+func ContextFile() string {
+	return findInParents(ContextFileName)
+}
+
+// findInParents looks for a regular file with the given name in the current
+// working directory and its parent directories, and returns the path of the
+// first file found.
+func findInParents(name string) string {
+	dir, err := getWorkingDir()
+	if err != nil {
+		return ""
+	}
+	for {
+		fn := filepath.Join(dir, name)
+		if fi, err := os.Stat(fn); err == nil && fi.Mode().IsRegular() {
+			return fn
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
 // ContextStoreDir returns the directory the docker contexts are stored in
 func ContextStoreDir() string {
 	return filepath.Join(Dir(), contextsDir)
@@ -134,24 +193,61 @@ func load(configDir string) (*configfile.ConfigFile, error) {
 	filename := filepath.Join(configDir, ConfigFileName)
 	configFile := configfile.New(filename)
 
-	file, err := os.Open(filename)
-	if err != nil {
-		if os.IsNotExist(err) {
-			// It is OK for no configuration file to be present, in which
-			// case we return a default struct.
-			return configFile, nil
+	layers := make([]configfile.Layer, 0, 3)
+	if fn := SystemConfigFile(); fn != "" {
+		layer, err := readLayer(configfile.ScopeSystem, fn)
+		if err != nil {
+			return configFile, err
+		}
+		if layer != nil {
+			layers = append(layers, *layer)
 		}
-		// Any other error happening when failing to read the file must be returned.
-		return configFile, fmt.Errorf("loading config file: %w", err)
 	}
-	defer func() { _ = file.Close() }()
-	err = configFile.LoadFromReader(file)
+
+	userLayer, err := readLayer(configfile.ScopeUser, filename)
 	if err != nil {
-		err = fmt.Errorf("parsing config file (%s): %w", filename, err)
+		return configFile, err
 	}
+	if userLayer != nil {
+		layers = append(layers, *userLayer)
+	}
+
+	if fn := ProjectConfigFile(); fn != "" {
+		layer, err := readLayer(configfile.ScopeProject, fn)
+		if err != nil {
+			return configFile, err
+		}
+		if layer != nil {
+			layers = append(layers, *layer)
+		}
+	}
+
+	if len(layers) == 0 {
+		// It is OK for no configuration file to be present, in which
+		// case we return a default struct.
+		return configFile, nil
+	}
+	err = configFile.LoadLayers(layers...)
 	return configFile, err
 }
 
+// readLayer reads the configuration file for the given scope. It returns nil
+// if the file does not exist.
+func readLayer(scope configfile.Scope, filename string) (*configfile.Layer, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		// Any other error happening when failing to read the file must be returned.
+		return nil, fmt.Errorf("loading config file: %w", err)
+	}
+	return &configfile.Layer{
+		Origin: configfile.Origin{Scope: scope, File: filename},
+		Data:   data,
+	}, nil
+}
+
 // LoadDefaultConfigFile attempts to load the default config file and returns
 // a reference to the ConfigFile struct. If none is found or when failing to load
 // the configuration file, it initializes a default ConfigFile struct. If no
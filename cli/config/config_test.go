@@ -11,6 +11,7 @@
 
 	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/cli/config/credentials"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/skip"
@@ -378,7 +379,7 @@ func TestLoadDefaultConfigFile(t *testing.T) {
 		expected.CredentialsStore = credStore
 		expected.PsFormat = "format"
 
-		assert.Check(t, is.DeepEqual(expected, configFile))
+		assert.Check(t, is.DeepEqual(expected, configFile, cmpopts.IgnoreUnexported(configfile.ConfigFile{})))
 		assert.Check(t, is.Equal(buffer.String(), ""))
 	})
 
@@ -482,3 +483,69 @@ func TestSetDir(t *testing.T) {
 	SetDir(expected)
 	assert.Check(t, is.Equal(Dir(), expected))
 }
+
+func TestLoadLayeredConfig(t *testing.T) {
+	dir := setupConfigDir(t)
+	tmpDir := t.TempDir()
+
+	systemFile := filepath.Join(tmpDir, "system.json")
+	assert.NilError(t, os.WriteFile(systemFile, []byte(`{"psFormat": "system-ps", "imagesFormat": "system-images", "detachKeys": "ctrl-s"}`), 0o644))
+	oldSystemConfigFile := systemConfigFile
+	systemConfigFile = systemFile
+	t.Cleanup(func() { systemConfigFile = oldSystemConfigFile })
+
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(`{"imagesFormat": "user-images"}`), 0o644))
+
+	projectDir := filepath.Join(tmpDir, "project")
+	workDir := filepath.Join(projectDir, "sub", "dir")
+	assert.NilError(t, os.MkdirAll(workDir, 0o755))
+	projectFile := filepath.Join(projectDir, ProjectConfigFileName)
+	assert.NilError(t, os.WriteFile(projectFile, []byte(`{"detachKeys": "ctrl-p", "credsStore": "evil"}`), 0o644))
+	oldGetWorkingDir := getWorkingDir
+	getWorkingDir = func() (string, error) { return workDir, nil }
+	t.Cleanup(func() { getWorkingDir = oldGetWorkingDir })
+
+	assert.Check(t, is.Equal(ProjectConfigFile(), projectFile))
+
+	configFile, err := Load("")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(configFile.PsFormat, "system-ps"))
+	assert.Check(t, is.Equal(configFile.ImagesFormat, "user-images"))
+	assert.Check(t, is.Equal(configFile.DetachKeys, "ctrl-p"))
+	assert.Check(t, is.Equal(configFile.CredentialsStore, ""))
+
+	origin, ok := configFile.Origin("detachKeys")
+	assert.Check(t, ok)
+	assert.Check(t, is.DeepEqual(origin, configfile.Origin{Scope: configfile.ScopeProject, File: projectFile}))
+	origin, ok = configFile.Origin("imagesFormat")
+	assert.Check(t, ok)
+	assert.Check(t, is.DeepEqual(origin, configfile.Origin{Scope: configfile.ScopeUser, File: filepath.Join(dir, ConfigFileName)}))
+}
+
+func TestProjectConfigFileNotFound(t *testing.T) {
+	workDir := t.TempDir()
+	oldGetWorkingDir := getWorkingDir
+	getWorkingDir = func() (string, error) { return workDir, nil }
+	t.Cleanup(func() { getWorkingDir = oldGetWorkingDir })
+
+	assert.Check(t, is.Equal(ProjectConfigFile(), ""))
+}
+
+func TestContextFile(t *testing.T) {
+	projectDir := t.TempDir()
+	workDir := filepath.Join(projectDir, "sub", "dir")
+	assert.NilError(t, os.MkdirAll(workDir, 0o755))
+	oldGetWorkingDir := getWorkingDir
+	getWorkingDir = func() (string, error) { return workDir, nil }
+	t.Cleanup(func() { getWorkingDir = oldGetWorkingDir })
+
+	assert.Check(t, is.Equal(ContextFile(), ""))
+
+	// A directory with the same name is ignored.
+	assert.NilError(t, os.Mkdir(filepath.Join(workDir, ContextFileName), 0o755))
+	assert.Check(t, is.Equal(ContextFile(), ""))
+
+	contextFile := filepath.Join(projectDir, ContextFileName)
+	assert.NilError(t, os.WriteFile(contextFile, []byte("staging\n"), 0o644))
+	assert.Check(t, is.Equal(ContextFile(), contextFile))
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package config
+
+// defaultSystemConfigFile returns the default location of the system-wide
+// configuration file.
+func defaultSystemConfigFile() string {
+	return "/etc/docker/cli/" + ConfigFileName
+}
@@ -0,0 +1,16 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// defaultSystemConfigFile returns the default location of the system-wide
+// configuration file.
+func defaultSystemConfigFile() string {
+	programData := os.Getenv("ProgramData")
+	if programData == "" {
+		return ""
+	}
+	return filepath.Join(programData, "Docker", "cli", ConfigFileName)
+}
@@ -74,7 +74,11 @@ type ConfigFile struct {
 	CLIPluginsExtraDirs  []string                     `json:"cliPluginsExtraDirs,omitempty"`
 	Plugins              map[string]map[string]string `json:"plugins,omitempty"`
 	Aliases              map[string]string            `json:"aliases,omitempty"`
+	RunPresets           map[string]string            `json:"runPresets,omitempty"`
 	Features             map[string]string            `json:"features,omitempty"`
+
+	// layers is set when loading the configuration using LoadLayers.
+	layers *layers
 }
 
 type configEnvAuth struct {
@@ -189,6 +193,14 @@ func (c *ConfigFile) SaveToWriter(writer io.Writer) error {
 	if err != nil {
 		return err
 	}
+	if c.layers != nil && c.layers.hasNonUserValues() {
+		// Only save the user's configuration, and don't persist values
+		// from the system or project configuration.
+		data, err = c.layers.userConfig(data)
+		if err != nil {
+			return err
+		}
+	}
 	_, err = writer.Write(data)
 	return err
 }
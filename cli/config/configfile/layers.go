@@ -0,0 +1,344 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template/parse"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Scope identifies the kind of configuration file a value was loaded from.
+type Scope string
+
+const (
+	// ScopeSystem is the system-wide configuration file, which is shared
+	// by all users.
+	ScopeSystem Scope = "system"
+	// ScopeUser is the configuration file in the user's config-directory
+	// (~/.docker/config.json). It is the only configuration file that is
+	// written to when saving the configuration.
+	ScopeUser Scope = "user"
+	// ScopeProject is the project configuration file, which is discovered
+	// from the current working directory.
+	ScopeProject Scope = "project"
+)
+
+// projectKeys are the configuration keys allowed in a project configuration
+// file. Project configuration files are discovered from the working directory,
+// and may not be trusted, so options that result in executing binaries (such
+// as credential-helpers, plugins, and aliases) are not allowed.
+var projectKeys = map[string]struct{}{
+	"psFormat":             {},
+	"imagesFormat":         {},
+	"networksFormat":       {},
+	"pluginsFormat":        {},
+	"volumesFormat":        {},
+	"statsFormat":          {},
+	"serviceInspectFormat": {},
+	"servicesFormat":       {},
+	"tasksFormat":          {},
+	"secretFormat":         {},
+	"configFormat":         {},
+	"nodesFormat":          {},
+	"detachKeys":           {},
+	"currentContext":       {},
+}
+
+// projectFormatDeniedFuncs are the template functions that are not allowed
+// in output formats in project configuration files, as they expose values
+// that are not part of the output of the command, such as secrets in
+// environment variables.
+var projectFormatDeniedFuncs = []string{"env"}
+
+// Origin describes the configuration file a value was loaded from.
+type Origin struct {
+	Scope Scope
+	File  string
+}
+
+// Layer is a configuration file to merge into the effective configuration.
+type Layer struct {
+	Origin
+	Data []byte
+}
+
+// layers holds the information needed to track the origin of configuration
+// values, and to save only the user's configuration.
+type layers struct {
+	// origins holds the origin of each configuration value. Keys are the
+	// top-level JSON field name (e.g. "psFormat"), or the field name and
+	// key, separated by a dot, for values in objects (e.g. "credHelpers.example.com").
+	origins map[string]Origin
+	// merged and user are the merged, and the user's own configuration as
+	// loaded, and used to determine if values were changed when saving.
+	merged map[string]json.RawMessage
+	user   map[string]json.RawMessage
+}
+
+// LoadLayers merges the given configuration files, and populates the receiver
+// with the result. Layers are merged in order, with values in later layers
+// overriding those in earlier layers. Values that are JSON objects (such as
+// "credHelpers" and "aliases") are merged per key; other values are replaced.
+//
+// Credentials ("auths") are only loaded from the user's configuration file,
+// and project configuration files can only set output formats, "detachKeys",
+// and "currentContext"; other options in these layers are ignored.
+func (c *ConfigFile) LoadLayers(layers ...Layer) error {
+	l := newLayers()
+	for _, layer := range layers {
+		if err := l.merge(layer); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(l.merged)
+	if err != nil {
+		return err
+	}
+	if err := c.LoadFromReader(bytes.NewReader(data)); err != nil {
+		return err
+	}
+	c.layers = l
+	return nil
+}
+
+func newLayers() *layers {
+	return &layers{
+		origins: map[string]Origin{},
+		merged:  map[string]json.RawMessage{},
+		user:    map[string]json.RawMessage{},
+	}
+}
+
+func (l *layers) merge(layer Layer) error {
+	values := map[string]json.RawMessage{}
+	if len(bytes.TrimSpace(layer.Data)) > 0 {
+		if err := json.Unmarshal(layer.Data, &values); err != nil {
+			return fmt.Errorf("parsing config file (%s): %w", layer.File, err)
+		}
+	}
+	if layer.Scope == ScopeUser {
+		l.user = values
+	}
+	for key, value := range values {
+		if !allowedInScope(key, layer.Scope) {
+			logrus.WithField("file", layer.File).Warnf("Ignoring option %q: not allowed in %s configuration file", key, layer.Scope)
+			continue
+		}
+		if layer.Scope == ScopeProject {
+			if fn, ok := deniedFormatFunc(value); ok {
+				logrus.WithField("file", layer.File).Warnf("Ignoring option %q: the %q template function is not allowed in %s configuration file", key, fn, layer.Scope)
+				continue
+			}
+		}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(value, &obj); err != nil || obj == nil {
+			l.merged[key] = value
+			l.setOrigin(key, layer.Origin)
+			continue
+		}
+		var current map[string]json.RawMessage
+		_ = json.Unmarshal(l.merged[key], &current)
+		if current == nil {
+			current = map[string]json.RawMessage{}
+		}
+		for k, v := range obj {
+			current[k] = v
+			l.origins[key+"."+k] = layer.Origin
+		}
+		merged, err := json.Marshal(current)
+		if err != nil {
+			return err
+		}
+		l.merged[key] = merged
+	}
+	return nil
+}
+
+// setOrigin sets the origin for a value that is replaced as a whole, removing
+// origins of values it may have contained.
+func (l *layers) setOrigin(key string, origin Origin) {
+	for k := range l.origins {
+		if strings.HasPrefix(k, key+".") {
+			delete(l.origins, k)
+		}
+	}
+	l.origins[key] = origin
+}
+
+// deniedFormatFunc returns the first function in projectFormatDeniedFuncs
+// that's used by the template in the value, if the value is a string. It
+// returns false if the value isn't a string, or isn't a valid template.
+func deniedFormatFunc(value json.RawMessage) (string, bool) {
+	var format string
+	if err := json.Unmarshal(value, &format); err != nil || !strings.Contains(format, "{{") {
+		return "", false
+	}
+	t := parse.New("format")
+	t.Mode = parse.SkipFuncCheck
+	trees := map[string]*parse.Tree{}
+	if _, err := t.Parse(format, "", "", trees); err != nil {
+		return "", false
+	}
+	for _, tree := range trees {
+		if fn, ok := findIdentifier(tree.Root, projectFormatDeniedFuncs); ok {
+			return fn, true
+		}
+	}
+	return "", false
+}
+
+// findIdentifier returns the first of the function names that's used in
+// the template node.
+func findIdentifier(node parse.Node, names []string) (string, bool) {
+	var children []parse.Node
+	switch n := node.(type) {
+	case *parse.IdentifierNode:
+		return n.Ident, slices.Contains(names, n.Ident)
+	case *parse.ListNode:
+		if n != nil {
+			children = n.Nodes
+		}
+	case *parse.ActionNode:
+		children = []parse.Node{n.Pipe}
+	case *parse.PipeNode:
+		if n != nil {
+			for _, c := range n.Cmds {
+				children = append(children, c)
+			}
+		}
+	case *parse.CommandNode:
+		children = n.Args
+	case *parse.ChainNode:
+		children = []parse.Node{n.Node}
+	case *parse.IfNode:
+		children = []parse.Node{n.Pipe, n.List, n.ElseList}
+	case *parse.RangeNode:
+		children = []parse.Node{n.Pipe, n.List, n.ElseList}
+	case *parse.WithNode:
+		children = []parse.Node{n.Pipe, n.List, n.ElseList}
+	case *parse.TemplateNode:
+		children = []parse.Node{n.Pipe}
+	}
+	for _, c := range children {
+		if fn, ok := findIdentifier(c, names); ok {
+			return fn, true
+		}
+	}
+	return "", false
+}
+
+func allowedInScope(key string, scope Scope) bool {
+	switch scope {
+	case ScopeUser:
+		return true
+	case ScopeProject:
+		_, ok := projectKeys[key]
+		return ok
+	default:
+		return key != "auths"
+	}
+}
+
+// Origin returns the origin of the configuration value with the given key,
+// which is either a top-level field name (for example, "psFormat"), or a
+// field name and key, separated by a dot (for example, "credHelpers.example.com").
+// It returns false if the value was not loaded from a configuration file, or
+// if the configuration was not loaded using [ConfigFile.LoadLayers].
+func (c *ConfigFile) Origin(key string) (Origin, bool) {
+	if c.layers == nil {
+		return Origin{}, false
+	}
+	o, ok := c.layers.origins[key]
+	return o, ok
+}
+
+// Origins returns the origin of all configuration values loaded using
+// [ConfigFile.LoadLayers]. See [ConfigFile.Origin] for the format of keys.
+func (c *ConfigFile) Origins() map[string]Origin {
+	if c.layers == nil {
+		return nil
+	}
+	return maps.Clone(c.layers.origins)
+}
+
+// hasNonUserValues returns true if any of the configuration values were
+// loaded from a configuration file other than the user's.
+func (l *layers) hasNonUserValues() bool {
+	for _, o := range l.origins {
+		if o.Scope != ScopeUser {
+			return true
+		}
+	}
+	return false
+}
+
+// userConfig filters data, which is the JSON-encoded configuration, to only
+// contain the user's configuration. Values from other layers that have not
+// been changed are restored to the user's value, or omitted if the user did
+// not set them. Values that have been changed or added are kept.
+func (l *layers) userConfig(data []byte) ([]byte, error) {
+	current := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &current); err != nil {
+		return nil, err
+	}
+	out := map[string]json.RawMessage{}
+	for key, value := range current {
+		if _, ok := l.merged[key]; !ok {
+			// not loaded from any of the layers.
+			out[key] = value
+			continue
+		}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(value, &obj); err != nil || obj == nil {
+			if v, ok := l.userValue(key, value, l.merged[key], l.user[key]); ok {
+				out[key] = v
+			}
+			continue
+		}
+
+		var mergedObj, userObj map[string]json.RawMessage
+		_ = json.Unmarshal(l.merged[key], &mergedObj)
+		_ = json.Unmarshal(l.user[key], &userObj)
+		filtered := map[string]json.RawMessage{}
+		for k, v := range obj {
+			if uv, ok := l.userValue(key+"."+k, v, mergedObj[k], userObj[k]); ok {
+				filtered[k] = uv
+			}
+		}
+		if len(filtered) > 0 || l.user[key] != nil {
+			v, err := json.Marshal(filtered)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = v
+		}
+	}
+	return json.MarshalIndent(out, "", "\t")
+}
+
+// userValue returns the value to save for key. It returns false if the value
+// should be omitted.
+func (l *layers) userValue(key string, current, merged, user json.RawMessage) (json.RawMessage, bool) {
+	origin, ok := l.origins[key]
+	if !ok || origin.Scope == ScopeUser || !jsonEqual(current, merged) {
+		return current, true
+	}
+	return user, user != nil
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
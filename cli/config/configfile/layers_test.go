@@ -0,0 +1,130 @@
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/cli/cli/config/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestLoadLayers(t *testing.T) {
+	system := Layer{
+		Origin: Origin{Scope: ScopeSystem, File: "/etc/docker/cli/config.json"},
+		Data: []byte(`{
+	"psFormat": "system-ps",
+	"imagesFormat": "system-images",
+	"credHelpers": {"a.example.com": "system", "b.example.com": "system"},
+	"auths": {"system.example.com": {"auth": "c3lzdGVtOnBhc3M="}}
+}`),
+	}
+	user := Layer{
+		Origin: Origin{Scope: ScopeUser, File: "/home/user/.docker/config.json"},
+		Data: []byte(`{
+	"psFormat": "user-ps",
+	"credHelpers": {"b.example.com": "user"},
+	"auths": {"user.example.com": {"auth": "dXNlcjpwYXNz"}}
+}`),
+	}
+	project := Layer{
+		Origin: Origin{Scope: ScopeProject, File: "/src/project/.docker-cli.json"},
+		Data: []byte(`{
+	"imagesFormat": "project-images",
+	"credHelpers": {"a.example.com": "project"},
+	"aliases": {"ls": "ps"}
+}`),
+	}
+
+	configFile := New(user.File)
+	assert.NilError(t, configFile.LoadLayers(system, user, project))
+
+	assert.Check(t, is.Equal(configFile.PsFormat, "user-ps"))
+	assert.Check(t, is.Equal(configFile.ImagesFormat, "project-images"))
+	assert.Check(t, is.DeepEqual(configFile.CredentialHelpers, map[string]string{
+		"a.example.com": "system",
+		"b.example.com": "user",
+	}))
+	assert.Check(t, is.Len(configFile.Aliases, 0))
+	assert.Check(t, is.DeepEqual(configFile.AuthConfigs, map[string]types.AuthConfig{
+		"user.example.com": {Username: "user", Password: "pass", ServerAddress: "user.example.com"},
+	}))
+
+	assert.Check(t, is.DeepEqual(configFile.Origins(), map[string]Origin{
+		"psFormat":                  user.Origin,
+		"imagesFormat":              project.Origin,
+		"credHelpers.a.example.com": system.Origin,
+		"credHelpers.b.example.com": user.Origin,
+		"auths.user.example.com":    user.Origin,
+	}))
+
+	_, ok := configFile.Origin("aliases.ls")
+	assert.Check(t, !ok)
+}
+
+func TestLoadLayersProjectFormatFuncs(t *testing.T) {
+	user := Layer{
+		Origin: Origin{Scope: ScopeUser, File: "/home/user/.docker/config.json"},
+		Data:   []byte(`{"psFormat": "{{env \"HOME\"}}", "imagesFormat": "user-images"}`),
+	}
+	project := Layer{
+		Origin: Origin{Scope: ScopeProject, File: "/src/project/.docker-cli.json"},
+		Data: []byte(`{
+	"imagesFormat": "table {{.ID}}\t{{if .Containers}}{{.Repository | env}}{{end}}",
+	"volumesFormat": "table {{.Name}}\t{{.Driver | upper}}"
+}`),
+	}
+
+	configFile := New(user.File)
+	assert.NilError(t, configFile.LoadLayers(user, project))
+
+	// The env function is allowed in the user's configuration file, but
+	// formats that use it are ignored in project configuration files.
+	assert.Check(t, is.Equal(configFile.PsFormat, `{{env "HOME"}}`))
+	assert.Check(t, is.Equal(configFile.ImagesFormat, "user-images"))
+	assert.Check(t, is.Equal(configFile.VolumesFormat, "table {{.Name}}\t{{.Driver | upper}}"))
+}
+
+func TestLoadLayersInvalid(t *testing.T) {
+	configFile := New("config.json")
+	err := configFile.LoadLayers(Layer{
+		Origin: Origin{Scope: ScopeSystem, File: "/etc/docker/cli/config.json"},
+		Data:   []byte(`{"psFormat":`),
+	})
+	assert.Check(t, is.ErrorContains(err, "parsing config file (/etc/docker/cli/config.json)"))
+}
+
+func TestSaveLayersOnlyUserConfig(t *testing.T) {
+	configFile := New("config.json")
+	assert.NilError(t, configFile.LoadLayers(
+		Layer{
+			Origin: Origin{Scope: ScopeSystem, File: "system.json"},
+			Data:   []byte(`{"psFormat": "system-ps", "imagesFormat": "system-images", "credHelpers": {"a.example.com": "system"}}`),
+		},
+		Layer{
+			Origin: Origin{Scope: ScopeUser, File: "config.json"},
+			Data:   []byte(`{"imagesFormat": "user-images", "credHelpers": {"b.example.com": "user"}}`),
+		},
+		Layer{
+			Origin: Origin{Scope: ScopeProject, File: ".docker-cli.json"},
+			Data:   []byte(`{"imagesFormat": "project-images", "detachKeys": "ctrl-x"}`),
+		},
+	))
+
+	configFile.DetachKeys = "ctrl-y"
+	configFile.NetworksFormat = "networks"
+
+	var buf bytes.Buffer
+	assert.NilError(t, configFile.SaveToWriter(&buf))
+
+	var saved map[string]any
+	assert.NilError(t, json.Unmarshal(buf.Bytes(), &saved))
+	assert.Check(t, is.DeepEqual(saved, map[string]any{
+		"auths":          map[string]any{},
+		"imagesFormat":   "user-images",
+		"credHelpers":    map[string]any{"b.example.com": "user"},
+		"detachKeys":     "ctrl-y",
+		"networksFormat": "networks",
+	}))
+}
@@ -0,0 +1,265 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+// Package broker implements a connection broker, which runs in a background
+// process, and keeps connections to a daemon open so that they can be reused
+// by multiple CLI invocations. This avoids establishing a new connection for
+// each invocation, which can be slow for connections that are made through
+// a connection helper, such as for "ssh://" hosts.
+//
+// The CLI connects to the broker through a unix socket, and sends its API
+// requests to the broker, which forwards them to the daemon using a pool of
+// connections.
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrRunning is returned by [Listen] if a broker is already listening on
+// the socket.
+var ErrRunning = errors.New("connection broker is already running")
+
+// DialFunc connects to the daemon.
+type DialFunc func(ctx context.Context) (net.Conn, error)
+
+// Listen listens on the unix socket at the given path, removing the socket
+// if it's left behind by a broker that's no longer running. It returns an
+// [ErrRunning] error if a broker is already listening on the socket.
+func Listen(socketPath string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0o700); err != nil {
+		return nil, err
+	}
+	l, err := net.Listen("unix", socketPath)
+	if err == nil {
+		return l, nil
+	}
+	if c, dialErr := net.Dial("unix", socketPath); dialErr == nil {
+		_ = c.Close()
+		return nil, ErrRunning
+	}
+	if rmErr := os.Remove(socketPath); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+		return nil, err
+	}
+	return net.Listen("unix", socketPath)
+}
+
+// Dial connects to the broker at the given socket. If no broker is running,
+// it calls start to start the broker, and waits for it to accept connections.
+func Dial(ctx context.Context, socketPath string, start func() error) (net.Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", socketPath)
+	if err == nil || start == nil {
+		return conn, err
+	}
+	logrus.Debugf("broker: starting connection broker for %s", socketPath)
+	if err := start(); err != nil {
+		return nil, fmt.Errorf("failed to start connection broker: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	for delay := 10 * time.Millisecond; ; delay = min(2*delay, 500*time.Millisecond) {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("connection broker did not start: %w", err)
+		case <-time.After(delay):
+		}
+		if conn, err = d.DialContext(ctx, "unix", socketPath); err == nil {
+			return conn, nil
+		}
+	}
+}
+
+// Serve accepts connections on the listener, and forwards the API requests
+// on those connections to the daemon, using dial to connect to the daemon.
+// Connections to the daemon are kept open to be reused, except for requests
+// that upgrade the connection, such as "attach", which use a connection of
+// their own.
+//
+// Serve returns when no connections were accepted for the idle timeout, or
+// when the context is cancelled. It closes the listener before returning.
+func Serve(ctx context.Context, l net.Listener, dial DialFunc, idleTimeout time.Duration) error {
+	b := &broker{
+		dial: dial,
+		idle: newIdleTracker(idleTimeout),
+	}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dial(ctx)
+		},
+		MaxIdleConnsPerHost: 8,
+		IdleConnTimeout:     idleTimeout,
+		DisableCompression:  true,
+	}
+	defer transport.CloseIdleConnections()
+	b.proxy = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.Out.URL.Scheme = "http"
+			r.Out.URL.Host = r.In.Host
+		},
+		Transport:     transport,
+		FlushInterval: -1,
+		ErrorHandler:  writeError,
+	}
+
+	srv := &http.Server{
+		Handler: b,
+		ConnState: func(_ net.Conn, state http.ConnState) {
+			switch state {
+			case http.StateNew:
+				b.idle.add(1)
+			case http.StateClosed, http.StateHijacked:
+				b.idle.add(-1)
+			}
+		},
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(l)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-b.idle.done:
+		logrus.Debug("broker: shutting down after idle timeout")
+	case <-ctx.Done():
+	}
+	return srv.Close()
+}
+
+type broker struct {
+	dial  DialFunc
+	proxy *httputil.ReverseProxy
+	idle  *idleTracker
+}
+
+func (b *broker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Upgrade") == "" {
+		b.proxy.ServeHTTP(w, r)
+		return
+	}
+	b.upgrade(w, r)
+}
+
+// upgrade forwards a request that upgrades the connection, such as "attach"
+// and "exec", using a connection of its own, and copies the streams in both
+// directions until both are closed.
+func (b *broker) upgrade(w http.ResponseWriter, r *http.Request) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		writeError(w, r, errors.New("connection does not support upgrades"))
+		return
+	}
+	upstream, err := b.dial(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	defer upstream.Close()
+	if err := r.Write(upstream); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Keep the broker active while the hijacked connection is in use.
+	b.idle.add(1)
+	defer b.idle.add(-1)
+	conn, buf, err := hijacker.Hijack()
+	if err != nil {
+		logrus.WithError(err).Debug("broker: failed to hijack connection")
+		return
+	}
+	defer conn.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(upstream, buf)
+		closeWrite(upstream)
+	}()
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(conn, upstream)
+		closeWrite(conn)
+	}()
+	wg.Wait()
+}
+
+func closeWrite(conn net.Conn) {
+	if c, ok := conn.(interface{ CloseWrite() error }); ok {
+		_ = c.CloseWrite()
+		return
+	}
+	_ = conn.Close()
+}
+
+// writeError writes an error response in the format that's used by the
+// daemon, so that the error is presented to the user.
+func writeError(w http.ResponseWriter, _ *http.Request, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadGateway)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"message": "connection broker: " + err.Error(),
+	})
+}
+
+// idleTracker tracks the number of active connections, and closes done if
+// there were no active connections for the timeout. Once done is closed,
+// connections are no longer tracked.
+type idleTracker struct {
+	mu      sync.Mutex
+	active  int
+	closed  bool
+	timeout time.Duration
+	timer   *time.Timer
+	done    chan struct{}
+}
+
+func newIdleTracker(timeout time.Duration) *idleTracker {
+	t := &idleTracker{timeout: timeout, done: make(chan struct{})}
+	t.timer = time.AfterFunc(timeout, t.expire)
+	return t
+}
+
+// expire closes done, unless a connection became active after the timer
+// fired, or done was already closed.
+func (t *idleTracker) expire() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed || t.active > 0 {
+		return
+	}
+	t.closed = true
+	close(t.done)
+}
+
+func (t *idleTracker) add(delta int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return
+	}
+	t.active += delta
+	if t.active == 0 {
+		t.timer.Reset(t.timeout)
+	} else {
+		t.timer.Stop()
+	}
+}
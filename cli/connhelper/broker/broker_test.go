@@ -0,0 +1,197 @@
+package broker
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// newDaemon starts a fake daemon, and returns a DialFunc to connect to it
+// that counts the number of connections that were made.
+func newDaemon(t *testing.T) (DialFunc, *atomic.Int32) {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_ping", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "OK")
+	})
+	mux.HandleFunc("/attach", func(w http.ResponseWriter, _ *http.Request) {
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = io.WriteString(conn, "HTTP/1.1 101 UPGRADED\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+		// Echo the stream until the client closes its side.
+		_, _ = io.Copy(conn, buf)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	var dials atomic.Int32
+	return func(ctx context.Context) (net.Conn, error) {
+		dials.Add(1)
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", srv.Listener.Addr().String())
+	}, &dials
+}
+
+func startBroker(t *testing.T, dial DialFunc, idleTimeout time.Duration) (string, chan error) {
+	t.Helper()
+	socketPath := filepath.Join(t.TempDir(), "broker.sock")
+	l, err := Listen(socketPath)
+	assert.NilError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, l, dial, idleTimeout)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return socketPath, done
+}
+
+func newClient(socketPath string) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return Dial(ctx, socketPath, nil)
+			},
+		},
+	}
+}
+
+func TestServeReusesConnections(t *testing.T) {
+	dial, dials := newDaemon(t)
+	socketPath, _ := startBroker(t, dial, time.Minute)
+
+	// Each client represents a CLI invocation.
+	for range 3 {
+		c := newClient(socketPath)
+		resp, err := c.Get("http://docker.example.com/_ping")
+		assert.NilError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		assert.NilError(t, err)
+		_ = resp.Body.Close()
+		assert.Check(t, is.Equal(resp.StatusCode, http.StatusOK))
+		assert.Check(t, is.Equal(string(body), "OK"))
+		c.CloseIdleConnections()
+	}
+	assert.Check(t, is.Equal(dials.Load(), int32(1)))
+}
+
+func TestServeUpgrade(t *testing.T) {
+	dial, _ := newDaemon(t)
+	socketPath, _ := startBroker(t, dial, time.Minute)
+
+	conn, err := Dial(context.Background(), socketPath, nil)
+	assert.NilError(t, err)
+	defer conn.Close()
+	_, err = io.WriteString(conn, "POST /attach HTTP/1.1\r\nHost: docker\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+	assert.NilError(t, err)
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(resp.StatusCode, http.StatusSwitchingProtocols))
+
+	_, err = io.WriteString(conn, "hello")
+	assert.NilError(t, err)
+	assert.NilError(t, conn.(*net.UnixConn).CloseWrite())
+	out, err := io.ReadAll(br)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(out), "hello"))
+}
+
+func TestServeIdleTimeout(t *testing.T) {
+	dial, _ := newDaemon(t)
+	socketPath, done := startBroker(t, dial, 100*time.Millisecond)
+
+	conn, err := Dial(context.Background(), socketPath, nil)
+	assert.NilError(t, err)
+	// The broker must not exit while a connection is open.
+	select {
+	case <-done:
+		t.Fatal("broker exited while a connection was open")
+	case <-time.After(300 * time.Millisecond):
+	}
+	_ = conn.Close()
+
+	select {
+	case err := <-done:
+		assert.NilError(t, err)
+		done <- err
+	case <-time.After(5 * time.Second):
+		t.Fatal("broker did not exit after the idle timeout")
+	}
+	_, err = Dial(context.Background(), socketPath, nil)
+	assert.Check(t, err != nil)
+}
+
+func TestIdleTrackerAddAfterExpire(t *testing.T) {
+	idle := newIdleTracker(time.Millisecond)
+	select {
+	case <-idle.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("idle tracker did not expire")
+	}
+
+	// Connections that are accepted after the timeout must not reset the
+	// timer, which would close done a second time.
+	idle.add(1)
+	idle.add(-1)
+	time.Sleep(10 * time.Millisecond)
+}
+
+func TestDialStartsBroker(t *testing.T) {
+	dial, _ := newDaemon(t)
+	socketPath := filepath.Join(t.TempDir(), "broker.sock")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var starts int
+	start := func() error {
+		starts++
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			l, err := Listen(socketPath)
+			if err == nil {
+				_ = Serve(ctx, l, dial, time.Minute)
+			}
+		}()
+		return nil
+	}
+	for range 2 {
+		conn, err := Dial(ctx, socketPath, start)
+		assert.NilError(t, err)
+		_ = conn.Close()
+	}
+	assert.Check(t, is.Equal(starts, 1))
+}
+
+func TestListen(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "broker.sock")
+	l, err := Listen(socketPath)
+	assert.NilError(t, err)
+
+	_, err = Listen(socketPath)
+	assert.Check(t, is.ErrorIs(err, ErrRunning))
+
+	// Simulate a broker that exited without removing its socket.
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	assert.NilError(t, l.Close())
+	l, err = Listen(socketPath)
+	assert.NilError(t, err)
+	assert.NilError(t, l.Close())
+}
@@ -0,0 +1,20 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package broker
+
+import (
+	"os/exec"
+)
+
+// Start starts the broker by running the given command in the background.
+// The command is detached from the current process, so that the broker
+// keeps running after the current process exits.
+func Start(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
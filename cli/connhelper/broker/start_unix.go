@@ -0,0 +1,14 @@
+//go:build !windows
+
+package broker
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+func detach(cmd *exec.Cmd) {
+	// Run the broker in a session of its own, so that it's not terminated
+	// when the terminal that the CLI runs in is closed.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
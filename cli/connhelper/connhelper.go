@@ -6,16 +6,26 @@
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/url"
+	"os"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/docker/cli/cli/connhelper/commandconn"
+	"github.com/docker/cli/cli/connhelper/internal/sshclient"
 	"github.com/docker/cli/cli/connhelper/ssh"
+	"github.com/sirupsen/logrus"
 )
 
+// EnvNativeSSH is the name of the environment variable to opt in to using
+// the built-in ssh client for ssh:// hosts, instead of the "ssh" binary.
+const EnvNativeSSH = "DOCKER_SSH_NATIVE"
+
 // ConnectionHelper allows to connect to a remote host with custom stream provider binary.
 type ConnectionHelper struct {
 	Dialer func(ctx context.Context, network, addr string) (net.Conn, error)
@@ -34,6 +44,13 @@ func GetConnectionHelper(daemonURL string) (*ConnectionHelper, error) {
 // the given URL, and accepts additional options for ssh connections. It returns
 // nil without error when no helper is registered for the scheme.
 //
+// By default, ssh connections are made with the "ssh" binary. If the
+// DOCKER_SSH_NATIVE ([EnvNativeSSH]) environment variable is set to a
+// truthy value, the built-in ssh client is used instead, unless sshFlags
+// are passed, or the ssh config for the host uses options that are not
+// supported by the built-in client, in which case it falls back to using
+// the "ssh" binary.
+//
 // Requires Docker 18.09 or later on the remote host.
 func GetConnectionHelperWithSSHOpts(daemonURL string, sshFlags []string) (*ConnectionHelper, error) {
 	return getConnectionHelper(daemonURL, sshFlags)
@@ -49,14 +66,21 @@ func getConnectionHelper(daemonURL string, sshFlags []string) (*ConnectionHelper
 		if err != nil {
 			return nil, fmt.Errorf("ssh host connection is not valid: %w", err)
 		}
-		sshFlags = addSSHTimeout(sshFlags)
-		sshFlags = disablePseudoTerminalAllocation(sshFlags)
-
 		remoteCommand := []string{"docker", "system", "dial-stdio"}
 		socketPath := sp.Path
 		if strings.Trim(sp.Path, "/") != "" {
 			remoteCommand = []string{"docker", "--host=unix://" + socketPath, "system", "dial-stdio"}
 		}
+		if useNativeSSH() {
+			helper, err := getNativeSSHHelper(sp, sshFlags, remoteCommand)
+			if !errors.Is(err, sshclient.ErrUnsupported) {
+				return helper, err
+			}
+			logrus.WithError(err).Debug("connhelper: falling back to the ssh binary")
+		}
+
+		sshFlags = addSSHTimeout(sshFlags)
+		sshFlags = disablePseudoTerminalAllocation(sshFlags)
 		sshArgs, err := sp.Command(sshFlags, remoteCommand...)
 		if err != nil {
 			return nil, err
@@ -73,6 +97,35 @@ func getConnectionHelper(daemonURL string, sshFlags []string) (*ConnectionHelper
 	return nil, err
 }
 
+func useNativeSSH() bool {
+	v, _ := strconv.ParseBool(os.Getenv(EnvNativeSSH))
+	return v
+}
+
+// getNativeSSHHelper returns a connection helper that uses the built-in ssh
+// client. It returns an [sshclient.ErrUnsupported] error if the built-in
+// client cannot be used.
+func getNativeSSHHelper(sp *ssh.Spec, sshFlags []string, remoteCommand []string) (*ConnectionHelper, error) {
+	if len(sshFlags) > 0 {
+		return nil, fmt.Errorf("ssh flags are %w", sshclient.ErrUnsupported)
+	}
+	cfg, err := sshclient.LoadConfig(sp)
+	if err != nil {
+		return nil, err
+	}
+	var initPrompt sync.Once
+	return &ConnectionHelper{
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			// Only open the terminal when connecting.
+			initPrompt.Do(func() {
+				cfg.Prompt = sshclient.TerminalPrompt()
+			})
+			return sshclient.Dial(ctx, cfg, remoteCommand...)
+		},
+		Host: "http://docker.example.com",
+	}, nil
+}
+
 // GetCommandConnectionHelper returns Docker-specific connection helper constructed from an arbitrary command.
 func GetCommandConnectionHelper(cmd string, flags ...string) (*ConnectionHelper, error) {
 	return &ConnectionHelper{
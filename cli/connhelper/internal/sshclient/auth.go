@@ -0,0 +1,183 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package sshclient
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// PromptFunc prompts the user with the given question, and returns the
+// answer. If echo is false, the answer must not be echoed, for example,
+// because it's a password.
+type PromptFunc func(question string, echo bool) (string, error)
+
+// dialAgent connects to the ssh-agent in the configuration. It returns nil
+// if no agent is configured, or if the agent is not available.
+func dialAgent(cfg *Config) (net.Conn, agent.ExtendedAgent) {
+	if cfg.IdentityAgent == "" {
+		return nil, nil
+	}
+	conn, err := net.Dial("unix", cfg.IdentityAgent)
+	if err != nil {
+		logrus.WithError(err).Debug("sshclient: ssh-agent is not available")
+		return nil, nil
+	}
+	return conn, agent.NewClient(conn)
+}
+
+// authMethods returns the methods to authenticate with, which are, in order
+// of preference, public keys from the agent and the identity files, and, if
+// the user can be prompted, keyboard-interactive and password authentication.
+func authMethods(cfg *Config, agentClient agent.ExtendedAgent) []ssh.AuthMethod {
+	methods := []ssh.AuthMethod{
+		ssh.PublicKeysCallback(func() ([]ssh.Signer, error) {
+			return signers(cfg, agentClient), nil
+		}),
+	}
+	if cfg.Prompt != nil {
+		methods = append(methods,
+			ssh.KeyboardInteractive(func(name, instruction string, questions []string, echos []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i, q := range questions {
+					if i == 0 {
+						if header := strings.TrimSpace(strings.Join([]string{name, instruction}, "\n")); header != "" {
+							q = header + "\n" + q
+						}
+					}
+					answer, err := cfg.Prompt(q, echos[i])
+					if err != nil {
+						return nil, err
+					}
+					answers[i] = answer
+				}
+				return answers, nil
+			}),
+			ssh.PasswordCallback(func() (string, error) {
+				return cfg.Prompt(fmt.Sprintf("%s@%s's password: ", cfg.User, cfg.Host), false)
+			}),
+		)
+	}
+	return methods
+}
+
+// signers returns the signers of the keys in the agent, followed by the
+// signers of the identity files that exist. If IdentitiesOnly is set, only
+// the keys of the agent that match an identity file are used.
+func signers(cfg *Config, agentClient agent.ExtendedAgent) []ssh.Signer {
+	var identities []ssh.PublicKey
+	var result []ssh.Signer
+	for _, fn := range cfg.IdentityFiles {
+		s, err := loadIdentity(fn, cfg.Prompt)
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				logrus.WithError(err).Debugf("sshclient: failed to load identity file %s", fn)
+			}
+			continue
+		}
+		identities = append(identities, s.PublicKey())
+		result = append(result, s)
+	}
+	if agentClient == nil {
+		return result
+	}
+	agentSigners, err := agentClient.Signers()
+	if err != nil {
+		logrus.WithError(err).Debug("sshclient: failed to get keys from ssh-agent")
+		return result
+	}
+	var fromAgent []ssh.Signer
+	for _, s := range agentSigners {
+		if cfg.IdentitiesOnly && !containsKey(identities, s.PublicKey()) {
+			continue
+		}
+		fromAgent = append(fromAgent, s)
+	}
+	return append(fromAgent, result...)
+}
+
+func containsKey(keys []ssh.PublicKey, key ssh.PublicKey) bool {
+	for _, k := range keys {
+		if bytes.Equal(k.Marshal(), key.Marshal()) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadIdentity loads the private key in the given file. If the key is
+// protected by a passphrase, and its public key is available in the
+// corresponding ".pub" file, the user is only prompted for the passphrase
+// if the server accepts the key.
+func loadIdentity(fn string, prompt PromptFunc) (ssh.Signer, error) {
+	pemBytes, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+	s, err := ssh.ParsePrivateKey(pemBytes)
+	var missing *ssh.PassphraseMissingError
+	if !errors.As(err, &missing) {
+		return s, err
+	}
+	if prompt == nil {
+		return nil, fmt.Errorf("key is protected by a passphrase: %w", err)
+	}
+	decrypt := func() (ssh.Signer, error) {
+		passphrase, err := prompt(fmt.Sprintf("Enter passphrase for key '%s': ", fn), false)
+		if err != nil {
+			return nil, err
+		}
+		return ssh.ParsePrivateKeyWithPassphrase(pemBytes, []byte(passphrase))
+	}
+	if missing.PublicKey == nil {
+		if pubBytes, err := os.ReadFile(fn + ".pub"); err == nil {
+			missing.PublicKey, _, _, _, _ = ssh.ParseAuthorizedKey(pubBytes)
+		}
+	}
+	if missing.PublicKey == nil {
+		return decrypt()
+	}
+	return &lazySigner{pub: missing.PublicKey, load: sync.OnceValues(decrypt)}, nil
+}
+
+// lazySigner is a signer for a key that's protected by a passphrase, which
+// is only decrypted when the key is used for signing.
+type lazySigner struct {
+	pub  ssh.PublicKey
+	load func() (ssh.Signer, error)
+}
+
+func (s *lazySigner) PublicKey() ssh.PublicKey {
+	return s.pub
+}
+
+func (s *lazySigner) Sign(rand io.Reader, data []byte) (*ssh.Signature, error) {
+	signer, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return signer.Sign(rand, data)
+}
+
+func (s *lazySigner) SignWithAlgorithm(rand io.Reader, data []byte, algorithm string) (*ssh.Signature, error) {
+	signer, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	as, ok := signer.(ssh.AlgorithmSigner)
+	if !ok {
+		return nil, fmt.Errorf("key does not support signing with algorithm %s", algorithm)
+	}
+	return as.SignWithAlgorithm(rand, data, algorithm)
+}
@@ -0,0 +1,364 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package sshclient
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/connhelper/ssh"
+)
+
+// ErrUnsupported is returned if the configuration for a host uses options
+// that are not supported by the built-in client, such as "ProxyJump", in
+// which case the "ssh" binary must be used.
+var ErrUnsupported = errors.New("not supported by the built-in ssh client")
+
+// defaultConnectTimeout is the timeout for establishing the connection if no
+// ConnectTimeout is configured, and matches the timeout that's used when
+// connecting with the "ssh" binary.
+const defaultConnectTimeout = 30 * time.Second
+
+// maxIncludeDepth is the maximum depth of nested "Include" directives.
+const maxIncludeDepth = 16
+
+// unsupportedOptions are ssh_config options that change how the connection
+// is established, and that are not implemented by the built-in client.
+var unsupportedOptions = map[string]struct{}{
+	"proxycommand":        {},
+	"proxyjump":           {},
+	"pkcs11provider":      {},
+	"securitykeyprovider": {},
+}
+
+// Config is the configuration for connecting to a host.
+type Config struct {
+	// Host is the hostname or IP address to connect to.
+	Host string
+	Port string
+	User string
+
+	// IdentityFiles are the private keys to authenticate with.
+	IdentityFiles []string
+	// IdentitiesOnly restricts the keys of the agent that are used for
+	// authentication to the keys in IdentityFiles.
+	IdentitiesOnly bool
+	// IdentityAgent is the path of the ssh-agent's socket. No agent is used
+	// if empty.
+	IdentityAgent string
+	// ForwardAgent enables forwarding the agent to the remote host.
+	ForwardAgent bool
+
+	// KnownHostsFiles are the files that contain the known host keys. Host
+	// keys that are accepted are added to the first file.
+	KnownHostsFiles []string
+	// StrictHostKeyChecking is one of "yes", "no", "accept-new", or "ask",
+	// as described in ssh_config(5). It defaults to "ask".
+	StrictHostKeyChecking string
+
+	ConnectTimeout time.Duration
+
+	// Prompt is used to prompt for passwords and passphrases, and to confirm
+	// unknown host keys. No prompts are shown if nil.
+	Prompt PromptFunc
+}
+
+// LoadConfig returns the configuration for connecting to the host in the
+// given spec, using the options for the host in the user's ssh_config file
+// ("~/.ssh/config") and the system's ssh_config file. Options that are set
+// in the spec, such as the user and port, take precedence.
+func LoadConfig(sp *ssh.Spec) (*Config, error) {
+	home, _ := os.UserHomeDir()
+	return loadConfig(sp, home, filepath.Join(home, ".ssh", "config"), systemConfigFile())
+}
+
+func systemConfigFile() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("ProgramData"), "ssh", "ssh_config")
+	}
+	return "/etc/ssh/ssh_config"
+}
+
+func loadConfig(sp *ssh.Spec, home string, configFiles ...string) (*Config, error) {
+	p := configParser{host: sp.Host, home: home, values: map[string][]string{}}
+	for _, fn := range configFiles {
+		if err := p.parseFile(fn, filepath.Dir(fn), 0); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg := &Config{
+		Host:                  p.get("hostname", sp.Host),
+		Port:                  sp.Port,
+		User:                  sp.User,
+		IdentitiesOnly:        strings.EqualFold(p.get("identitiesonly", "no"), "yes"),
+		ForwardAgent:          strings.EqualFold(p.get("forwardagent", "no"), "yes"),
+		StrictHostKeyChecking: strings.ToLower(p.get("stricthostkeychecking", "ask")),
+		ConnectTimeout:        defaultConnectTimeout,
+	}
+	cfg.Host = strings.NewReplacer("%%", "%", "%h", sp.Host).Replace(cfg.Host)
+	if cfg.Port == "" {
+		cfg.Port = p.get("port", "22")
+	}
+	if cfg.User == "" {
+		cfg.User = p.get("user", "")
+	}
+	if cfg.User == "" {
+		cfg.User = localUser()
+	}
+	if cfg.StrictHostKeyChecking == "off" {
+		cfg.StrictHostKeyChecking = "no"
+	}
+	if v := p.get("connecttimeout", ""); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("invalid ConnectTimeout in ssh config: %q", v)
+		}
+		cfg.ConnectTimeout = time.Duration(seconds) * time.Second
+	}
+
+	expand := func(s string) string {
+		return p.expand(s, cfg)
+	}
+	for _, fn := range p.identityFiles {
+		cfg.IdentityFiles = append(cfg.IdentityFiles, expand(fn))
+	}
+	if len(cfg.IdentityFiles) == 0 {
+		for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+			cfg.IdentityFiles = append(cfg.IdentityFiles, filepath.Join(home, ".ssh", name))
+		}
+	}
+	switch agentSock := p.get("identityagent", "SSH_AUTH_SOCK"); {
+	case agentSock == "none":
+	case agentSock == "SSH_AUTH_SOCK":
+		cfg.IdentityAgent = os.Getenv("SSH_AUTH_SOCK")
+	case strings.HasPrefix(agentSock, "$"):
+		cfg.IdentityAgent = os.Getenv(agentSock[1:])
+	default:
+		cfg.IdentityAgent = expand(agentSock)
+	}
+	knownHostsFiles := slices.Concat(
+		p.getArgs("userknownhostsfile", "~/.ssh/known_hosts", "~/.ssh/known_hosts2"),
+		p.getArgs("globalknownhostsfile", "/etc/ssh/ssh_known_hosts", "/etc/ssh/ssh_known_hosts2"),
+	)
+	for _, fn := range knownHostsFiles {
+		if fn != "none" {
+			cfg.KnownHostsFiles = append(cfg.KnownHostsFiles, expand(fn))
+		}
+	}
+	return cfg, nil
+}
+
+func localUser() string {
+	if u, err := user.Current(); err == nil {
+		// Strip the domain of Windows users ("DOMAIN\user").
+		_, name, ok := strings.Cut(u.Username, `\`)
+		if ok {
+			return name
+		}
+		return u.Username
+	}
+	if runtime.GOOS == "windows" {
+		return os.Getenv("USERNAME")
+	}
+	return os.Getenv("USER")
+}
+
+// configParser parses ssh_config files, as described in ssh_config(5), and
+// collects the options for a single host. It implements the subset of the
+// format that's needed to connect to a host; options that change how the
+// connection is established, such as "ProxyJump", and "Match" blocks, are
+// reported as unsupported.
+type configParser struct {
+	host string
+	home string
+
+	// values contains the options that were found for the host. As with
+	// ssh, the first value that's found for an option is used.
+	values        map[string][]string
+	identityFiles []string
+}
+
+func (p *configParser) get(key, defaultValue string) string {
+	if v, ok := p.values[key]; ok {
+		return strings.Join(v, " ")
+	}
+	return defaultValue
+}
+
+func (p *configParser) getArgs(key string, defaultValue ...string) []string {
+	if v, ok := p.values[key]; ok {
+		return v
+	}
+	return defaultValue
+}
+
+// parseFile parses the given file. Relative paths in "Include" directives
+// are relative to baseDir, which is the directory of the top-level file.
+func (p *configParser) parseFile(fn string, baseDir string, depth int) error {
+	f, err := os.Open(fn)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	// Options before the first "Host" or "Match" apply to all hosts.
+	active := true
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		key, args := splitLine(scanner.Text())
+		if key == "" {
+			continue
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("%s:%d: missing argument for %s", fn, lineNum, key)
+		}
+		switch key {
+		case "host":
+			active = matchHost(p.host, args)
+		case "match":
+			if len(args) != 1 || !strings.EqualFold(args[0], "all") {
+				return fmt.Errorf("%s:%d: Match: %w", fn, lineNum, ErrUnsupported)
+			}
+			active = true
+		case "include":
+			if !active {
+				continue
+			}
+			if depth >= maxIncludeDepth {
+				return fmt.Errorf("%s:%d: too many nested includes", fn, lineNum)
+			}
+			for _, pattern := range args {
+				pattern = p.expand(pattern, nil)
+				if !filepath.IsAbs(pattern) {
+					pattern = filepath.Join(baseDir, pattern)
+				}
+				matches, err := filepath.Glob(pattern)
+				if err != nil {
+					return fmt.Errorf("%s:%d: invalid include: %w", fn, lineNum, err)
+				}
+				for _, m := range matches {
+					if err := p.parseFile(m, baseDir, depth+1); err != nil {
+						return err
+					}
+				}
+			}
+		default:
+			if !active {
+				continue
+			}
+			if _, ok := unsupportedOptions[key]; ok && !strings.EqualFold(args[0], "none") {
+				return fmt.Errorf("%s:%d: %s: %w", fn, lineNum, key, ErrUnsupported)
+			}
+			if key == "identityfile" {
+				p.identityFiles = append(p.identityFiles, args[0])
+				continue
+			}
+			if _, ok := p.values[key]; !ok {
+				p.values[key] = args
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// expand expands "~" and the tokens that are supported in paths. Tokens that
+// depend on the configuration are only expanded if cfg is set.
+func (p *configParser) expand(s string, cfg *Config) string {
+	if s == "~" || strings.HasPrefix(s, "~/") {
+		s = p.home + s[1:]
+	}
+	pairs := []string{"%%", "%", "%d", p.home, "%u", localUser()}
+	if cfg != nil {
+		pairs = append(pairs, "%h", cfg.Host, "%n", p.host, "%p", cfg.Port, "%r", cfg.User)
+	}
+	return filepath.FromSlash(strings.NewReplacer(pairs...).Replace(s))
+}
+
+// splitLine splits a line in an ssh_config file into the lowercase keyword
+// and its arguments. Keywords and arguments are separated by whitespace or
+// an optional "=".
+func splitLine(line string) (key string, args []string) {
+	line = strings.TrimSpace(line)
+	if line == "" || line[0] == '#' {
+		return "", nil
+	}
+	i := strings.IndexAny(line, " \t=")
+	if i < 0 {
+		return strings.ToLower(line), nil
+	}
+	key, rest := strings.ToLower(line[:i]), strings.TrimLeft(line[i:], " \t")
+	rest = strings.TrimPrefix(rest, "=")
+	return key, splitArgs(rest)
+}
+
+// splitArgs splits arguments separated by whitespace, which may be enclosed
+// in double quotes.
+func splitArgs(s string) []string {
+	var (
+		args    []string
+		current strings.Builder
+		quoted  bool
+		inArg   bool
+	)
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inArg = true
+		case (r == ' ' || r == '\t') && !quoted:
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+// matchHost matches the host against the patterns of a "Host" directive. It
+// returns false if the host matches a negated pattern ("!pattern").
+func matchHost(host string, patterns []string) bool {
+	var matched bool
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		if !matchPattern(host, strings.TrimPrefix(pattern, "!")) {
+			continue
+		}
+		if negated {
+			return false
+		}
+		matched = true
+	}
+	return matched
+}
+
+// matchPattern matches s against a pattern that can contain "*" and "?"
+// wildcards.
+func matchPattern(s, pattern string) bool {
+	re := regexp.QuoteMeta(pattern)
+	re = strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(re)
+	ok, _ := regexp.MatchString("(?i)^"+re+"$", s)
+	return ok
+}
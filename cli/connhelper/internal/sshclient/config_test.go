@@ -0,0 +1,129 @@
+package sshclient
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/connhelper/ssh"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "/run/agent.sock")
+	home := t.TempDir()
+	sshDir := filepath.Join(home, ".ssh")
+	assert.NilError(t, os.MkdirAll(filepath.Join(sshDir, "config.d"), 0o700))
+	assert.NilError(t, os.WriteFile(filepath.Join(sshDir, "config"), []byte(`
+# Global options
+ConnectTimeout 10
+
+Include config.d/*
+
+Host prod-* !prod-legacy
+    HostName %h.example.com
+    User deploy
+    IdentityFile ~/.ssh/id_prod
+    IdentitiesOnly yes
+    ForwardAgent yes
+
+Host *
+    User=someone-else
+    Port 2222
+    StrictHostKeyChecking accept-new
+    UserKnownHostsFile "~/.ssh/known hosts"
+`), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(sshDir, "config.d", "agent"), []byte(`
+Host prod-db
+    IdentityAgent ~/.ssh/agent-%r.sock
+`), 0o600))
+	systemConfig := filepath.Join(t.TempDir(), "ssh_config")
+	assert.NilError(t, os.WriteFile(systemConfig, []byte("Host *\n    GlobalKnownHostsFile none\n    Port 22\n"), 0o600))
+
+	cfg, err := loadConfig(&ssh.Spec{Host: "prod-db"}, home, filepath.Join(sshDir, "config"), systemConfig)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(cfg, &Config{
+		Host:                  "prod-db.example.com",
+		Port:                  "2222",
+		User:                  "deploy",
+		IdentityFiles:         []string{filepath.Join(sshDir, "id_prod")},
+		IdentitiesOnly:        true,
+		IdentityAgent:         filepath.Join(sshDir, "agent-deploy.sock"),
+		ForwardAgent:          true,
+		KnownHostsFiles:       []string{filepath.Join(sshDir, "known hosts")},
+		StrictHostKeyChecking: "accept-new",
+		ConnectTimeout:        10 * time.Second,
+	}))
+
+	// Options set in the URL take precedence, and negated patterns exclude hosts.
+	cfg, err = loadConfig(&ssh.Spec{Host: "prod-legacy", User: "me", Port: "2022"}, home, filepath.Join(sshDir, "config"), systemConfig)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(cfg.Host, "prod-legacy"))
+	assert.Check(t, is.Equal(cfg.Port, "2022"))
+	assert.Check(t, is.Equal(cfg.User, "me"))
+	assert.Check(t, is.Equal(cfg.IdentityAgent, "/run/agent.sock"))
+	assert.Check(t, !cfg.ForwardAgent)
+	assert.Check(t, is.DeepEqual(cfg.IdentityFiles, []string{
+		filepath.Join(sshDir, "id_ed25519"),
+		filepath.Join(sshDir, "id_ecdsa"),
+		filepath.Join(sshDir, "id_rsa"),
+	}))
+}
+
+func TestLoadConfigDefaults(t *testing.T) {
+	home := t.TempDir()
+	cfg, err := loadConfig(&ssh.Spec{Host: "example.com", User: "me"}, home, filepath.Join(home, ".ssh", "config"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(cfg.Host, "example.com"))
+	assert.Check(t, is.Equal(cfg.Port, "22"))
+	assert.Check(t, is.Equal(cfg.StrictHostKeyChecking, "ask"))
+	assert.Check(t, is.Equal(cfg.ConnectTimeout, defaultConnectTimeout))
+	assert.Check(t, is.DeepEqual(cfg.KnownHostsFiles, []string{
+		filepath.Join(home, ".ssh", "known_hosts"),
+		filepath.Join(home, ".ssh", "known_hosts2"),
+		"/etc/ssh/ssh_known_hosts",
+		"/etc/ssh/ssh_known_hosts2",
+	}))
+}
+
+func TestLoadConfigUnsupported(t *testing.T) {
+	tests := []struct {
+		doc    string
+		config string
+		host   string
+	}{
+		{
+			doc:    "ProxyJump",
+			config: "Host example.com\n    ProxyJump bastion\n",
+			host:   "example.com",
+		},
+		{
+			doc:    "ProxyCommand",
+			config: "ProxyCommand nc %h %p\n",
+			host:   "example.com",
+		},
+		{
+			doc:    "Match",
+			config: "Match host example.com exec \"true\"\n    User me\n",
+			host:   "other.example.com",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			home := t.TempDir()
+			fn := filepath.Join(home, "config")
+			assert.NilError(t, os.WriteFile(fn, []byte(tc.config), 0o600))
+			_, err := loadConfig(&ssh.Spec{Host: tc.host}, home, fn)
+			assert.Check(t, is.ErrorIs(err, ErrUnsupported))
+		})
+	}
+
+	// Options for other hosts are ignored, and "none" disables the option.
+	home := t.TempDir()
+	fn := filepath.Join(home, "config")
+	assert.NilError(t, os.WriteFile(fn, []byte("Host bastion\n    ProxyCommand nc %h %p\nHost *\n    ProxyJump none\n"), 0o600))
+	_, err := loadConfig(&ssh.Spec{Host: "example.com"}, home, fn)
+	assert.NilError(t, err)
+}
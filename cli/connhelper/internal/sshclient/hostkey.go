@@ -0,0 +1,124 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package sshclient
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// hostKeyChecker verifies host keys against the known_hosts files in the
+// configuration, and handles unknown host keys according to the configured
+// StrictHostKeyChecking.
+type hostKeyChecker struct {
+	cfg   *Config
+	known ssh.HostKeyCallback
+}
+
+func newHostKeyChecker(cfg *Config) (*hostKeyChecker, error) {
+	var files []string
+	for _, fn := range cfg.KnownHostsFiles {
+		if _, err := os.Stat(fn); err == nil {
+			files = append(files, fn)
+		}
+	}
+	c := &hostKeyChecker{cfg: cfg}
+	if len(files) > 0 {
+		known, err := knownhosts.New(files...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load known hosts: %w", err)
+		}
+		c.known = known
+	}
+	return c, nil
+}
+
+// algorithms returns the host key algorithms of the known keys for the
+// given address, so that the server is asked for a key that can be verified.
+// It returns nil if no keys are known.
+func (c *hostKeyChecker) algorithms(addr string) []string {
+	if c.known == nil {
+		return nil
+	}
+	var keyErr *knownhosts.KeyError
+	if err := c.known(addr, &net.TCPAddr{}, probeKey{}); !errors.As(err, &keyErr) {
+		return nil
+	}
+	var algos []string
+	for _, k := range keyErr.Want {
+		if k.Key.Type() == ssh.KeyAlgoRSA {
+			// Prefer SHA-2 signatures for RSA keys.
+			algos = append(algos, ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256)
+		}
+		algos = append(algos, k.Key.Type())
+	}
+	return algos
+}
+
+func (c *hostKeyChecker) check(hostname string, remote net.Addr, key ssh.PublicKey) error {
+	err := error(&knownhosts.KeyError{})
+	if c.known != nil {
+		err = c.known(hostname, remote, key)
+	}
+	var keyErr *knownhosts.KeyError
+	if err == nil || !errors.As(err, &keyErr) {
+		return err
+	}
+	if len(keyErr.Want) > 0 {
+		return fmt.Errorf("host key verification failed: the %s host key for %s does not match the key in %s:%d; the host key may have been changed, or someone may be intercepting the connection",
+			key.Type(), hostname, keyErr.Want[0].Filename, keyErr.Want[0].Line)
+	}
+
+	fingerprint := ssh.FingerprintSHA256(key)
+	switch c.cfg.StrictHostKeyChecking {
+	case "no", "accept-new":
+	case "yes":
+		return fmt.Errorf("host key verification failed: no host key is known for %s (%s key fingerprint is %s)", hostname, key.Type(), fingerprint)
+	default:
+		if c.cfg.Prompt == nil {
+			return fmt.Errorf("host key verification failed: no host key is known for %s (%s key fingerprint is %s); add the key to the known_hosts file, or set StrictHostKeyChecking to accept-new in the ssh config", hostname, key.Type(), fingerprint)
+		}
+		answer, err := c.cfg.Prompt(fmt.Sprintf("The authenticity of host '%s' can't be established.\n%s key fingerprint is %s.\nAre you sure you want to continue connecting (yes/no)? ", hostname, key.Type(), fingerprint), true)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(strings.TrimSpace(answer), "yes") {
+			return errors.New("host key verification failed")
+		}
+	}
+	return c.add(hostname, key)
+}
+
+// add adds the host key to the first known_hosts file.
+func (c *hostKeyChecker) add(hostname string, key ssh.PublicKey) error {
+	if len(c.cfg.KnownHostsFiles) == 0 {
+		return nil
+	}
+	fn := c.cfg.KnownHostsFiles[0]
+	if err := os.MkdirAll(filepath.Dir(fn), 0o700); err != nil {
+		return fmt.Errorf("failed to add host key to known hosts: %w", err)
+	}
+	f, err := os.OpenFile(fn, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to add host key to known hosts: %w", err)
+	}
+	defer f.Close()
+	_, err = fmt.Fprintln(f, knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key))
+	return err
+}
+
+// probeKey is a public key that never matches, which is used to obtain the
+// known keys for an address from the known_hosts callback.
+type probeKey struct{}
+
+func (probeKey) Type() string                        { return "probe" }
+func (probeKey) Marshal() []byte                     { return nil }
+func (probeKey) Verify([]byte, *ssh.Signature) error { return errors.New("probe key") }
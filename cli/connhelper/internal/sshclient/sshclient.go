@@ -0,0 +1,287 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+// Package sshclient implements an in-process ssh client, which is used as
+// an alternative to the "ssh" binary to connect to a remote daemon through
+// "docker system dial-stdio".
+package sshclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/connhelper/internal/syntax"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/term"
+)
+
+// Dial connects to the host in the configuration, and runs the given
+// command on the remote host. It returns a [net.Conn] that reads from the
+// command's stdout and writes to its stdin.
+//
+// The context is only used for establishing the connection; the lifetime of
+// the connection is managed by the caller, who must close it.
+func Dial(ctx context.Context, cfg *Config, remoteCommandAndArgs ...string) (net.Conn, error) {
+	remoteCommand, err := quoteCommand(remoteCommandAndArgs...)
+	if err != nil {
+		return nil, err
+	}
+	hostKeys, err := newHostKeyChecker(cfg)
+	if err != nil {
+		return nil, err
+	}
+	addr := net.JoinHostPort(cfg.Host, cfg.Port)
+
+	agentConn, agentClient := dialAgent(cfg)
+	clientConfig := &ssh.ClientConfig{
+		User:              cfg.User,
+		Auth:              authMethods(cfg, agentClient),
+		HostKeyCallback:   hostKeys.check,
+		HostKeyAlgorithms: hostKeys.algorithms(addr),
+		Timeout:           cfg.ConnectTimeout,
+	}
+
+	c, err := dial(ctx, addr, clientConfig)
+	if err != nil {
+		if agentConn != nil {
+			_ = agentConn.Close()
+		}
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	conn, err := newConn(c, cfg, agentConn, agentClient, remoteCommand)
+	if err != nil {
+		_ = c.Close()
+		if agentConn != nil {
+			_ = agentConn.Close()
+		}
+		return nil, err
+	}
+	return conn, nil
+}
+
+// dial establishes the connection and performs the ssh handshake, aborting
+// if the context is cancelled, or if the connect timeout is reached.
+func dial(ctx context.Context, addr string, clientConfig *ssh.ClientConfig) (*ssh.Client, error) {
+	if clientConfig.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, clientConfig.Timeout)
+		defer cancel()
+	}
+	nc, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = nc.Close()
+	})
+	c, chans, reqs, err := ssh.NewClientConn(nc, addr, clientConfig)
+	if !stop() {
+		if err == nil {
+			_ = c.Close()
+		}
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		_ = nc.Close()
+		return nil, err
+	}
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// quoteCommand returns the remote command to run as a single string, quoting
+// values where needed, because ssh executes the command in a POSIX shell.
+func quoteCommand(commandAndArgs ...string) (string, error) {
+	quoted := make([]string, 0, len(commandAndArgs))
+	for _, arg := range commandAndArgs {
+		a, err := syntax.Quote(arg, syntax.LangPOSIX)
+		if err != nil {
+			return "", fmt.Errorf("invalid argument: %w", err)
+		}
+		quoted = append(quoted, a)
+	}
+	return strings.Join(quoted, " "), nil
+}
+
+// conn implements net.Conn for a command that runs in an ssh session.
+type conn struct {
+	client    *ssh.Client
+	session   *ssh.Session
+	agentConn net.Conn
+	command   string
+	stdin     io.WriteCloser
+	stdout    io.Reader
+	stderr    stderrBuffer
+
+	waitOnce sync.Once
+	waitErr  error
+}
+
+func newConn(client *ssh.Client, cfg *Config, agentConn net.Conn, agentClient agent.ExtendedAgent, command string) (*conn, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	c := &conn{client: client, session: session, agentConn: agentConn, command: command}
+	if cfg.ForwardAgent && agentClient != nil {
+		if err := agent.ForwardToAgent(client, agentClient); err != nil {
+			return nil, fmt.Errorf("failed to forward ssh-agent: %w", err)
+		}
+		if err := agent.RequestAgentForwarding(session); err != nil {
+			return nil, fmt.Errorf("failed to forward ssh-agent: %w", err)
+		}
+	}
+	if c.stdin, err = session.StdinPipe(); err != nil {
+		return nil, err
+	}
+	if c.stdout, err = session.StdoutPipe(); err != nil {
+		return nil, err
+	}
+	session.Stderr = &c.stderr
+	logrus.Debugf("sshclient: running %s on %s", command, client.RemoteAddr())
+	if err := session.Start(command); err != nil {
+		return nil, fmt.Errorf("failed to run %s on the remote host: %w", command, err)
+	}
+	return c, nil
+}
+
+func (c *conn) wait() error {
+	c.waitOnce.Do(func() {
+		c.waitErr = c.session.Wait()
+	})
+	return c.waitErr
+}
+
+func (c *conn) Read(p []byte) (int, error) {
+	n, err := c.stdout.Read(p)
+	if !errors.Is(err, io.EOF) {
+		return n, err
+	}
+	if werr := c.wait(); werr != nil {
+		return n, fmt.Errorf("command %q has exited with %v, make sure the URL is valid, and Docker 18.09 or later is installed on the remote host: stderr=%s", c.command, werr, c.stderr.String())
+	}
+	return n, err
+}
+
+func (c *conn) Write(p []byte) (int, error) {
+	return c.stdin.Write(p)
+}
+
+// CloseRead allows conn to implement halfCloser.
+func (*conn) CloseRead() error {
+	return nil
+}
+
+// CloseWrite allows conn to implement halfCloser.
+func (c *conn) CloseWrite() error {
+	return c.stdin.Close()
+}
+
+func (c *conn) Close() error {
+	_ = c.session.Close()
+	err := c.client.Close()
+	if c.agentConn != nil {
+		_ = c.agentConn.Close()
+	}
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+func (c *conn) LocalAddr() net.Addr {
+	return c.client.LocalAddr()
+}
+
+func (c *conn) RemoteAddr() net.Addr {
+	return c.client.RemoteAddr()
+}
+
+func (*conn) SetDeadline(t time.Time) error {
+	logrus.Debugf("unimplemented call: SetDeadline(%v)", t)
+	return nil
+}
+
+func (*conn) SetReadDeadline(t time.Time) error {
+	logrus.Debugf("unimplemented call: SetReadDeadline(%v)", t)
+	return nil
+}
+
+func (*conn) SetWriteDeadline(t time.Time) error {
+	logrus.Debugf("unimplemented call: SetWriteDeadline(%v)", t)
+	return nil
+}
+
+// stderrBuffer keeps the last output of the remote command's stderr to
+// include in errors.
+type stderrBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *stderrBuffer) Write(p []byte) (int, error) {
+	logrus.Debugf("sshclient: %s", string(p))
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.buf.Len() > 4096 {
+		w.buf.Reset()
+	}
+	return w.buf.Write(p)
+}
+
+func (w *stderrBuffer) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+// TerminalPrompt returns a [PromptFunc] that prompts the user on the
+// terminal. It returns nil if there's no terminal to prompt on.
+func TerminalPrompt() PromptFunc {
+	inName, outName := "/dev/tty", "/dev/tty"
+	if runtime.GOOS == "windows" {
+		inName, outName = "CONIN$", "CONOUT$"
+	}
+	in, err := os.OpenFile(inName, os.O_RDWR, 0)
+	if err != nil {
+		return nil
+	}
+	if !term.IsTerminal(int(in.Fd())) {
+		_ = in.Close()
+		return nil
+	}
+	out, err := os.OpenFile(outName, os.O_WRONLY, 0)
+	if err != nil {
+		_ = in.Close()
+		return nil
+	}
+	// The terminal remains open for the lifetime of the CLI, as the prompt
+	// may be used for every connection that's made.
+	var mu sync.Mutex
+	reader := bufio.NewReader(in)
+	return func(question string, echo bool) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, err := fmt.Fprint(out, question); err != nil {
+			return "", err
+		}
+		if !echo {
+			answer, err := term.ReadPassword(int(in.Fd()))
+			_, _ = fmt.Fprintln(out)
+			return string(answer), err
+		}
+		answer, err := reader.ReadString('\n')
+		return strings.TrimRight(answer, "\r\n"), err
+	}
+}
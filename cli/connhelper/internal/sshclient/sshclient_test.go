@@ -0,0 +1,364 @@
+package sshclient
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// testServer is an in-process ssh server. Sessions run a fake "dial-stdio"
+// command, which echoes its stdin to stdout.
+type testServer struct {
+	addr    string
+	hostKey ssh.Signer
+
+	mu             sync.Mutex
+	commands       []string
+	agentForwarded bool
+}
+
+func newTestKey(t *testing.T) ssh.Signer {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NilError(t, err)
+	signer, err := ssh.NewSignerFromKey(priv)
+	assert.NilError(t, err)
+	return signer
+}
+
+// writeTestKey writes the private key to a file in dir, optionally protected
+// by a passphrase, and returns the path of the file.
+func writeTestKey(t *testing.T, dir string, passphrase string) (string, ssh.PublicKey) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NilError(t, err)
+	var block *pem.Block
+	if passphrase == "" {
+		block, err = ssh.MarshalPrivateKey(priv, "")
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(priv, "", []byte(passphrase))
+	}
+	assert.NilError(t, err)
+	fn := filepath.Join(dir, "id_ed25519")
+	assert.NilError(t, os.WriteFile(fn, pem.EncodeToMemory(block), 0o600))
+	pub, err := ssh.NewPublicKey(priv.Public())
+	assert.NilError(t, err)
+	return fn, pub
+}
+
+// startTestServer starts an ssh server that accepts the given public keys,
+// and, if kbdAnswer is set, keyboard-interactive authentication with that
+// answer.
+func startTestServer(t *testing.T, authorized []ssh.PublicKey, kbdAnswer string) *testServer {
+	t.Helper()
+	srv := &testServer{hostKey: newTestKey(t)}
+	serverConfig := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			for _, k := range authorized {
+				if string(k.Marshal()) == string(key.Marshal()) {
+					return nil, nil
+				}
+			}
+			return nil, errors.New("unauthorized key")
+		},
+	}
+	if kbdAnswer != "" {
+		serverConfig.KeyboardInteractiveCallback = func(_ ssh.ConnMetadata, challenge ssh.KeyboardInteractiveChallenge) (*ssh.Permissions, error) {
+			answers, err := challenge("", "", []string{"Verification code: "}, []bool{false})
+			if err != nil {
+				return nil, err
+			}
+			if len(answers) != 1 || answers[0] != kbdAnswer {
+				return nil, errors.New("wrong answer")
+			}
+			return nil, nil
+		}
+	}
+	serverConfig.AddHostKey(srv.hostKey)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+	srv.addr = l.Addr().String()
+	go func() {
+		for {
+			nc, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go srv.serve(nc, serverConfig)
+		}
+	}()
+	return srv
+}
+
+func (srv *testServer) serve(nc net.Conn, serverConfig *ssh.ServerConfig) {
+	defer nc.Close()
+	sc, chans, reqs, err := ssh.NewServerConn(nc, serverConfig)
+	if err != nil {
+		return
+	}
+	defer sc.Close()
+	go ssh.DiscardRequests(reqs)
+	for newCh := range chans {
+		if newCh.ChannelType() != "session" {
+			_ = newCh.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+		ch, chReqs, err := newCh.Accept()
+		if err != nil {
+			return
+		}
+		go srv.session(ch, chReqs)
+	}
+}
+
+func (srv *testServer) session(ch ssh.Channel, reqs <-chan *ssh.Request) {
+	defer ch.Close()
+	for req := range reqs {
+		switch req.Type {
+		case "auth-agent-req@openssh.com":
+			srv.mu.Lock()
+			srv.agentForwarded = true
+			srv.mu.Unlock()
+			_ = req.Reply(true, nil)
+		case "exec":
+			var payload struct{ Command string }
+			_ = ssh.Unmarshal(req.Payload, &payload)
+			srv.mu.Lock()
+			srv.commands = append(srv.commands, payload.Command)
+			srv.mu.Unlock()
+			_ = req.Reply(true, nil)
+
+			status := uint32(0)
+			if strings.HasPrefix(payload.Command, "fail") {
+				_, _ = fmt.Fprintln(ch.Stderr(), "sh: fail: command not found")
+				status = 127
+			} else {
+				_, _ = io.Copy(ch, ch)
+			}
+			_, _ = ch.SendRequest("exit-status", false, binary.BigEndian.AppendUint32(nil, status))
+			return
+		default:
+			_ = req.Reply(false, nil)
+		}
+	}
+}
+
+func (srv *testServer) config(t *testing.T, dir string) *Config {
+	t.Helper()
+	host, port, err := net.SplitHostPort(srv.addr)
+	assert.NilError(t, err)
+	return &Config{
+		Host:                  host,
+		Port:                  port,
+		User:                  "me",
+		KnownHostsFiles:       []string{filepath.Join(dir, "known_hosts")},
+		StrictHostKeyChecking: "yes",
+		ConnectTimeout:        10 * time.Second,
+	}
+}
+
+func (srv *testServer) addKnownHost(t *testing.T, cfg *Config) {
+	t.Helper()
+	line := knownhosts.Line([]string{knownhosts.Normalize(srv.addr)}, srv.hostKey.PublicKey())
+	assert.NilError(t, os.WriteFile(cfg.KnownHostsFiles[0], []byte(line+"\n"), 0o600))
+}
+
+// assertEcho asserts that the connection echoes the data that's written to it.
+func assertEcho(t *testing.T, conn net.Conn) {
+	t.Helper()
+	_, err := conn.Write([]byte("hello"))
+	assert.NilError(t, err)
+	assert.NilError(t, conn.(interface{ CloseWrite() error }).CloseWrite())
+	out, err := io.ReadAll(conn)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(out), "hello"))
+}
+
+func TestDial(t *testing.T) {
+	dir := t.TempDir()
+	keyFile, pub := writeTestKey(t, dir, "")
+	srv := startTestServer(t, []ssh.PublicKey{pub}, "")
+	cfg := srv.config(t, dir)
+	cfg.IdentityFiles = []string{filepath.Join(dir, "no-such-key"), keyFile}
+	srv.addKnownHost(t, cfg)
+
+	conn, err := Dial(context.Background(), cfg, "docker", "--host=unix:///var/run/my docker.sock", "system", "dial-stdio")
+	assert.NilError(t, err)
+	defer conn.Close()
+	assertEcho(t, conn)
+	assert.Check(t, is.DeepEqual(srv.commands, []string{`docker '--host=unix:///var/run/my docker.sock' system dial-stdio`}))
+}
+
+func TestDialRemoteError(t *testing.T) {
+	dir := t.TempDir()
+	keyFile, pub := writeTestKey(t, dir, "")
+	srv := startTestServer(t, []ssh.PublicKey{pub}, "")
+	cfg := srv.config(t, dir)
+	cfg.IdentityFiles = []string{keyFile}
+	srv.addKnownHost(t, cfg)
+
+	conn, err := Dial(context.Background(), cfg, "fail")
+	assert.NilError(t, err)
+	defer conn.Close()
+	_, err = io.ReadAll(conn)
+	assert.Check(t, is.ErrorContains(err, `command "fail" has exited with Process exited with status 127`))
+	assert.Check(t, is.ErrorContains(err, "stderr=sh: fail: command not found"))
+}
+
+func TestDialHostKeyChecking(t *testing.T) {
+	dir := t.TempDir()
+	keyFile, pub := writeTestKey(t, dir, "")
+	srv := startTestServer(t, []ssh.PublicKey{pub}, "")
+
+	t.Run("unknown host", func(t *testing.T) {
+		cfg := srv.config(t, t.TempDir())
+		cfg.IdentityFiles = []string{keyFile}
+		_, err := Dial(context.Background(), cfg, "docker", "system", "dial-stdio")
+		assert.Check(t, is.ErrorContains(err, "host key verification failed: no host key is known for "+srv.addr))
+	})
+
+	t.Run("changed host key", func(t *testing.T) {
+		cfg := srv.config(t, t.TempDir())
+		cfg.IdentityFiles = []string{keyFile}
+		line := knownhosts.Line([]string{knownhosts.Normalize(srv.addr)}, newTestKey(t).PublicKey())
+		assert.NilError(t, os.WriteFile(cfg.KnownHostsFiles[0], []byte(line+"\n"), 0o600))
+		cfg.StrictHostKeyChecking = "no"
+		_, err := Dial(context.Background(), cfg, "docker", "system", "dial-stdio")
+		assert.Check(t, is.ErrorContains(err, "host key verification failed: the ssh-ed25519 host key for "+srv.addr+" does not match the key in "))
+	})
+
+	t.Run("accept-new", func(t *testing.T) {
+		cfg := srv.config(t, t.TempDir())
+		cfg.IdentityFiles = []string{keyFile}
+		cfg.StrictHostKeyChecking = "accept-new"
+		conn, err := Dial(context.Background(), cfg, "docker", "system", "dial-stdio")
+		assert.NilError(t, err)
+		assertEcho(t, conn)
+		_ = conn.Close()
+
+		// The host key must be added, and used for subsequent connections.
+		cfg.StrictHostKeyChecking = "yes"
+		conn, err = Dial(context.Background(), cfg, "docker", "system", "dial-stdio")
+		assert.NilError(t, err)
+		_ = conn.Close()
+	})
+
+	t.Run("ask", func(t *testing.T) {
+		cfg := srv.config(t, t.TempDir())
+		cfg.IdentityFiles = []string{keyFile}
+		cfg.StrictHostKeyChecking = "ask"
+		var questions []string
+		cfg.Prompt = func(question string, echo bool) (string, error) {
+			questions = append(questions, question)
+			return "no", nil
+		}
+		_, err := Dial(context.Background(), cfg, "docker", "system", "dial-stdio")
+		assert.Check(t, is.ErrorContains(err, "host key verification failed"))
+		assert.Assert(t, is.Len(questions, 1))
+		assert.Check(t, is.Contains(questions[0], "The authenticity of host '"+srv.addr+"' can't be established."))
+		assert.Check(t, is.Contains(questions[0], ssh.FingerprintSHA256(srv.hostKey.PublicKey())))
+	})
+}
+
+func TestDialPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	keyFile, pub := writeTestKey(t, dir, "secret")
+	srv := startTestServer(t, []ssh.PublicKey{pub}, "")
+	cfg := srv.config(t, dir)
+	cfg.IdentityFiles = []string{keyFile}
+	srv.addKnownHost(t, cfg)
+
+	_, err := Dial(context.Background(), cfg, "docker", "system", "dial-stdio")
+	assert.Check(t, is.ErrorContains(err, "unable to authenticate"))
+
+	var questions []string
+	cfg.Prompt = func(question string, echo bool) (string, error) {
+		questions = append(questions, question)
+		assert.Check(t, !echo)
+		return "secret", nil
+	}
+	conn, err := Dial(context.Background(), cfg, "docker", "system", "dial-stdio")
+	assert.NilError(t, err)
+	defer conn.Close()
+	assertEcho(t, conn)
+	assert.Check(t, is.DeepEqual(questions, []string{"Enter passphrase for key '" + keyFile + "': "}))
+}
+
+func TestDialKeyboardInteractive(t *testing.T) {
+	dir := t.TempDir()
+	srv := startTestServer(t, nil, "123456")
+	cfg := srv.config(t, dir)
+	srv.addKnownHost(t, cfg)
+
+	_, err := Dial(context.Background(), cfg, "docker", "system", "dial-stdio")
+	assert.Check(t, is.ErrorContains(err, "unable to authenticate"))
+
+	cfg.Prompt = func(question string, echo bool) (string, error) {
+		assert.Check(t, is.Equal(question, "Verification code: "))
+		return "123456", nil
+	}
+	conn, err := Dial(context.Background(), cfg, "docker", "system", "dial-stdio")
+	assert.NilError(t, err)
+	defer conn.Close()
+	assertEcho(t, conn)
+}
+
+func TestDialAgent(t *testing.T) {
+	dir := t.TempDir()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NilError(t, err)
+	keyring := agent.NewKeyring()
+	assert.NilError(t, keyring.Add(agent.AddedKey{PrivateKey: priv}))
+	agentSock := filepath.Join(dir, "agent.sock")
+	l, err := net.Listen("unix", agentSock)
+	assert.NilError(t, err)
+	defer l.Close()
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer c.Close()
+				_ = agent.ServeAgent(keyring, c)
+			}()
+		}
+	}()
+
+	pub, err := ssh.NewPublicKey(priv.Public())
+	assert.NilError(t, err)
+	srv := startTestServer(t, []ssh.PublicKey{pub}, "")
+	cfg := srv.config(t, dir)
+	cfg.IdentityAgent = agentSock
+	cfg.ForwardAgent = true
+	srv.addKnownHost(t, cfg)
+
+	conn, err := Dial(context.Background(), cfg, "docker", "system", "dial-stdio")
+	assert.NilError(t, err)
+	defer conn.Close()
+	assertEcho(t, conn)
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	assert.Check(t, srv.agentForwarded)
+}
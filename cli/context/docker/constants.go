@@ -3,4 +3,12 @@
 const (
 	// DockerEndpoint is the name of the docker endpoint in a stored context
 	DockerEndpoint = "docker"
+
+	// FailoverEndpoint is the name of the endpoint in a stored context that
+	// holds the failover endpoints of the docker endpoint
+	FailoverEndpoint = "docker-failover"
+
+	// ProxyEndpoint is the name of the endpoint in a stored context that
+	// holds the proxies of the docker endpoint and the failover endpoints
+	ProxyEndpoint = "docker-proxy"
 )
@@ -0,0 +1,307 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package docker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	clicontext "github.com/docker/cli/cli/context"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/moby/moby/client"
+	"github.com/sirupsen/logrus"
+)
+
+// Policies to select an endpoint of a context that has failover endpoints.
+const (
+	// PolicyFirst selects the first endpoint that's reachable, in the order
+	// in which the endpoints are configured. It is the default policy.
+	PolicyFirst = "first"
+	// PolicyLatency selects the reachable endpoint with the lowest latency.
+	PolicyLatency = "latency"
+	// PolicyRoundRobin selects the next reachable endpoint for each client
+	// that's created.
+	PolicyRoundRobin = "round-robin"
+)
+
+// Policies are the supported policies to select an endpoint.
+var Policies = []string{PolicyFirst, PolicyLatency, PolicyRoundRobin}
+
+// failoverProbeTimeout is the time to wait for an endpoint to respond when
+// checking if it's reachable.
+const failoverProbeTimeout = 3 * time.Second
+
+// FailoverMeta is the typed metadata of the failover endpoint of a context.
+// It holds the endpoints to fail over to when the docker endpoint of the
+// context is not reachable, and the policy to select an endpoint.
+type FailoverMeta struct {
+	Policy    string `json:",omitempty"`
+	Endpoints []EndpointMeta
+}
+
+// ValidatePolicy validates the policy to select an endpoint.
+func ValidatePolicy(policy string) error {
+	switch policy {
+	case "", PolicyFirst, PolicyLatency, PolicyRoundRobin:
+		return nil
+	default:
+		return fmt.Errorf("invalid failover policy: %q: must be one of %q, %q, or %q", policy, PolicyFirst, PolicyLatency, PolicyRoundRobin)
+	}
+}
+
+// FailoverTLSEndpoint returns the name of the endpoint under which the TLS
+// data of the failover endpoint with the given index is stored.
+func FailoverTLSEndpoint(i int) string {
+	return FailoverEndpoint + "-" + strconv.Itoa(i+1)
+}
+
+// IsFailoverTLSEndpoint returns whether the name is the name of an endpoint
+// under which the TLS data of a failover endpoint is stored.
+func IsFailoverTLSEndpoint(name string) bool {
+	n, ok := strings.CutPrefix(name, FailoverEndpoint+"-")
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(n)
+	return err == nil
+}
+
+// FailoverFromContext parses the failover endpoint metadata of a context. It
+// returns false if the context has no failover endpoints.
+func FailoverFromContext(metadata store.Metadata) (FailoverMeta, bool, error) {
+	ep, ok := metadata.Endpoints[FailoverEndpoint]
+	if !ok {
+		return FailoverMeta{}, false, nil
+	}
+	typed, ok := ep.(FailoverMeta)
+	if !ok {
+		return FailoverMeta{}, false, fmt.Errorf("endpoint %q is not of type FailoverMeta", FailoverEndpoint)
+	}
+	return typed, len(typed.Endpoints) > 0, nil
+}
+
+// withFailover loads the failover endpoints of the context, and their TLS
+// materials.
+func withFailover(s store.Reader, metadata store.Metadata, ep Endpoint) (Endpoint, error) {
+	contextName := metadata.Name
+	failover, ok, err := FailoverFromContext(metadata)
+	if err != nil || !ok {
+		return ep, err
+	}
+	ep.contextName = contextName
+	ep.FailoverPolicy = failover.Policy
+	for i, m := range failover.Endpoints {
+		tlsData, err := clicontext.LoadTLSData(s, contextName, FailoverTLSEndpoint(i))
+		if err != nil {
+			return Endpoint{}, err
+		}
+		ep.Failover = append(ep.Failover, Endpoint{EndpointMeta: m, TLSData: tlsData})
+	}
+	return ep, nil
+}
+
+// Select selects the endpoint to connect to, using the failover policy of
+// the endpoint. It returns the endpoint itself if it has no failover
+// endpoints. If none of the endpoints are reachable, the first endpoint is
+// returned, so that the error is produced when connecting.
+func (ep *Endpoint) Select(ctx context.Context) (Endpoint, error) {
+	return ep.selectWithPolicy(ctx, false)
+}
+
+// Peek returns the endpoint that [Endpoint.Select] selects, without
+// recording the selection for the round-robin policy. It probes the
+// endpoints concurrently, and is meant for presenting the endpoint that
+// is selected, for example, in "docker context inspect".
+func (ep *Endpoint) Peek(ctx context.Context) (Endpoint, error) {
+	return ep.selectWithPolicy(ctx, true)
+}
+
+func (ep *Endpoint) selectWithPolicy(ctx context.Context, peek bool) (Endpoint, error) {
+	if len(ep.Failover) == 0 {
+		return *ep, nil
+	}
+	if err := ValidatePolicy(ep.FailoverPolicy); err != nil {
+		return Endpoint{}, err
+	}
+	primary := *ep
+	primary.Failover, primary.FailoverPolicy = nil, ""
+	candidates := append([]Endpoint{primary}, ep.Failover...)
+
+	var selected int
+	var err error
+	switch ep.FailoverPolicy {
+	case PolicyLatency:
+		selected, err = selectLowestLatency(ctx, candidates)
+	case PolicyRoundRobin:
+		start := nextRoundRobin(ep.contextName, len(candidates))
+		if peek {
+			selected, err = peekFirstReachable(ctx, candidates, start)
+			break
+		}
+		selected, err = selectFirstReachable(ctx, candidates, start)
+		if err == nil {
+			saveRoundRobin(ep.contextName, selected)
+		}
+	default:
+		if peek {
+			selected, err = peekFirstReachable(ctx, candidates, 0)
+			break
+		}
+		selected, err = selectFirstReachable(ctx, candidates, 0)
+	}
+	if err != nil {
+		logrus.Debugf("context %q: %v; using endpoint %s", ep.contextName, err, primary.Host)
+		return primary, nil
+	}
+	policy := ep.FailoverPolicy
+	if policy == "" {
+		policy = PolicyFirst
+	}
+	logrus.Debugf("context %q: selected endpoint %s using the %s policy", ep.contextName, candidates[selected].Host, policy)
+	return candidates[selected], nil
+}
+
+// selectEndpoint selects the endpoint to connect to when creating a client
+// for the endpoint.
+func (ep *Endpoint) selectEndpoint() (Endpoint, error) {
+	return ep.Select(context.Background())
+}
+
+// selectFirstReachable returns the index of the first reachable endpoint,
+// starting at the given index.
+func selectFirstReachable(ctx context.Context, candidates []Endpoint, start int) (int, error) {
+	var errs []error
+	for n := range candidates {
+		i := (start + n) % len(candidates)
+		if _, err := probe(ctx, candidates[i]); err != nil {
+			logrus.Debugf("endpoint %s is not reachable: %v", candidates[i].Host, err)
+			errs = append(errs, fmt.Errorf("%s: %w", candidates[i].Host, err))
+			continue
+		}
+		return i, nil
+	}
+	return 0, fmt.Errorf("no reachable endpoint: %w", errors.Join(errs...))
+}
+
+// peekFirstReachable probes all endpoints concurrently, and returns the
+// index of the first reachable endpoint, starting at the given index. It
+// selects the same endpoint as [selectFirstReachable], but doesn't wait
+// for unreachable endpoints one after the other.
+func peekFirstReachable(ctx context.Context, candidates []Endpoint, start int) (int, error) {
+	_, errs := probeAll(ctx, candidates)
+	for n := range candidates {
+		i := (start + n) % len(candidates)
+		if errs[i] == nil {
+			return i, nil
+		}
+		logrus.Debugf("endpoint %s is not reachable: %v", candidates[i].Host, errs[i])
+		errs[i] = fmt.Errorf("%s: %w", candidates[i].Host, errs[i])
+	}
+	return 0, fmt.Errorf("no reachable endpoint: %w", errors.Join(errs...))
+}
+
+// selectLowestLatency probes all endpoints concurrently, and returns the
+// index of the reachable endpoint with the lowest latency.
+func selectLowestLatency(ctx context.Context, candidates []Endpoint) (int, error) {
+	latencies, errs := probeAll(ctx, candidates)
+
+	selected := -1
+	for i, err := range errs {
+		if err != nil {
+			logrus.Debugf("endpoint %s is not reachable: %v", candidates[i].Host, err)
+			errs[i] = fmt.Errorf("%s: %w", candidates[i].Host, err)
+			continue
+		}
+		logrus.Debugf("endpoint %s responded in %s", candidates[i].Host, latencies[i])
+		if selected < 0 || latencies[i] < latencies[selected] {
+			selected = i
+		}
+	}
+	if selected < 0 {
+		return 0, fmt.Errorf("no reachable endpoint: %w", errors.Join(errs...))
+	}
+	return selected, nil
+}
+
+// probeAll probes all endpoints concurrently, and returns their latencies
+// and errors, in the order of the endpoints.
+func probeAll(ctx context.Context, candidates []Endpoint) ([]time.Duration, []error) {
+	latencies := make([]time.Duration, len(candidates))
+	errs := make([]error, len(candidates))
+	var wg sync.WaitGroup
+	for i, c := range candidates {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			latencies[i], errs[i] = probe(ctx, c)
+		}()
+	}
+	wg.Wait()
+	return latencies, errs
+}
+
+// probe pings the daemon at the endpoint, and returns the time it took to
+// respond.
+func probe(ctx context.Context, ep Endpoint) (time.Duration, error) {
+	opts, err := ep.ClientOpts()
+	if err != nil {
+		return 0, err
+	}
+	apiClient, err := client.New(opts...)
+	if err != nil {
+		return 0, err
+	}
+	defer apiClient.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, failoverProbeTimeout)
+	defer cancel()
+	start := time.Now()
+	if _, err := apiClient.Ping(ctx, client.PingOptions{}); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return 0, fmt.Errorf("no response within %s", failoverProbeTimeout)
+		}
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+// roundRobinStateFile returns the file that holds the index of the endpoint
+// that was last selected for the context using the round-robin policy.
+func roundRobinStateFile(contextName string) string {
+	sum := sha256.Sum256([]byte(contextName))
+	return filepath.Join(config.Dir(), "failover", hex.EncodeToString(sum[:8]))
+}
+
+func nextRoundRobin(contextName string, n int) int {
+	data, err := os.ReadFile(roundRobinStateFile(contextName))
+	if err != nil {
+		return 0
+	}
+	last, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || last < 0 {
+		return 0
+	}
+	return (last + 1) % n
+}
+
+func saveRoundRobin(contextName string, selected int) {
+	fn := roundRobinStateFile(contextName)
+	if err := os.MkdirAll(filepath.Dir(fn), 0o700); err != nil {
+		logrus.Debugf("failed to save failover state: %v", err)
+		return
+	}
+	if err := os.WriteFile(fn, []byte(strconv.Itoa(selected)), 0o600); err != nil {
+		logrus.Debugf("failed to save failover state: %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package docker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// newDaemon starts a fake daemon that responds to pings after the given
+// delay, and returns its host.
+func newDaemon(t *testing.T, delay time.Duration) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/_ping") {
+			time.Sleep(delay)
+			w.Header().Set("Api-Version", "1.51")
+			_, _ = w.Write([]byte("OK"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+	return "tcp://" + srv.Listener.Addr().String()
+}
+
+// newUnreachable returns the host of a daemon that's not reachable.
+func newUnreachable(t *testing.T) string {
+	t.Helper()
+	srv := httptest.NewServer(http.NotFoundHandler())
+	host := "tcp://" + srv.Listener.Addr().String()
+	srv.Close()
+	return host
+}
+
+func newEndpoint(policy string, hosts ...string) Endpoint {
+	ep := Endpoint{
+		EndpointMeta:   EndpointMeta{Host: hosts[0]},
+		FailoverPolicy: policy,
+		contextName:    "test",
+	}
+	for _, h := range hosts[1:] {
+		ep.Failover = append(ep.Failover, Endpoint{EndpointMeta: EndpointMeta{Host: h}})
+	}
+	return ep
+}
+
+func TestSelect(t *testing.T) {
+	config.SetDir(t.TempDir())
+	unreachable := newUnreachable(t)
+	slow := newDaemon(t, 200*time.Millisecond)
+	fast := newDaemon(t, 0)
+
+	t.Run("no failover", func(t *testing.T) {
+		ep := Endpoint{EndpointMeta: EndpointMeta{Host: unreachable}}
+		selected, err := ep.Select(context.Background())
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(selected.Host, unreachable))
+	})
+	t.Run("first", func(t *testing.T) {
+		ep := newEndpoint("", unreachable, slow, fast)
+		selected, err := ep.Select(context.Background())
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(selected.Host, slow))
+		assert.Check(t, is.Len(selected.Failover, 0))
+	})
+	t.Run("latency", func(t *testing.T) {
+		ep := newEndpoint(PolicyLatency, unreachable, slow, fast)
+		selected, err := ep.Select(context.Background())
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(selected.Host, fast))
+	})
+	t.Run("round-robin", func(t *testing.T) {
+		ep := newEndpoint(PolicyRoundRobin, slow, unreachable, fast)
+		var hosts []string
+		for range 4 {
+			selected, err := ep.Select(context.Background())
+			assert.NilError(t, err)
+			hosts = append(hosts, selected.Host)
+		}
+		assert.Check(t, is.DeepEqual(hosts, []string{slow, fast, slow, fast}))
+	})
+	t.Run("peek", func(t *testing.T) {
+		ep := newEndpoint(PolicyFirst, unreachable, slow, fast)
+		selected, err := ep.Peek(context.Background())
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(selected.Host, slow))
+	})
+	t.Run("peek round-robin", func(t *testing.T) {
+		ep := newEndpoint(PolicyRoundRobin, slow, unreachable, fast)
+		ep.contextName = "peek"
+		for range 2 {
+			selected, err := ep.Peek(context.Background())
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(selected.Host, slow))
+		}
+		selected, err := ep.Select(context.Background())
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(selected.Host, slow))
+		selected, err = ep.Peek(context.Background())
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(selected.Host, fast))
+	})
+	t.Run("none reachable", func(t *testing.T) {
+		other := newUnreachable(t)
+		ep := newEndpoint(PolicyFirst, unreachable, other)
+		selected, err := ep.Select(context.Background())
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(selected.Host, unreachable))
+	})
+	t.Run("invalid policy", func(t *testing.T) {
+		ep := newEndpoint("random", fast, slow)
+		_, err := ep.Select(context.Background())
+		assert.Check(t, is.Error(err, `invalid failover policy: "random": must be one of "first", "latency", or "round-robin"`))
+	})
+}
+
+func TestIsFailoverTLSEndpoint(t *testing.T) {
+	assert.Check(t, IsFailoverTLSEndpoint(FailoverTLSEndpoint(0)))
+	assert.Check(t, IsFailoverTLSEndpoint(FailoverTLSEndpoint(11)))
+	assert.Check(t, !IsFailoverTLSEndpoint(DockerEndpoint))
+	assert.Check(t, !IsFailoverTLSEndpoint(FailoverEndpoint))
+	assert.Check(t, !IsFailoverTLSEndpoint(FailoverEndpoint+"-tls"))
+}
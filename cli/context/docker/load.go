@@ -27,18 +27,40 @@
 type Endpoint struct {
 	EndpointMeta
 	TLSData *context.TLSData
+
+	// Failover holds the endpoints to fail over to if the endpoint is not
+	// reachable, and FailoverPolicy the policy to select an endpoint with.
+	// See [Endpoint.Select].
+	Failover       []Endpoint
+	FailoverPolicy string
+
+	// Proxy is the URL of the HTTP CONNECT or SOCKS5 proxy to connect to
+	// the daemon through; see [ParseProxyURL]. It's stored separately from
+	// the endpoint's metadata, in the [ProxyEndpoint] of the context.
+	Proxy string
+
+	contextName string
 }
 
-// WithTLSData loads TLS materials for the endpoint
+// WithTLSData loads TLS materials for the endpoint, and the failover
+// endpoints and proxies of the context, if any.
 func WithTLSData(s store.Reader, contextName string, m EndpointMeta) (Endpoint, error) {
 	tlsData, err := context.LoadTLSData(s, contextName, DockerEndpoint)
 	if err != nil {
 		return Endpoint{}, err
 	}
-	return Endpoint{
+	metadata, err := s.GetMetadata(contextName)
+	if err != nil {
+		return Endpoint{}, err
+	}
+	ep, err := withFailover(s, metadata, Endpoint{
 		EndpointMeta: m,
 		TLSData:      tlsData,
-	}, nil
+	})
+	if err != nil {
+		return Endpoint{}, err
+	}
+	return withProxies(metadata, ep)
 }
 
 // tlsConfig extracts a context docker endpoint TLS config
@@ -83,8 +105,20 @@ func (ep *Endpoint) tlsConfig() (*tls.Config, error) {
 	return tlsconfig.ClientDefault(tlsOpts...), nil
 }
 
-// ClientOpts returns a slice of Client options to configure an API client with this endpoint
+// ClientOpts returns a slice of Client options to configure an API client with this endpoint.
+// If the endpoint has failover endpoints, the endpoint to use is selected
+// first; see [Endpoint.Select].
 func (ep *Endpoint) ClientOpts() ([]client.Opt, error) {
+	if len(ep.Failover) > 0 {
+		selected, err := ep.selectEndpoint()
+		if err != nil {
+			return nil, err
+		}
+		return selected.ClientOpts()
+	}
+	if ep.Proxy != "" {
+		return ep.proxyClientOpts()
+	}
 	var result []client.Opt
 	if ep.Host != "" {
 		helper, err := connhelper.GetConnectionHelper(ep.Host)
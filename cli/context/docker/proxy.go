@@ -0,0 +1,340 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package docker
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/moby/moby/client"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/proxy"
+)
+
+// ProxyMeta is the typed metadata of the proxy endpoint of a context. It
+// holds the URL of the proxy of the docker endpoint, and the URLs of the
+// proxies of the failover endpoints, in the order of the failover endpoints.
+type ProxyMeta struct {
+	Proxy    string   `json:",omitempty"`
+	Failover []string `json:",omitempty"`
+}
+
+// IsEmpty returns whether none of the endpoints use a proxy.
+func (p ProxyMeta) IsEmpty() bool {
+	return p.Proxy == "" && !slices.ContainsFunc(p.Failover, func(s string) bool { return s != "" })
+}
+
+// ProxyFromContext parses the proxy endpoint metadata of a context. It
+// returns an empty ProxyMeta if the context has no proxies.
+func ProxyFromContext(metadata store.Metadata) (ProxyMeta, error) {
+	ep, ok := metadata.Endpoints[ProxyEndpoint]
+	if !ok {
+		return ProxyMeta{}, nil
+	}
+	typed, ok := ep.(ProxyMeta)
+	if !ok {
+		return ProxyMeta{}, fmt.Errorf("endpoint %q is not of type ProxyMeta", ProxyEndpoint)
+	}
+	return typed, nil
+}
+
+// withProxies sets the proxies of the endpoint and its failover endpoints
+// from the proxy endpoint of the context.
+func withProxies(metadata store.Metadata, ep Endpoint) (Endpoint, error) {
+	proxies, err := ProxyFromContext(metadata)
+	if err != nil {
+		return Endpoint{}, err
+	}
+	ep.Proxy = proxies.Proxy
+	for i := range ep.Failover {
+		if i < len(proxies.Failover) {
+			ep.Failover[i].Proxy = proxies.Failover[i]
+		}
+	}
+	return ep, nil
+}
+
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// ParseProxyURL parses and validates the URL of the proxy to connect to the
+// daemon through. Supported schemes are "http" and "https" for HTTP CONNECT
+// proxies, and "socks5" and "socks5h" for SOCKS5 proxies.
+func ParseProxyURL(proxyURL string) (*url.URL, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL: %q: unsupported scheme %q: must be one of http, https, socks5, or socks5h", proxyURL, u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid proxy URL: %q: no host specified", proxyURL)
+	}
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("invalid proxy URL: %q: must not contain a path, query, or fragment", proxyURL)
+	}
+	return u, nil
+}
+
+// ProxyCredentialsKey returns the key under which the credentials for the
+// proxy are kept in the credentials store.
+func ProxyCredentialsKey(u *url.URL) string {
+	return u.Scheme + "://" + u.Host
+}
+
+// proxyAuth returns the credentials to authenticate with the proxy. The
+// credentials in the URL take precedence over the credentials in the
+// credentials store.
+func proxyAuth(u *url.URL) *url.Userinfo {
+	if _, ok := u.User.Password(); ok {
+		return u.User
+	}
+	key := ProxyCredentialsKey(u)
+	configFile := config.LoadDefaultConfigFile(io.Discard)
+	creds, err := configFile.GetCredentialsStore(key).Get(key)
+	if err != nil {
+		logrus.Debugf("failed to get credentials for proxy %s: %v", key, err)
+		return u.User
+	}
+	if creds.Password == "" || (u.User != nil && u.User.Username() != creds.Username) {
+		return u.User
+	}
+	return url.UserPassword(creds.Username, creds.Password)
+}
+
+// proxyClientOpts returns the options to configure an API client that
+// connects to the endpoint through the endpoint's proxy.
+func (ep *Endpoint) proxyClientOpts() ([]client.Opt, error) {
+	if !strings.HasPrefix(ep.Host, "tcp://") {
+		return nil, fmt.Errorf("invalid docker endpoint options: a proxy can only be used for tcp:// hosts: %s", ep.Host)
+	}
+	tlsConfig, err := ep.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	dial, err := proxyDialer(ep.Proxy, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	return []client.Opt{
+		client.WithHost(ep.Host),
+		// Set the HTTP client after the host, as setting the host configures
+		// the transport to use the proxy from the environment. The TLS
+		// handshake with the daemon is performed by the dialer, so that the
+		// connections for hijacked requests (such as "attach") are also
+		// made through the proxy.
+		client.WithHTTPClient(&http.Client{
+			Transport: &http.Transport{
+				DialContext:     dial,
+				MaxIdleConns:    6,
+				IdleConnTimeout: 30 * time.Second,
+			},
+			CheckRedirect: client.CheckRedirect,
+		}),
+		client.WithAPIVersionFromEnv(),
+	}, nil
+}
+
+// proxyDialer returns a dialer that connects to the daemon through the
+// proxy, and, if tlsConfig is set, performs a TLS handshake with the daemon
+// over the connection.
+func proxyDialer(proxyURL string, tlsConfig *tls.Config) (dialFunc, error) {
+	u, err := ParseProxyURL(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	auth := proxyAuth(u)
+	forward := &net.Dialer{
+		KeepAlive: 30 * time.Second,
+		Timeout:   30 * time.Second,
+	}
+
+	var dial dialFunc
+	switch u.Scheme {
+	case "socks5", "socks5h":
+		dial = (&socksDialer{proxyURL: u, auth: auth, forward: forward}).DialContext
+	default:
+		dial = (&connectDialer{proxyURL: u, auth: auth, forward: forward}).DialContext
+	}
+
+	return func(ctx context.Context, _, addr string) (net.Conn, error) {
+		logrus.Debugf("connecting to %s through proxy %s", addr, u.Redacted())
+		conn, err := dial(ctx, "tcp", addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %s through proxy %s: %w", addr, u.Redacted(), err)
+		}
+		if tlsConfig == nil {
+			return conn, nil
+		}
+		cfg := tlsConfig.Clone()
+		if cfg.ServerName == "" {
+			cfg.ServerName, _, _ = net.SplitHostPort(addr)
+		}
+		tlsConn := tls.Client(conn, cfg)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		return tlsConn, nil
+	}, nil
+}
+
+// connectDialer connects through an HTTP proxy using the CONNECT method.
+type connectDialer struct {
+	proxyURL *url.URL
+	auth     *url.Userinfo
+	forward  *net.Dialer
+}
+
+func (d *connectDialer) DialContext(ctx context.Context, _, addr string) (net.Conn, error) {
+	proxyAddr := d.proxyURL.Host
+	if d.proxyURL.Port() == "" {
+		port := "80"
+		if d.proxyURL.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddr = net.JoinHostPort(d.proxyURL.Hostname(), port)
+	}
+	conn, err := d.forward.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, err
+	}
+	if d.proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{
+			ServerName: d.proxyURL.Hostname(),
+			MinVersion: tls.VersionTLS12,
+		})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	// Abort the handshake with the proxy if the context is cancelled.
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if d.auth != nil {
+		password, _ := d.auth.Password()
+		req.Header.Set("Proxy-Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(d.auth.Username()+":"+password)))
+	}
+	if err := req.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, errors.New("proxy responded with " + resp.Status)
+	}
+	if !stop() {
+		_ = conn.Close()
+		return nil, ctx.Err()
+	}
+	_ = conn.SetDeadline(time.Time{})
+	if br.Buffered() > 0 {
+		// The proxy must not send data before the client does, but preserve
+		// it if it does.
+		return &proxyConn{Conn: conn, r: br, raw: conn}, nil
+	}
+	return conn, nil
+}
+
+// socksDialer connects through a SOCKS5 proxy.
+type socksDialer struct {
+	proxyURL *url.URL
+	auth     *url.Userinfo
+	forward  *net.Dialer
+}
+
+func (d *socksDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	var socksAuth *proxy.Auth
+	if d.auth != nil {
+		password, _ := d.auth.Password()
+		socksAuth = &proxy.Auth{User: d.auth.Username(), Password: password}
+	}
+	// Keep the connection to the proxy, so that the write side of the
+	// connection can be closed; the connection returned by the SOCKS5
+	// dialer does not support this.
+	forward := &rawDialer{Dialer: d.forward}
+	socks, err := proxy.SOCKS5("tcp", d.proxyURL.Host, socksAuth, forward)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := socks.(proxy.ContextDialer).DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	return &proxyConn{Conn: conn, raw: forward.conn}, nil
+}
+
+// rawDialer records the connection that it made.
+type rawDialer struct {
+	*net.Dialer
+	conn net.Conn
+}
+
+func (d *rawDialer) Dial(network, addr string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, addr)
+}
+
+func (d *rawDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := d.Dialer.DialContext(ctx, network, addr)
+	d.conn = conn
+	return conn, err
+}
+
+// proxyConn is a connection through a proxy. It reads from r if set, which
+// holds data that was buffered during the handshake with the proxy, and
+// closes the write side of the connection to the proxy on CloseWrite.
+type proxyConn struct {
+	net.Conn
+	r   io.Reader
+	raw net.Conn
+}
+
+func (c *proxyConn) Read(p []byte) (int, error) {
+	if c.r != nil {
+		return c.r.Read(p)
+	}
+	return c.Conn.Read(p)
+}
+
+// CloseWrite allows proxyConn to implement halfCloser.
+func (c *proxyConn) CloseWrite() error {
+	if cw, ok := c.raw.(interface{ CloseWrite() error }); ok {
+		return cw.CloseWrite()
+	}
+	return nil
+}
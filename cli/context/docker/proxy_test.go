@@ -0,0 +1,240 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// tunnel copies data between the connections until both are closed.
+func tunnel(a, b net.Conn) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(a, b)
+		_ = a.Close()
+	}()
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(b, a)
+		_ = b.Close()
+	}()
+	wg.Wait()
+}
+
+// newConnectProxy starts an HTTP proxy that supports the CONNECT method,
+// and requires the given credentials if set.
+func newConnectProxy(t *testing.T, username, password string) (string, func() []string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	var mu sync.Mutex
+	var targets []string
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				req, err := http.ReadRequest(bufio.NewReader(conn))
+				if err != nil {
+					return
+				}
+				if username != "" {
+					expected := "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+					if req.Header.Get("Proxy-Authorization") != expected {
+						_, _ = io.WriteString(conn, "HTTP/1.1 407 Proxy Authentication Required\r\n\r\n")
+						return
+					}
+				}
+				upstream, err := net.Dial("tcp", req.Host)
+				if err != nil {
+					_, _ = io.WriteString(conn, "HTTP/1.1 502 Bad Gateway\r\n\r\n")
+					return
+				}
+				mu.Lock()
+				targets = append(targets, req.Host)
+				mu.Unlock()
+				_, _ = io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+				tunnel(conn, upstream)
+			}()
+		}
+	}()
+	return "http://" + l.Addr().String(), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return targets
+	}
+}
+
+// newSOCKS5Proxy starts a SOCKS5 proxy that requires the given credentials.
+func newSOCKS5Proxy(t *testing.T, username, password string) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				br := bufio.NewReader(conn)
+				// Greeting: version, methods; select username/password.
+				hdr := make([]byte, 2)
+				if _, err := io.ReadFull(br, hdr); err != nil {
+					return
+				}
+				if _, err := io.ReadFull(br, make([]byte, hdr[1])); err != nil {
+					return
+				}
+				_, _ = conn.Write([]byte{0x05, 0x02})
+				// Username/password authentication.
+				if _, err := io.ReadFull(br, hdr); err != nil {
+					return
+				}
+				user := make([]byte, hdr[1])
+				_, _ = io.ReadFull(br, user)
+				n, _ := br.ReadByte()
+				pass := make([]byte, n)
+				_, _ = io.ReadFull(br, pass)
+				if string(user) != username || string(pass) != password {
+					_, _ = conn.Write([]byte{0x01, 0x01})
+					return
+				}
+				_, _ = conn.Write([]byte{0x01, 0x00})
+				// Connect request; only domain names and IPv4 addresses are
+				// used in the test.
+				req := make([]byte, 4)
+				if _, err := io.ReadFull(br, req); err != nil {
+					return
+				}
+				var host string
+				switch req[3] {
+				case 0x01:
+					ip := make([]byte, 4)
+					_, _ = io.ReadFull(br, ip)
+					host = net.IP(ip).String()
+				case 0x03:
+					n, _ := br.ReadByte()
+					name := make([]byte, n)
+					_, _ = io.ReadFull(br, name)
+					host = string(name)
+				}
+				port := make([]byte, 2)
+				_, _ = io.ReadFull(br, port)
+				upstream, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))))
+				if err != nil {
+					_, _ = conn.Write([]byte{0x05, 0x05, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				_, _ = conn.Write([]byte{0x05, 0x00, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+				tunnel(conn, upstream)
+			}()
+		}
+	}()
+	return "socks5://" + l.Addr().String()
+}
+
+func ping(t *testing.T, ep Endpoint) error {
+	t.Helper()
+	opts, err := ep.ClientOpts()
+	assert.NilError(t, err)
+	apiClient, err := client.New(opts...)
+	assert.NilError(t, err)
+	defer apiClient.Close()
+	_, err = apiClient.Ping(context.Background(), client.PingOptions{})
+	return err
+}
+
+func TestProxy(t *testing.T) {
+	config.SetDir(t.TempDir())
+	daemon := newDaemon(t, 0)
+
+	t.Run("connect", func(t *testing.T) {
+		proxyURL, targets := newConnectProxy(t, "", "")
+		ep := Endpoint{EndpointMeta: EndpointMeta{Host: daemon}, Proxy: proxyURL}
+		assert.NilError(t, ping(t, ep))
+		assert.Check(t, is.DeepEqual(targets(), []string{strings.TrimPrefix(daemon, "tcp://")}))
+	})
+	t.Run("connect with credentials in URL", func(t *testing.T) {
+		proxyURL, _ := newConnectProxy(t, "me", "secret")
+		ep := Endpoint{EndpointMeta: EndpointMeta{Host: daemon}, Proxy: strings.Replace(proxyURL, "http://", "http://me:secret@", 1)}
+		assert.NilError(t, ping(t, ep))
+
+		ep.Proxy = strings.Replace(proxyURL, "http://", "http://me:wrong@", 1)
+		assert.Check(t, is.ErrorContains(ping(t, ep), "proxy responded with 407 Proxy Authentication Required"))
+	})
+	t.Run("socks5 with credentials from the credentials store", func(t *testing.T) {
+		proxyURL := newSOCKS5Proxy(t, "me", "secret")
+		auth := base64.StdEncoding.EncodeToString([]byte("me:secret"))
+		assert.NilError(t, os.WriteFile(filepath.Join(config.Dir(), config.ConfigFileName), []byte(`{"auths":{"`+proxyURL+`":{"auth":"`+auth+`"}}}`), 0o600))
+
+		ep := Endpoint{EndpointMeta: EndpointMeta{Host: daemon}, Proxy: strings.Replace(proxyURL, "socks5://", "socks5://me@", 1)}
+		assert.NilError(t, ping(t, ep))
+	})
+}
+
+func TestProxyClientOptsErrors(t *testing.T) {
+	tests := []struct {
+		doc         string
+		host        string
+		proxy       string
+		expectedErr string
+	}{
+		{
+			doc:         "unsupported host",
+			host:        "unix:///var/run/docker.sock",
+			proxy:       "http://proxy.example.com:3128",
+			expectedErr: "a proxy can only be used for tcp:// hosts",
+		},
+		{
+			doc:         "unsupported scheme",
+			host:        "tcp://example.com:2376",
+			proxy:       "ftp://proxy.example.com",
+			expectedErr: `unsupported scheme "ftp"`,
+		},
+		{
+			doc:         "no host",
+			host:        "tcp://example.com:2376",
+			proxy:       "socks5://",
+			expectedErr: "no host specified",
+		},
+		{
+			doc:         "path",
+			host:        "tcp://example.com:2376",
+			proxy:       "http://proxy.example.com/some/path",
+			expectedErr: "must not contain a path, query, or fragment",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			ep := Endpoint{EndpointMeta: EndpointMeta{Host: tc.host}, Proxy: tc.proxy}
+			_, err := ep.ClientOpts()
+			assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/containerd/errdefs"
+)
+
+// tlsKeyFile is the name of the TLS file that holds the private key of an
+// endpoint. It's the only TLS file that's kept in the key store.
+const tlsKeyFile = "key.pem"
+
+// KeyStore stores the TLS private keys of contexts outside the context store,
+// such as in a credential helper. The metadata of a context holds a reference
+// to each of its keys that are kept in the key store.
+type KeyStore interface {
+	// Get returns the key for the given reference.
+	Get(ref string) ([]byte, error)
+	// Store stores the key under the given reference.
+	Store(ref string, key []byte) error
+	// Erase removes the key for the given reference.
+	Erase(ref string) error
+}
+
+// KeyMigrator moves the TLS private keys of contexts between the context
+// store and the key store.
+type KeyMigrator interface {
+	// MigrateTLSKeys moves the TLS private keys of the context into the key
+	// store if toKeyStore is true, or out of the key store otherwise.
+	MigrateTLSKeys(name string, toKeyStore bool) error
+}
+
+// tlsKeyRef returns the reference under which the TLS private key of the
+// endpoint is kept in the key store.
+func tlsKeyRef(contextName, endpointName string) string {
+	return "docker-context://" + contextName + "/" + endpointName
+}
+
+// SetKeyStore sets the key store that holds the TLS private keys of contexts.
+// If storeKeys is true, the TLS private keys of all contexts are stored in
+// the key store when their TLS material is reset. Otherwise, keys are only
+// stored in the key store for contexts that already keep their keys there.
+func (s *ContextStore) SetKeyStore(keys KeyStore, storeKeys bool) {
+	s.keys = keys
+	s.storeKeys = storeKeys
+}
+
+// MigrateTLSKeys moves the TLS private keys of the context into the key store
+// if toKeyStore is true, or out of the key store otherwise. The keys are
+// written to their new location before they're removed from the old one.
+func (s *ContextStore) MigrateTLSKeys(name string, toKeyStore bool) error {
+	if s.keys == nil {
+		return errors.New("no key store is configured for TLS keys")
+	}
+	meta, err := s.meta.get(name)
+	if err != nil {
+		return err
+	}
+	if toKeyStore {
+		return s.migrateToKeyStore(meta)
+	}
+	return s.migrateFromKeyStore(meta)
+}
+
+func (s *ContextStore) migrateToKeyStore(meta Metadata) error {
+	files, err := s.tls.listContextData(meta.Name)
+	if err != nil {
+		return err
+	}
+	var migrated []string
+	for ep, epFiles := range files {
+		if !hasFile(epFiles, tlsKeyFile) {
+			continue
+		}
+		data, err := s.tls.getData(meta.Name, ep, tlsKeyFile)
+		if err != nil {
+			return err
+		}
+		if err := s.storeTLSKey(&meta, ep, data); err != nil {
+			return err
+		}
+		migrated = append(migrated, ep)
+	}
+	if len(migrated) == 0 {
+		return nil
+	}
+	if err := s.meta.createOrUpdate(meta); err != nil {
+		return err
+	}
+	for _, ep := range migrated {
+		if err := s.tls.removeFile(meta.Name, ep, tlsKeyFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ContextStore) migrateFromKeyStore(meta Metadata) error {
+	if len(meta.TLSKeys) == 0 {
+		return nil
+	}
+	for ep, ref := range meta.TLSKeys {
+		data, err := s.getTLSKey(meta.Name, ep, ref)
+		if err != nil {
+			return err
+		}
+		if err := s.tls.createOrUpdate(meta.Name, ep, tlsKeyFile, data); err != nil {
+			return err
+		}
+	}
+	refs := meta.TLSKeys
+	meta.TLSKeys = nil
+	if err := s.meta.createOrUpdate(meta); err != nil {
+		return err
+	}
+	for ep, ref := range refs {
+		if err := s.keys.Erase(ref); err != nil {
+			return fmt.Errorf("failed to remove TLS key for endpoint %s from key store: %w", ep, err)
+		}
+	}
+	return nil
+}
+
+// tlsKeysMetadata returns the metadata of the context for managing its TLS
+// keys, and whether new keys of the context must be stored in the key store.
+// It returns nil metadata if the context does not exist.
+func (s *ContextStore) tlsKeysMetadata(name string) (*Metadata, bool, error) {
+	meta, err := s.meta.get(name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return &meta, s.keys != nil && (s.storeKeys || len(meta.TLSKeys) > 0), nil
+}
+
+// storeTLSKey stores the key of the endpoint in the key store, and records
+// its reference in the metadata.
+func (s *ContextStore) storeTLSKey(meta *Metadata, endpointName string, data []byte) error {
+	ref := tlsKeyRef(meta.Name, endpointName)
+	if err := s.keys.Store(ref, data); err != nil {
+		return fmt.Errorf("failed to store TLS key for endpoint %s in key store: %w", endpointName, err)
+	}
+	if meta.TLSKeys == nil {
+		meta.TLSKeys = make(map[string]string)
+	}
+	meta.TLSKeys[endpointName] = ref
+	return nil
+}
+
+// getTLSKey returns the key of the endpoint from the key store.
+func (s *ContextStore) getTLSKey(contextName, endpointName, ref string) ([]byte, error) {
+	if s.keys == nil {
+		return nil, fmt.Errorf("TLS key for %s/%s is kept in a key store, but no key store is configured", contextName, endpointName)
+	}
+	data, err := s.keys.Get(ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TLS key for %s/%s from key store: %w", contextName, endpointName, err)
+	}
+	return data, nil
+}
+
+// eraseTLSKey removes the key of the endpoint from the key store, and removes
+// its reference from the metadata.
+func (s *ContextStore) eraseTLSKey(meta *Metadata, endpointName string) error {
+	ref, ok := meta.TLSKeys[endpointName]
+	if !ok {
+		return nil
+	}
+	if s.keys == nil {
+		return fmt.Errorf("TLS key for %s/%s is kept in a key store, but no key store is configured", meta.Name, endpointName)
+	}
+	if err := s.keys.Erase(ref); err != nil {
+		return fmt.Errorf("failed to remove TLS key for endpoint %s from key store: %w", endpointName, err)
+	}
+	delete(meta.TLSKeys, endpointName)
+	return nil
+}
+
+func hasFile(files EndpointFiles, name string) bool {
+	for _, f := range files {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
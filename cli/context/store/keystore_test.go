@@ -0,0 +1,165 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type fakeKeyStore map[string][]byte
+
+func (s fakeKeyStore) Get(ref string) ([]byte, error) {
+	key, ok := s[ref]
+	if !ok {
+		return nil, errdefs.ErrNotFound
+	}
+	return key, nil
+}
+
+func (s fakeKeyStore) Store(ref string, key []byte) error {
+	s[ref] = key
+	return nil
+}
+
+func (s fakeKeyStore) Erase(ref string) error {
+	delete(s, ref)
+	return nil
+}
+
+// failingKeyStore is a key store that fails to store keys, like a key store
+// without a credential helper.
+type failingKeyStore struct{ fakeKeyStore }
+
+func (failingKeyStore) Store(string, []byte) error {
+	return errors.New("no credentials store is configured")
+}
+
+func createTLSContext(t *testing.T, s *ContextStore, name string) {
+	t.Helper()
+	assert.NilError(t, s.CreateOrUpdate(Metadata{
+		Name:      name,
+		Endpoints: map[string]any{"ep1": endpoint{Foo: "bar"}},
+	}))
+	assert.NilError(t, s.ResetTLSMaterial(name, &ContextTLSData{
+		Endpoints: map[string]EndpointTLSData{
+			"ep1": {Files: map[string][]byte{
+				"ca.pem":  []byte("ca"),
+				"key.pem": []byte("key"),
+			}},
+		},
+	}))
+}
+
+func TestKeyStore(t *testing.T) {
+	keys := fakeKeyStore{}
+	s := New(t.TempDir(), testCfg)
+	s.SetKeyStore(keys, true)
+	createTLSContext(t, s, "source")
+
+	const ref = "docker-context://source/ep1"
+	assert.Check(t, is.DeepEqual(keys, fakeKeyStore{ref: []byte("key")}))
+	_, err := os.Stat(filepath.Join(s.GetStorageInfo("source").TLSPath, "ep1", "key.pem"))
+	assert.Check(t, os.IsNotExist(err))
+
+	meta, err := s.GetMetadata("source")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(meta.TLSKeys, map[string]string{"ep1": ref}))
+
+	// Updating the metadata preserves the references to the keys.
+	meta.TLSKeys = nil
+	assert.NilError(t, s.CreateOrUpdate(meta))
+	data, err := s.GetTLSData("source", "ep1", "key.pem")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "key"))
+
+	files, err := s.ListTLSFiles("source")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(files["ep1"], EndpointFiles{"ca.pem", "key.pem"}))
+
+	t.Run("export import", func(t *testing.T) {
+		r := Export("source", s)
+		defer r.Close()
+		assert.NilError(t, Import("dest", s, r))
+		data, err := s.GetTLSData("dest", "ep1", "key.pem")
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(string(data), "key"))
+		assert.Check(t, is.Len(keys, 2))
+		assert.NilError(t, s.Remove("dest"))
+		assert.Check(t, is.Len(keys, 1))
+	})
+
+	t.Run("reset endpoint", func(t *testing.T) {
+		assert.NilError(t, s.ResetEndpointTLSMaterial("source", "ep1", &EndpointTLSData{
+			Files: map[string][]byte{"key.pem": []byte("new-key")},
+		}))
+		assert.Check(t, is.DeepEqual(keys, fakeKeyStore{ref: []byte("new-key")}))
+		files, err := s.ListTLSFiles("source")
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(files["ep1"], EndpointFiles{"key.pem"}))
+	})
+}
+
+func TestResetTLSMaterialKeyStoreFailure(t *testing.T) {
+	s := New(t.TempDir(), testCfg)
+	createTLSContext(t, s, "source")
+	s.SetKeyStore(failingKeyStore{fakeKeyStore{}}, true)
+
+	err := s.ResetTLSMaterial("source", &ContextTLSData{
+		Endpoints: map[string]EndpointTLSData{
+			"ep1": {Files: map[string][]byte{"key.pem": []byte("new-key")}},
+		},
+	})
+	assert.Check(t, is.ErrorContains(err, "no credentials store is configured"))
+	err = s.ResetEndpointTLSMaterial("source", "ep1", &EndpointTLSData{
+		Files: map[string][]byte{"key.pem": []byte("new-key")},
+	})
+	assert.Check(t, is.ErrorContains(err, "no credentials store is configured"))
+
+	// The old TLS material is kept.
+	files, err := s.ListTLSFiles("source")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(files["ep1"], EndpointFiles{"ca.pem", "key.pem"}))
+	data, err := s.GetTLSData("source", "ep1", "key.pem")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "key"))
+}
+
+func TestMigrateTLSKeys(t *testing.T) {
+	keys := fakeKeyStore{}
+	s := New(t.TempDir(), testCfg)
+	createTLSContext(t, s, "ctx")
+	keyPath := filepath.Join(s.GetStorageInfo("ctx").TLSPath, "ep1", "key.pem")
+
+	err := s.MigrateTLSKeys("ctx", true)
+	assert.Check(t, is.Error(err, "no key store is configured for TLS keys"))
+
+	// Contexts keep their keys on disk unless storing keys is enabled.
+	s.SetKeyStore(keys, false)
+	createTLSContext(t, s, "other")
+	assert.Check(t, is.Len(keys, 0))
+
+	assert.NilError(t, s.MigrateTLSKeys("ctx", true))
+	assert.Check(t, is.DeepEqual(keys, fakeKeyStore{"docker-context://ctx/ep1": []byte("key")}))
+	_, err = os.Stat(keyPath)
+	assert.Check(t, os.IsNotExist(err))
+
+	// Keys of contexts that keep their keys in the key store stay there.
+	assert.NilError(t, s.ResetEndpointTLSMaterial("ctx", "ep1", &EndpointTLSData{
+		Files: map[string][]byte{"key.pem": []byte("new-key")},
+	}))
+	assert.Check(t, is.DeepEqual(keys, fakeKeyStore{"docker-context://ctx/ep1": []byte("new-key")}))
+
+	assert.NilError(t, s.MigrateTLSKeys("ctx", false))
+	assert.Check(t, is.Len(keys, 0))
+	data, err := os.ReadFile(keyPath)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "new-key"))
+	meta, err := s.GetMetadata("ctx")
+	assert.NilError(t, err)
+	assert.Check(t, is.Nil(meta.TLSKeys))
+}
@@ -85,6 +85,7 @@ func (s *metadataStore) getByID(id contextdir) (Metadata, error) {
 		return Metadata{}, fmt.Errorf("parsing %s: %v", fileName, err)
 	}
 	r.Name = untyped.Name
+	r.TLSKeys = untyped.TLSKeys
 	if r.Metadata, err = parseTypedOrMap(untyped.Metadata, s.config.contextType); err != nil {
 		return Metadata{}, fmt.Errorf("parsing %s: %v", fileName, err)
 	}
@@ -163,4 +164,5 @@ type untypedContextMetadata struct {
 	Metadata  json.RawMessage            `json:"metadata,omitempty"`
 	Endpoints map[string]json.RawMessage `json:"endpoints,omitempty"`
 	Name      string                     `json:"name,omitempty"`
+	TLSKeys   map[string]string          `json:"tlsKeys,omitempty"`
 }
@@ -13,6 +13,7 @@
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"path"
 	"path/filepath"
@@ -71,6 +72,10 @@ type Metadata struct {
 	Name      string         `json:",omitempty"`
 	Metadata  any            `json:",omitempty"`
 	Endpoints map[string]any `json:",omitempty"`
+	// TLSKeys holds the references to the TLS private keys of endpoints
+	// that are kept in the key store, by endpoint name. It's managed by the
+	// store.
+	TLSKeys map[string]string `json:",omitempty"`
 }
 
 // StorageInfo contains data about where a given context is stored
@@ -108,8 +113,10 @@ func New(dir string, cfg Config) *ContextStore {
 
 // ContextStore implements Store.
 type ContextStore struct {
-	meta *metadataStore
-	tls  *tlsStore
+	meta      *metadataStore
+	tls       *tlsStore
+	keys      KeyStore
+	storeKeys bool
 }
 
 // List return all contexts.
@@ -135,11 +142,23 @@ func Names(s Lister) ([]string, error) {
 
 // CreateOrUpdate creates or updates metadata for the context.
 func (s *ContextStore) CreateOrUpdate(meta Metadata) error {
+	// Preserve the references to TLS keys, which are managed by the store.
+	meta.TLSKeys = nil
+	if existing, err := s.meta.get(meta.Name); err == nil {
+		meta.TLSKeys = existing.TLSKeys
+	}
 	return s.meta.createOrUpdate(meta)
 }
 
 // Remove deletes the context with the given name, if found.
 func (s *ContextStore) Remove(name string) error {
+	if meta, err := s.meta.get(name); err == nil {
+		for ep := range meta.TLSKeys {
+			if err := s.eraseTLSKey(&meta, ep); err != nil {
+				return fmt.Errorf("failed to remove context %s: %w", name, err)
+			}
+		}
+	}
 	if err := s.meta.remove(name); err != nil {
 		return fmt.Errorf("failed to remove context %s: %w", name, err)
 	}
@@ -158,34 +177,125 @@ func (s *ContextStore) GetMetadata(name string) (Metadata, error) {
 // ResetTLSMaterial removes TLS data for all endpoints in the context and replaces
 // it with the new data.
 func (s *ContextStore) ResetTLSMaterial(name string, data *ContextTLSData) error {
-	if err := s.tls.remove(name); err != nil {
+	meta, useKeyStore, err := s.tlsKeysMetadata(name)
+	if err != nil {
 		return err
 	}
-	if data == nil {
-		return nil
+	oldFiles, err := s.tls.listContextData(name)
+	if err != nil {
+		return err
 	}
-	for ep, files := range data.Endpoints {
-		for fileName, data := range files.Files {
-			if err := s.tls.createOrUpdate(name, ep, fileName, data); err != nil {
-				return err
-			}
-		}
+	var oldKeys map[string]string
+	if meta != nil {
+		oldKeys = maps.Clone(meta.TLSKeys)
 	}
-	return nil
+	var endpoints map[string]EndpointTLSData
+	if data != nil {
+		endpoints = data.Endpoints
+	}
+	return s.replaceTLS(name, meta, useKeyStore, oldFiles, oldKeys, endpoints)
 }
 
 // ResetEndpointTLSMaterial removes TLS data for the given context and endpoint,
 // and replaces it with the new data.
 func (s *ContextStore) ResetEndpointTLSMaterial(contextName string, endpointName string, data *EndpointTLSData) error {
-	if err := s.tls.removeEndpoint(contextName, endpointName); err != nil {
+	meta, useKeyStore, err := s.tlsKeysMetadata(contextName)
+	if err != nil {
 		return err
 	}
-	if data == nil {
+	files, err := s.tls.listContextData(contextName)
+	if err != nil {
+		return err
+	}
+	oldFiles := map[string]EndpointFiles{}
+	if f, ok := files[endpointName]; ok {
+		oldFiles[endpointName] = f
+	}
+	oldKeys := map[string]string{}
+	if meta != nil {
+		if ref, ok := meta.TLSKeys[endpointName]; ok {
+			oldKeys[endpointName] = ref
+		}
+	}
+	endpoints := map[string]EndpointTLSData{}
+	if data != nil {
+		endpoints[endpointName] = *data
+	}
+	return s.replaceTLS(contextName, meta, useKeyStore, oldFiles, oldKeys, endpoints)
+}
+
+// replaceTLS replaces the old TLS files and keys of the context with the TLS
+// data of the endpoints. The new data is written before the old data is
+// removed, so that the old data is kept if the new data can't be written,
+// for example, because the key store is unavailable. If useKeyStore is true,
+// the TLS private keys are stored in the key store, and their references are
+// recorded in meta.
+func (s *ContextStore) replaceTLS(name string, meta *Metadata, useKeyStore bool, oldFiles map[string]EndpointFiles, oldKeys map[string]string, endpoints map[string]EndpointTLSData) error {
+	if len(oldKeys) > 0 && s.keys == nil {
+		return fmt.Errorf("TLS keys for %s are kept in a key store, but no key store is configured", name)
+	}
+
+	// Store the keys first, as the key store is the most likely to fail.
+	stored := map[string]bool{}
+	if useKeyStore {
+		for ep, data := range endpoints {
+			if key, ok := data.Files[tlsKeyFile]; ok {
+				if err := s.storeTLSKey(meta, ep, key); err != nil {
+					return err
+				}
+				stored[ep] = true
+			}
+		}
+	}
+	for ep, data := range endpoints {
+		for fileName, fileData := range data.Files {
+			if fileName == tlsKeyFile && stored[ep] {
+				continue
+			}
+			if err := s.tls.createOrUpdate(name, ep, fileName, fileData); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Remove the old files that were not replaced.
+	for ep, files := range oldFiles {
+		data, ok := endpoints[ep]
+		if !ok {
+			if err := s.tls.removeEndpoint(name, ep); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, fileName := range files {
+			if _, ok := data.Files[fileName]; ok && !(fileName == tlsKeyFile && stored[ep]) {
+				continue
+			}
+			if err := s.tls.removeFile(name, ep, fileName); err != nil {
+				return err
+			}
+		}
+	}
+	if meta == nil {
 		return nil
 	}
-	for fileName, data := range data.Files {
-		if err := s.tls.createOrUpdate(contextName, endpointName, fileName, data); err != nil {
-			return err
+
+	// Remove the old keys that were not replaced. Keys are stored under the
+	// same reference for an endpoint, so keys that were replaced are kept.
+	for ep := range oldKeys {
+		if !stored[ep] {
+			delete(meta.TLSKeys, ep)
+		}
+	}
+	if err := s.meta.createOrUpdate(*meta); err != nil {
+		return err
+	}
+	for ep, ref := range oldKeys {
+		if stored[ep] {
+			continue
+		}
+		if err := s.keys.Erase(ref); err != nil {
+			return fmt.Errorf("failed to remove TLS key for endpoint %s from key store: %w", ep, err)
 		}
 	}
 	return nil
@@ -194,12 +304,28 @@ func (s *ContextStore) ResetEndpointTLSMaterial(contextName string, endpointName
 // ListTLSFiles returns the list of TLS files present for each endpoint in the
 // context.
 func (s *ContextStore) ListTLSFiles(name string) (map[string]EndpointFiles, error) {
-	return s.tls.listContextData(name)
+	files, err := s.tls.listContextData(name)
+	if err != nil {
+		return nil, err
+	}
+	if meta, err := s.meta.get(name); err == nil {
+		for ep := range meta.TLSKeys {
+			files[ep] = append(files[ep], tlsKeyFile)
+		}
+	}
+	return files, nil
 }
 
 // GetTLSData reads, and returns the content of the given fileName for an endpoint.
 // It returns an errdefs.ErrNotFound if the file was not found.
 func (s *ContextStore) GetTLSData(contextName, endpointName, fileName string) ([]byte, error) {
+	if fileName == tlsKeyFile {
+		if meta, err := s.meta.get(contextName); err == nil {
+			if ref, ok := meta.TLSKeys[endpointName]; ok {
+				return s.getTLSKey(contextName, endpointName, ref)
+			}
+		}
+	}
 	return s.tls.getData(contextName, endpointName, fileName)
 }
 
@@ -271,6 +397,9 @@ func Export(name string, s Reader) io.ReadCloser {
 			writer.CloseWithError(err)
 			return
 		}
+		// The TLS keys are included in the export, so references to keys
+		// in the key store are not exported.
+		meta.TLSKeys = nil
 		metaBytes, err := json.Marshal(&meta)
 		if err != nil {
 			writer.CloseWithError(err)
@@ -504,6 +633,7 @@ func parseMetadata(data []byte, name string) (Metadata, error) {
 		return Metadata{}, err
 	}
 	meta.Name = name
+	meta.TLSKeys = nil
 	return meta, nil
 }
 
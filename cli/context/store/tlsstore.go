@@ -60,6 +60,13 @@ func (s *tlsStore) removeEndpoint(name, endpointName string) error {
 	return nil
 }
 
+func (s *tlsStore) removeFile(name, endpointName, filename string) error {
+	if err := os.Remove(filepath.Join(s.endpointDir(name, endpointName), filename)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove TLS data for endpoint %s: %w", endpointName, err)
+	}
+	return nil
+}
+
 func (s *tlsStore) listContextData(name string) (map[string]EndpointFiles, error) {
 	contextDir := s.contextDir(name)
 	epFSs, err := os.ReadDir(contextDir)
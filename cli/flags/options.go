@@ -39,11 +39,18 @@
 'table':            Print output in table format with column headers (default)
 'table TEMPLATE':   Print output in table format using the given Go template
 'json':             Print in JSON format
+'ndjson':           Print in newline-delimited JSON format
+'yaml':             Print in YAML format
+'csv':              Print in CSV format with column headers
+'csv TEMPLATE':     Print output in CSV format using the given Go template
 'TEMPLATE':         Print output using the given Go template.
 Refer to https://docs.docker.com/go/formatting/ for more information about formatting output with templates`
 	// InspectFormatHelp describes the --format flag behavior for inspect commands
 	InspectFormatHelp = `Format output using a custom template:
 'json':             Print in JSON format
+'ndjson':           Print in newline-delimited JSON format
+'yaml':             Print in YAML format
+'csv':              Print in CSV format, with nested fields as separate columns
 'TEMPLATE':         Print output using the given Go template.
 Refer to https://docs.docker.com/go/formatting/ for more information about formatting output with templates`
 )
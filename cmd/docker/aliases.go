@@ -1,39 +1,53 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"syscall"
 
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/cli"
 	pluginmanager "github.com/docker/cli/cli-plugins/manager"
 	"github.com/docker/cli/cli/command"
+	"github.com/google/shlex"
 	"github.com/spf13/cobra"
 )
 
 const (
 	keyBuilderAlias = "builder"
+
+	// shellAliasPrefix is the prefix for aliases that are executed as a
+	// shell command, instead of being expanded to a docker command.
+	shellAliasPrefix = "!"
 )
 
-var allowedAliases = map[string]struct{}{
-	keyBuilderAlias: {},
-}
+// aliasParamRe matches positional parameters ($1, $2, ...) and escaped
+// dollar-signs ($$) in an alias' expansion.
+var aliasParamRe = regexp.MustCompile(`\$(\$|[0-9]+)`)
 
 func processAliases(dockerCli command.Cli, cmd *cobra.Command, args, osArgs []string) ([]string, []string, []string, error) {
 	var err error
 	var envs []string
 	aliasMap := dockerCli.ConfigFile().Aliases
-	aliases := make([][2][]string, 0, len(aliasMap))
+	aliases := make([][2][]string, 0, 1)
 
-	for k, v := range aliasMap {
-		if _, ok := allowedAliases[k]; !ok {
-			return args, osArgs, envs, fmt.Errorf("not allowed to alias %q (allowed: %#v)", k, allowedAliases)
-		}
+	if v, ok := aliasMap[keyBuilderAlias]; ok {
 		if c, _, err := cmd.Find(strings.Split(v, " ")); err == nil {
 			if !pluginmanager.IsPluginCommand(c) {
 				return args, osArgs, envs, fmt.Errorf("not allowed to alias with builtin %q as target", v)
 			}
 		}
-		aliases = append(aliases, [2][]string{{k}, {v}})
+		aliases = append(aliases, [2][]string{{keyBuilderAlias}, {v}})
 	}
 
 	args, osArgs, envs, err = processBuilder(dockerCli, cmd, args, os.Args)
@@ -52,3 +66,172 @@ func processAliases(dockerCli command.Cli, cmd *cobra.Command, args, osArgs []st
 
 	return args, osArgs, envs, nil
 }
+
+// expandUserAliases expands a user-defined alias if it is the first argument
+// in args. Aliases can refer to other aliases. If the alias is a shell alias,
+// its command is returned as shellCmd, and args are returned as-is.
+//
+// Built-in commands and CLI plugins take precedence over aliases; a warning
+// is printed when invoking an alias that is shadowed by either of them.
+func expandUserAliases(dockerCli command.Cli, cmd *cobra.Command, args, osArgs []string) (_ []string, _ []string, shellCmd string, _ error) {
+	seen := map[string]struct{}{}
+	for len(args) > 0 {
+		name := args[0]
+		expansion, err := lookupUserAlias(dockerCli, cmd, name)
+		if err != nil {
+			_, _ = fmt.Fprintln(dockerCli.Err(), "WARNING:", err)
+			break
+		}
+		if expansion == "" {
+			break
+		}
+		if _, ok := seen[name]; ok {
+			return args, osArgs, "", fmt.Errorf("alias %q: recursive alias expansion", name)
+		}
+		seen[name] = struct{}{}
+
+		if strings.HasPrefix(expansion, shellAliasPrefix) {
+			return args, osArgs, strings.TrimPrefix(expansion, shellAliasPrefix), nil
+		}
+		aliasArgs, err := shlex.Split(expansion)
+		if err != nil {
+			return args, osArgs, "", fmt.Errorf("alias %q: invalid expansion: %w", name, err)
+		}
+		if len(aliasArgs) == 0 {
+			return args, osArgs, "", fmt.Errorf("alias %q: empty expansion", name)
+		}
+
+		// args are the remaining arguments after global flags, so the alias
+		// is at the same position from the end of osArgs.
+		idx := len(osArgs) - len(args)
+		expanded := expandAliasArgs(aliasArgs, args[1:])
+		osArgs = append(append([]string{}, osArgs[:idx]...), expanded...)
+		args = expanded
+	}
+	return args, osArgs, "", nil
+}
+
+// lookupUserAlias returns the expansion for the user-defined alias with the
+// given name, or an empty string if no alias exists. An error is returned if
+// the alias is shadowed by a built-in command or CLI plugin.
+func lookupUserAlias(dockerCli command.Cli, cmd *cobra.Command, name string) (string, error) {
+	if name == keyBuilderAlias {
+		return "", nil
+	}
+	expansion, ok := dockerCli.ConfigFile().Aliases[name]
+	if !ok {
+		return "", nil
+	}
+	if isBuiltinCommand(cmd, name) {
+		return "", fmt.Errorf("ignoring alias %q: it shadows a built-in command", name)
+	}
+	if _, err := pluginmanager.GetPlugin(name, dockerCli, cmd.Root()); !errdefs.IsNotFound(err) {
+		return "", fmt.Errorf("ignoring alias %q: it shadows a CLI plugin", name)
+	}
+	return expansion, nil
+}
+
+func isBuiltinCommand(cmd *cobra.Command, name string) bool {
+	root := cmd.Root()
+	c, _, err := root.Find([]string{name})
+	return err == nil && c != root && !pluginmanager.IsPluginCommand(c) && !cli.IsUserAlias(c)
+}
+
+// expandAliasArgs substitutes positional parameters in the alias' arguments.
+// "$1", "$2", etc. are replaced with the corresponding argument, "$@" as a
+// separate argument is replaced with all arguments, and "$$" is replaced
+// with a literal "$". Arguments that are not consumed by a positional
+// parameter are appended.
+func expandAliasArgs(aliasArgs, args []string) []string {
+	var out []string
+	consumed := 0
+	for _, a := range aliasArgs {
+		if a == "$@" {
+			out = append(out, args...)
+			consumed = len(args)
+			continue
+		}
+		out = append(out, aliasParamRe.ReplaceAllStringFunc(a, func(m string) string {
+			if m == "$$" {
+				return "$"
+			}
+			n, _ := strconv.Atoi(m[1:])
+			if n < 1 || n > len(args) {
+				return ""
+			}
+			consumed = max(consumed, n)
+			return args[n-1]
+		}))
+	}
+	if consumed < len(args) {
+		out = append(out, args[consumed:]...)
+	}
+	return out
+}
+
+// runShellAlias executes a shell alias, where args[0] is the alias' name.
+// Similar to git, arguments are passed to the shell command as positional
+// parameters, and appended to the command if any are given.
+func runShellAlias(ctx context.Context, dockerCli command.Cli, shellCmd string, args []string) error {
+	name := args[0]
+	if len(args) > 1 {
+		shellCmd += ` "$@"`
+	}
+	c := exec.CommandContext(ctx, "sh", append([]string{"-c", shellCmd}, args...)...)
+	c.Stdin = dockerCli.In()
+	c.Stdout = dockerCli.Out()
+	c.Stderr = dockerCli.Err()
+	c.Env = os.Environ()
+	if ctxName := dockerCli.CurrentContext(); ctxName != command.DefaultContextName {
+		// Make sure docker commands in the shell command use the same
+		// context, for example, if it was set through the "--context" flag.
+		c.Env = append(c.Env, command.EnvOverrideContext+"="+ctxName)
+	}
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return fmt.Errorf("alias %q: %w", name, err)
+		}
+		statusCode := 1
+		if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			statusCode = ws.ExitStatus()
+		}
+		return cli.StatusError{StatusCode: statusCode}
+	}
+	return nil
+}
+
+// addUserAliasCommandStubs adds a stub command for each user-defined alias
+// to the root command, so that aliases are included in the help output and
+// in shell completion.
+func addUserAliasCommandStubs(dockerCli command.Cli, rootCmd *cobra.Command) {
+	aliases := dockerCli.ConfigFile().Aliases
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if name == keyBuilderAlias {
+			continue
+		}
+		if c, _, err := rootCmd.Find([]string{name}); err == nil && c != rootCmd {
+			// shadowed by a built-in command, plugin, or an existing stub.
+			continue
+		}
+		expansion := aliases[name]
+		short := "Alias for \"docker " + expansion + "\""
+		if strings.HasPrefix(expansion, shellAliasPrefix) {
+			short = "Alias for shell command \"" + strings.TrimPrefix(expansion, shellAliasPrefix) + "\""
+		}
+		rootCmd.AddCommand(&cobra.Command{
+			Use:                   name,
+			Short:                 short,
+			Run:                   func(*cobra.Command, []string) {},
+			Annotations:           map[string]string{cli.UserAliasAnnotation: expansion},
+			DisableFlagParsing:    true,
+			DisableFlagsInUseLine: true,
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/flags"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func newAliasTestCli(t *testing.T, aliases map[string]string) (*command.DockerCli, *bytes.Buffer) {
+	t.Helper()
+	var b bytes.Buffer
+	dockerCli, err := command.NewDockerCli(
+		command.WithBaseContext(t.Context()),
+		command.WithAPIClient(&fakeClient{}),
+		command.WithInputStream(discard),
+		command.WithCombinedStreams(&b),
+	)
+	assert.NilError(t, err)
+	assert.NilError(t, dockerCli.Initialize(flags.NewClientOptions()))
+	dockerCli.ConfigFile().CLIPluginsExtraDirs = []string{t.TempDir()}
+	dockerCli.ConfigFile().Aliases = aliases
+	return dockerCli, &b
+}
+
+func TestExpandAliasArgs(t *testing.T) {
+	testCases := []struct {
+		doc       string
+		aliasArgs []string
+		args      []string
+		expected  []string
+	}{
+		{
+			doc:       "no parameters",
+			aliasArgs: []string{"ps", "-a"},
+			args:      []string{"--no-trunc"},
+			expected:  []string{"ps", "-a", "--no-trunc"},
+		},
+		{
+			doc:       "positional parameters",
+			aliasArgs: []string{"exec", "-it", "$1", "sh", "-c", "echo $2"},
+			args:      []string{"web", "hello", "--extra"},
+			expected:  []string{"exec", "-it", "web", "sh", "-c", "echo hello", "--extra"},
+		},
+		{
+			doc:       "missing positional parameter",
+			aliasArgs: []string{"logs", "$1"},
+			expected:  []string{"logs", ""},
+		},
+		{
+			doc:       "all parameters",
+			aliasArgs: []string{"run", "--rm", "$@", "--label", "foo"},
+			args:      []string{"alpine", "echo"},
+			expected:  []string{"run", "--rm", "alpine", "echo", "--label", "foo"},
+		},
+		{
+			doc:       "escaped dollar",
+			aliasArgs: []string{"ps", "--format", "$$1"},
+			args:      []string{"-a"},
+			expected:  []string{"ps", "--format", "$1", "-a"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			assert.Check(t, is.DeepEqual(expandAliasArgs(tc.aliasArgs, tc.args), tc.expected))
+		})
+	}
+}
+
+func TestExpandUserAliases(t *testing.T) {
+	dockerCli, out := newAliasTestCli(t, map[string]string{
+		"lsa":   `ps -a --format 'table {{.Names}}\t{{.Status}}'`,
+		"la":    "lsa --no-trunc",
+		"nuke":  "!docker ps -q | xargs docker rm -f",
+		"ps":    "ps -a",
+		"loop":  "loop2",
+		"loop2": "loop",
+		"bad":   `ps "unterminated`,
+	})
+	tcmd := newDockerCommand(dockerCli)
+	tcmd.SetArgs([]string{"--debug", "la", "-q"})
+	cmd, args, err := tcmd.HandleGlobalFlags()
+	assert.NilError(t, err)
+
+	osArgs := []string{"docker", "--debug", "la", "-q"}
+	args, osArgs, shellCmd, err := expandUserAliases(dockerCli, cmd, args, osArgs)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(shellCmd, ""))
+	expected := []string{"ps", "-a", "--format", "table {{.Names}}\\t{{.Status}}", "--no-trunc", "-q"}
+	assert.Check(t, is.DeepEqual(args, expected))
+	assert.Check(t, is.DeepEqual(osArgs, append([]string{"docker", "--debug"}, expected...)))
+
+	args, _, shellCmd, err = expandUserAliases(dockerCli, cmd, []string{"nuke", "foo"}, []string{"docker", "nuke", "foo"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(shellCmd, "docker ps -q | xargs docker rm -f"))
+	assert.Check(t, is.DeepEqual(args, []string{"nuke", "foo"}))
+
+	args, _, _, err = expandUserAliases(dockerCli, cmd, []string{"ps"}, []string{"docker", "ps"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(args, []string{"ps"}))
+	assert.Check(t, is.Contains(out.String(), `WARNING: ignoring alias "ps": it shadows a built-in command`))
+
+	_, _, _, err = expandUserAliases(dockerCli, cmd, []string{"loop"}, []string{"docker", "loop"})
+	assert.Check(t, is.Error(err, `alias "loop": recursive alias expansion`))
+
+	_, _, _, err = expandUserAliases(dockerCli, cmd, []string{"bad"}, []string{"docker", "bad"})
+	assert.Check(t, is.ErrorContains(err, `alias "bad": invalid expansion`))
+}
+
+func TestUserAliasCommandStubs(t *testing.T) {
+	dockerCli, _ := newAliasTestCli(t, map[string]string{
+		"builder": "buildx",
+		"lsa":     "ps -a",
+		"nuke":    "!docker ps -q | xargs docker rm -f",
+		"images":  "images -a",
+	})
+	tcmd := newDockerCommand(dockerCli)
+	cmd, _, err := tcmd.HandleGlobalFlags()
+	assert.NilError(t, err)
+
+	addUserAliasCommandStubs(dockerCli, cmd)
+	addUserAliasCommandStubs(dockerCli, cmd)
+
+	var stubs []string
+	for _, c := range cmd.Commands() {
+		if cli.IsUserAlias(c) {
+			stubs = append(stubs, c.Name()+": "+c.Short)
+		}
+	}
+	assert.Check(t, is.DeepEqual(stubs, []string{
+		`lsa: Alias for "docker ps -a"`,
+		`nuke: Alias for shell command "docker ps -q | xargs docker rm -f"`,
+	}))
+
+	args := expandAliasCompletionArgs(dockerCli, cmd, []string{"__complete", "lsa", "--f"})
+	assert.Check(t, is.DeepEqual(args, []string{"__complete", "ps", "-a", "--f"}))
+
+	args = expandAliasCompletionArgs(dockerCli, cmd, []string{"__complete", "nuke", ""})
+	assert.Check(t, is.DeepEqual(args, []string{"__complete", "nuke", ""}))
+}
@@ -1,7 +1,11 @@
 package main
 
 import (
+	"strings"
+
+	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/context/store"
+	"github.com/google/shlex"
 	"github.com/spf13/cobra"
 )
 
@@ -21,3 +25,29 @@ func completeContextNames(dockerCLI contextStoreProvider) func(*cobra.Command, [
 func completeLogLevels(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
 	return cobra.FixedCompletions(logLevels, cobra.ShellCompDirectiveNoFileComp)(nil, nil, "")
 }
+
+// expandAliasCompletionArgs expands a user-defined alias in a shell completion
+// request ("docker __complete ALIAS ..."), so that the arguments and flags of
+// the alias are completed as for the command it expands to. Shell aliases are
+// not expanded.
+func expandAliasCompletionArgs(dockerCLI command.Cli, cmd *cobra.Command, args []string) []string {
+	if len(args) < 2 || !hasCompletionArg(args[:1]) {
+		return args
+	}
+	expansion, err := lookupUserAlias(dockerCLI, cmd, args[1])
+	if err != nil || expansion == "" || strings.HasPrefix(expansion, shellAliasPrefix) {
+		return args
+	}
+	aliasArgs, err := shlex.Split(expansion)
+	if err != nil {
+		return args
+	}
+	out := []string{args[0]}
+	for _, a := range expandAliasArgs(aliasArgs, nil) {
+		// positional parameters are expanded to an empty string.
+		if a != "" {
+			out = append(out, a)
+		}
+	}
+	return append(out, args[2:]...)
+}
@@ -186,7 +186,7 @@ func newDockerCommand(dockerCli *command.DockerCli) *cli.TopLevelCommand {
 	cmd.SetOut(dockerCli.Out())
 	commands.AddCommands(cmd, dockerCli)
 
-	visitAll(cmd, setValidateArgs(dockerCli))
+	visitAll(cmd, setValidateArgs(dockerCli), setArgumentPicker(dockerCli))
 
 	// flags must be the top-level command flags, not cmd.Flags()
 	return cli.NewTopLevelCommand(cmd, dockerCli, opts, cmd.Flags())
@@ -250,6 +250,7 @@ func setHelpFunc(dockerCli command.Cli, cmd *cobra.Command) {
 			ccmd.Println(err)
 			return
 		}
+		addUserAliasCommandStubs(dockerCli, ccmd.Root())
 
 		if len(args) >= 1 {
 			err := tryRunPluginHelp(dockerCli, ccmd, args)
@@ -497,12 +498,25 @@ func runDocker(ctx context.Context, dockerCli *command.DockerCli) error {
 
 	dockerCli.InstrumentCobraCommands(ctx, cmd)
 
+	var shellAlias string
+	args, os.Args, shellAlias, err = expandUserAliases(dockerCli, cmd, args, os.Args)
+	if err != nil {
+		return err
+	}
+	if shellAlias != "" {
+		return runShellAlias(ctx, dockerCli, shellAlias, args)
+	}
+
 	var envs []string
 	args, os.Args, envs, err = processAliases(dockerCli, cmd, args, os.Args)
 	if err != nil {
 		return err
 	}
 
+	if isFanOutContext(dockerCli.CurrentContext()) && !hasCompletionArg(args) {
+		return runFanOut(ctx, dockerCli, cmd, args)
+	}
+
 	if hasCompletionArg(args) {
 		// We add plugin command stubs early only for completion. We don't
 		// want to add them for normal command execution as it would cause
@@ -510,6 +524,8 @@ func runDocker(ctx context.Context, dockerCli *command.DockerCli) error {
 		if err := pluginmanager.AddPluginCommandStubs(dockerCli, cmd); err != nil {
 			return err
 		}
+		addUserAliasCommandStubs(dockerCli, cmd)
+		args = expandAliasCompletionArgs(dockerCli, cmd, args)
 	}
 
 	var subCommand *cobra.Command
@@ -205,3 +205,31 @@ func TestVisitAll(t *testing.T) {
 	expected := []string{"sub1sub1", "sub1sub2", "sub1", "sub2", "root"}
 	assert.DeepEqual(t, expected, visited)
 }
+
+func TestFirstArgName(t *testing.T) {
+	for _, tc := range []struct {
+		use      string
+		expected string
+	}{
+		{use: "logs [OPTIONS] CONTAINER", expected: "container"},
+		{use: "exec [OPTIONS] CONTAINER COMMAND [ARG...]", expected: "container"},
+		{use: "inspect [OPTIONS] VOLUME [VOLUME...]", expected: "volume"},
+		{use: "prune [OPTIONS]", expected: "an argument"},
+	} {
+		assert.Check(t, is.Equal(firstArgName(&cobra.Command{Use: tc.use}), tc.expected))
+	}
+}
+
+func TestPickCandidates(t *testing.T) {
+	cmd := &cobra.Command{
+		ValidArgsFunction: func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+			return []string{"web\tUp 2 hours", "db"}, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+	assert.Check(t, is.DeepEqual(pickCandidates(cmd), []string{"web", "db"}))
+
+	cmd.ValidArgsFunction = func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	assert.Check(t, is.Len(pickCandidates(cmd), 0))
+}
@@ -0,0 +1,351 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/spf13/cobra"
+)
+
+// fanOutCommands are the read-only commands that can be run against
+// multiple contexts at once.
+var fanOutCommands = []string{
+	"docker container ls",
+	"docker image ls",
+	"docker images",
+	"docker network ls",
+	"docker ps",
+	"docker volume ls",
+}
+
+// fanOutFormats returns the output format that's configured for each of the
+// fanOutCommands in the configuration file, which is used if the command is
+// run without a --format flag.
+func fanOutFormats(configFile *configfile.ConfigFile) map[string]string {
+	return map[string]string{
+		"docker container ls": configFile.PsFormat,
+		"docker image ls":     configFile.ImagesFormat,
+		"docker images":       configFile.ImagesFormat,
+		"docker network ls":   configFile.NetworksFormat,
+		"docker ps":           configFile.PsFormat,
+		"docker volume ls":    configFile.VolumesFormat,
+	}
+}
+
+// fanOutMode describes how the output of a command is merged.
+type fanOutMode int
+
+const (
+	// fanOutRaw writes the output as-is, in the order of the contexts.
+	fanOutRaw fanOutMode = iota
+	// fanOutTable merges the tables into a single table with a "CONTEXT"
+	// column.
+	fanOutTable
+	// fanOutJSON adds a "Context" field to each JSON object.
+	fanOutJSON
+)
+
+// outputMode returns how the output of the command is merged, based on the
+// format that the command uses.
+func outputMode(format string, quiet bool) fanOutMode {
+	f := formatter.Format(format)
+	switch {
+	case quiet:
+		return fanOutRaw
+	case format == "", f.IsTable():
+		return fanOutTable
+	case f.IsJSON(), format == formatter.NDJSONFormatKey:
+		return fanOutJSON
+	default:
+		return fanOutRaw
+	}
+}
+
+// isFanOutContext returns whether the context name selects multiple
+// contexts, either through a glob pattern, or a comma-separated list.
+// Context names cannot contain these characters.
+func isFanOutContext(name string) bool {
+	return strings.ContainsAny(name, "*?[,")
+}
+
+// matchContexts returns the names of the contexts that are selected by the
+// comma-separated list of names and glob patterns, in the order in which
+// they're selected.
+func matchContexts(s store.Store, selector string) ([]string, error) {
+	all, err := store.Names(s)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, pattern := range strings.Split(selector, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if !strings.ContainsAny(pattern, "*?[") {
+			if _, err := s.GetMetadata(pattern); err != nil {
+				return nil, err
+			}
+			if !slices.Contains(names, pattern) {
+				names = append(names, pattern)
+			}
+			continue
+		}
+		var matched bool
+		for _, name := range all {
+			ok, err := path.Match(pattern, name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid context pattern %q: %w", pattern, err)
+			}
+			if ok {
+				matched = true
+				if !slices.Contains(names, name) {
+					names = append(names, name)
+				}
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no contexts match %q", pattern)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no contexts match %q", selector)
+	}
+	return names, nil
+}
+
+// fanOutResult holds the output of a command that was run against a single
+// context.
+type fanOutResult struct {
+	context string
+	stdout  []byte
+	stderr  []byte
+	err     error
+}
+
+// runFanOut runs the command against each of the contexts that are selected
+// by the --context flag concurrently, and merges their output. Failures for
+// a context are reported after the output, without aborting the command for
+// other contexts.
+func runFanOut(ctx context.Context, dockerCli *command.DockerCli, cmd *cobra.Command, args []string) error {
+	ccmd, flags, err := cmd.Find(args)
+	if err != nil || !slices.Contains(fanOutCommands, ccmd.CommandPath()) {
+		return fmt.Errorf("multiple contexts can only be used with the following commands: %s", strings.Join(fanOutCommands, ", "))
+	}
+	if err := ccmd.ParseFlags(flags); err != nil {
+		return err
+	}
+	format := fanOutFormats(dockerCli.ConfigFile())[ccmd.CommandPath()]
+	if f := ccmd.Flags().Lookup("format"); f != nil && f.Changed {
+		format = f.Value.String()
+	}
+	quiet, _ := ccmd.Flags().GetBool("quiet")
+	names, err := matchContexts(dockerCli.ContextStore(), dockerCli.CurrentContext())
+	if err != nil {
+		return err
+	}
+
+	results := make([]fanOutResult, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runInContext(ctx, name, args)
+		}()
+	}
+	wg.Wait()
+
+	writeFanOutOutput(dockerCli.Out(), outputMode(format, quiet), results)
+	var errs []error
+	for _, r := range results {
+		writePrefixed(dockerCli.Err(), r.context, r.stderr)
+		if r.err != nil {
+			errs = append(errs, fmt.Errorf("context %s: %w", r.context, r.err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runInContext runs the command with a CLI of its own for the given context,
+// and captures its output.
+func runInContext(ctx context.Context, name string, args []string) (r fanOutResult) {
+	var stdout, stderr bytes.Buffer
+	r.context = name
+	defer func() {
+		r.stdout, r.stderr = stdout.Bytes(), stderr.Bytes()
+	}()
+
+	contextCLI, err := command.NewDockerCli(
+		command.WithBaseContext(ctx),
+		command.WithInputStream(io.NopCloser(strings.NewReader(""))),
+		command.WithOutputStream(&stdout),
+		command.WithErrorStream(&stderr),
+	)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	tcmd := newDockerCommand(contextCLI)
+	cmd, _, err := tcmd.HandleGlobalFlags()
+	if err != nil {
+		r.err = err
+		return r
+	}
+	tcmd.SetFlag("context", name)
+	if err := tcmd.Initialize(); err != nil {
+		r.err = err
+		return r
+	}
+	cmd.SetArgs(args)
+	r.err = cmd.ExecuteContext(ctx)
+	return r
+}
+
+// writeFanOutOutput writes the merged output of the results. Tables are
+// merged into a single table with a "CONTEXT" column, and a "Context" field
+// is added to JSON objects. Other output, and tables that don't have the
+// same columns, are written as-is, in the order of the contexts.
+func writeFanOutOutput(out io.Writer, mode fanOutMode, results []fanOutResult) {
+	var outputs []fanOutResult
+	for _, r := range results {
+		if len(r.stdout) > 0 {
+			outputs = append(outputs, r)
+		}
+	}
+	switch {
+	case len(outputs) == 0:
+	case mode == fanOutTable && sameColumns(outputs):
+		writeMergedTable(out, outputs)
+	case mode == fanOutJSON:
+		for _, r := range outputs {
+			for _, line := range splitLines(r.stdout) {
+				_, _ = fmt.Fprintln(out, addContextField(line, r.context))
+			}
+		}
+	default:
+		for _, r := range outputs {
+			_, _ = out.Write(r.stdout)
+		}
+	}
+}
+
+// sameColumns returns whether the tables of the outputs have the same
+// columns. The columns may have different widths.
+func sameColumns(outputs []fanOutResult) bool {
+	header, _, _ := strings.Cut(string(outputs[0].stdout), "\n")
+	names := splitColumns(header, columnOffsets(header))
+	for _, r := range outputs[1:] {
+		h, _, _ := strings.Cut(string(r.stdout), "\n")
+		if !slices.Equal(splitColumns(h, columnOffsets(h)), names) {
+			return false
+		}
+	}
+	return true
+}
+
+// addContextField adds a "Context" field with the name of the context to
+// the JSON object. Lines that aren't JSON objects are returned as-is.
+func addContextField(object, contextName string) string {
+	if !strings.HasPrefix(object, "{") || !strings.HasSuffix(object, "}") {
+		return object
+	}
+	name, _ := json.Marshal(contextName)
+	if strings.TrimSpace(object[1:len(object)-1]) == "" {
+		return `{"Context":` + string(name) + `}`
+	}
+	return `{"Context":` + string(name) + "," + object[1:]
+}
+
+// writeMergedTable writes the tables of the outputs as a single table, with
+// a "CONTEXT" column. The columns of each table are found from the offsets
+// of the column names in its header, which are aligned by the tab-writer.
+func writeMergedTable(out io.Writer, outputs []fanOutResult) {
+	tw := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+	for i, r := range outputs {
+		lines := splitLines(r.stdout)
+		offsets := columnOffsets(lines[0])
+		if i == 0 {
+			_, _ = fmt.Fprintln(tw, "CONTEXT\t"+strings.Join(splitColumns(lines[0], offsets), "\t"))
+		}
+		for _, line := range lines[1:] {
+			_, _ = fmt.Fprintln(tw, r.context+"\t"+strings.Join(splitColumns(line, offsets), "\t"))
+		}
+	}
+	_ = tw.Flush()
+}
+
+// columnOffsets returns the offsets, in runes, at which the columns in the
+// header start. Columns are separated by at least the padding of the
+// tab-writer, whereas column names contain single spaces at most.
+func columnOffsets(header string) []int {
+	offsets := []int{0}
+	var spaces int
+	for i, c := range []rune(header) {
+		switch {
+		case c == ' ':
+			spaces++
+		case spaces > 1:
+			offsets = append(offsets, i)
+			spaces = 0
+		default:
+			spaces = 0
+		}
+	}
+	return offsets
+}
+
+// splitColumns splits the line of a table into the values of its columns.
+func splitColumns(line string, offsets []int) []string {
+	runes := []rune(line)
+	columns := make([]string, 0, len(offsets))
+	for i, start := range offsets {
+		end := len(runes)
+		if i+1 < len(offsets) {
+			end = min(offsets[i+1], len(runes))
+		}
+		if start >= end {
+			columns = append(columns, "")
+			continue
+		}
+		columns = append(columns, strings.TrimSpace(string(runes[start:end])))
+	}
+	return columns
+}
+
+func splitLines(b []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// writePrefixed writes each line of the output, prefixed with the name of
+// the context.
+func writePrefixed(out io.Writer, contextName string, b []byte) {
+	for _, line := range splitLines(b) {
+		_, _ = fmt.Fprintf(out, "%s: %s\n", contextName, line)
+	}
+}
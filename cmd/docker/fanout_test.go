@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/context/store"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestMatchContexts(t *testing.T) {
+	s := &command.ContextStoreWithDefault{
+		Store: store.New(t.TempDir(), command.DefaultContextStoreConfig()),
+		Resolver: func() (*command.DefaultContext, error) {
+			return &command.DefaultContext{Meta: store.Metadata{Name: command.DefaultContextName}}, nil
+		},
+	}
+	for _, name := range []string{"prod-b", "prod-a", "dev"} {
+		assert.NilError(t, s.CreateOrUpdate(store.Metadata{Name: name}))
+	}
+
+	tests := []struct {
+		selector    string
+		expected    []string
+		expectedErr string
+	}{
+		{selector: "prod-*", expected: []string{"prod-a", "prod-b"}},
+		{selector: "dev,prod-b", expected: []string{"dev", "prod-b"}},
+		{selector: "prod-b, prod-*", expected: []string{"prod-b", "prod-a"}},
+		{selector: "*", expected: []string{"dev", "prod-a", "prod-b", "default"}},
+		{selector: "staging-*", expectedErr: `no contexts match "staging-*"`},
+		{selector: "dev,staging", expectedErr: `context "staging": context not found`},
+		{selector: "prod-[", expectedErr: `invalid context pattern "prod-["`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.selector, func(t *testing.T) {
+			assert.Check(t, isFanOutContext(tc.selector))
+			names, err := matchContexts(s, tc.selector)
+			if tc.expectedErr != "" {
+				assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, is.DeepEqual(names, tc.expected))
+		})
+	}
+}
+
+func TestWriteFanOutOutput(t *testing.T) {
+	tests := []struct {
+		doc      string
+		mode     fanOutMode
+		results  []fanOutResult
+		expected string
+	}{
+		{
+			doc:  "table",
+			mode: fanOutTable,
+			results: []fanOutResult{
+				{
+					context: "prod-a",
+					stdout:  []byte("DRIVER    VOLUME NAME\nlocal     data\nlocal     logs\n"),
+				},
+				{context: "prod-b", stdout: nil},
+				{
+					context: "prod-c",
+					stdout:  []byte("DRIVER    VOLUME NAME\nlocal     a-much-longer-name\n"),
+				},
+			},
+			expected: `CONTEXT   DRIVER    VOLUME NAME
+prod-a    local     data
+prod-a    local     logs
+prod-c    local     a-much-longer-name
+`,
+		},
+		{
+			doc:  "table with different column widths",
+			mode: fanOutTable,
+			results: []fanOutResult{
+				{
+					context: "prod-a",
+					stdout:  []byte("CONTAINER ID   IMAGE     NAMES\nabc123         nginx     web\n"),
+				},
+				{
+					context: "prod-b",
+					stdout:  []byte("CONTAINER ID   IMAGE                 NAMES\ndef456         postgres:16-alpine    db\n"),
+				},
+			},
+			expected: `CONTEXT   CONTAINER ID   IMAGE                NAMES
+prod-a    abc123         nginx                web
+prod-b    def456         postgres:16-alpine   db
+`,
+		},
+		{
+			doc:  "table with different columns",
+			mode: fanOutTable,
+			results: []fanOutResult{
+				{context: "prod-a", stdout: []byte("DRIVER    VOLUME NAME\nlocal     data\n")},
+				{context: "prod-b", stdout: []byte("VOLUME NAME\nlogs\n")},
+			},
+			expected: "DRIVER    VOLUME NAME\nlocal     data\nVOLUME NAME\nlogs\n",
+		},
+		{
+			doc:  "table with empty columns",
+			mode: fanOutTable,
+			results: []fanOutResult{
+				{
+					context: "prod-a",
+					stdout:  []byte("NETWORK ID     NAME      DRIVER\nabc123         bridge    \n"),
+				},
+			},
+			expected: "CONTEXT   NETWORK ID   NAME      DRIVER\nprod-a    abc123       bridge    \n",
+		},
+		{
+			doc:  "json",
+			mode: fanOutJSON,
+			results: []fanOutResult{
+				{context: "prod-a", stdout: []byte(`{"Driver":"local","Name":"data"}` + "\n")},
+				{context: "prod-b", stdout: []byte("{}\n")},
+			},
+			expected: `{"Context":"prod-a","Driver":"local","Name":"data"}
+{"Context":"prod-b"}
+`,
+		},
+		{
+			doc:  "other",
+			mode: fanOutRaw,
+			results: []fanOutResult{
+				{context: "prod-a", stdout: []byte("data\n")},
+				{context: "prod-b", stdout: []byte("logs\n")},
+			},
+			expected: "data\nlogs\n",
+		},
+		{
+			doc:  "other with upper-case output",
+			mode: fanOutRaw,
+			results: []fanOutResult{
+				{context: "prod-a", stdout: []byte("0B\n12MB\n")},
+				{context: "prod-b", stdout: []byte("1GB\n")},
+			},
+			expected: "0B\n12MB\n1GB\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			var out bytes.Buffer
+			writeFanOutOutput(&out, tc.mode, tc.results)
+			assert.Check(t, is.Equal(out.String(), tc.expected))
+		})
+	}
+}
+
+func TestOutputMode(t *testing.T) {
+	tests := []struct {
+		format   string
+		quiet    bool
+		expected fanOutMode
+	}{
+		{format: "", expected: fanOutTable},
+		{format: "table", expected: fanOutTable},
+		{format: "table {{.ID}}\t{{.Size}}", expected: fanOutTable},
+		{format: "json", expected: fanOutJSON},
+		{format: "ndjson", expected: fanOutJSON},
+		{format: "{{.Size}}", expected: fanOutRaw},
+		{format: "{{json .}}", expected: fanOutRaw},
+		{format: "", quiet: true, expected: fanOutRaw},
+	}
+	for _, tc := range tests {
+		assert.Check(t, is.Equal(outputMode(tc.format, tc.quiet), tc.expected), "format: %q, quiet: %v", tc.format, tc.quiet)
+	}
+}
@@ -0,0 +1,105 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+// pickerFeature is the name of the feature in the CLI's config file that
+// enables the interactive picker for commands that are invoked without
+// their first argument.
+const pickerFeature = "interactive-picker"
+
+// setArgumentPicker wraps the Args validation of commands that provide
+// completion for their arguments. If the validation fails because the first
+// argument is missing, the user is prompted to pick the argument from the
+// completion candidates, after which the command continues with the picked
+// argument.
+//
+// The picker is only used if it's enabled through the "interactive-picker"
+// feature in the CLI's config file, and if both stdin and stdout are a
+// terminal.
+func setArgumentPicker(dockerCLI command.Cli) func(*cobra.Command) {
+	return func(ccmd *cobra.Command) {
+		if ccmd.Args == nil || ccmd.ValidArgsFunction == nil || ccmd.RunE == nil {
+			return
+		}
+
+		// picked is the argument that was picked during Args validation,
+		// which is prepended to the arguments passed to RunE.
+		var picked string
+
+		cmdArgs, runE := ccmd.Args, ccmd.RunE
+		ccmd.Args = func(cmd *cobra.Command, args []string) error {
+			picked = ""
+			err := cmdArgs(cmd, args)
+			if err == nil || !pickerEnabled(dockerCLI) {
+				return err
+			}
+			// Only pick the first argument if that would make the arguments
+			// valid; picking doesn't help for other validation errors, such
+			// as a missing COMMAND for "docker exec".
+			if cmdArgs(cmd, append([]string{"_"}, args...)) != nil {
+				return err
+			}
+			candidates := pickCandidates(cmd)
+			if len(candidates) == 0 || (len(args) > 0 && slices.Contains(candidates, args[0])) {
+				return err
+			}
+			msg := fmt.Sprintf("Select %s for %q:", firstArgName(cmd), cmd.CommandPath())
+			picked, err = prompt.Pick(cmd.Context(), dockerCLI.In(), dockerCLI.Out(), msg, candidates)
+			return err
+		}
+		ccmd.RunE = func(cmd *cobra.Command, args []string) error {
+			if picked != "" {
+				args = append([]string{picked}, args...)
+			}
+			return runE(cmd, args)
+		}
+	}
+}
+
+func pickerEnabled(dockerCLI command.Cli) bool {
+	if !dockerCLI.In().IsTerminal() || !dockerCLI.Out().IsTerminal() {
+		return false
+	}
+	enabled, _ := strconv.ParseBool(dockerCLI.ConfigFile().Features[pickerFeature])
+	return enabled
+}
+
+// firstArgName returns the name of the first argument in the command's usage,
+// for example, "container" for "logs [OPTIONS] CONTAINER".
+func firstArgName(cmd *cobra.Command) string {
+	for _, f := range strings.Fields(cmd.Use)[1:] {
+		if !strings.HasPrefix(f, "[") {
+			return strings.ToLower(strings.TrimSuffix(f, "..."))
+		}
+	}
+	return "an argument"
+}
+
+// pickCandidates returns the completion candidates for the first argument
+// of the command, without their descriptions.
+func pickCandidates(cmd *cobra.Command) []string {
+	completions, directive := cmd.ValidArgsFunction(cmd, nil, "")
+	if directive&cobra.ShellCompDirectiveError != 0 {
+		return nil
+	}
+	candidates := make([]string, 0, len(completions))
+	for _, c := range completions {
+		c, _, _ = strings.Cut(c, "\t")
+		if c != "" {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates
+}
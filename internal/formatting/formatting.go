@@ -0,0 +1,52 @@
+// Package formatting provides helpers that are shared by the template
+// functions and the structured output formats of the CLI.
+package formatting
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// timeLayouts are the layouts accepted when parsing a string as a time. In
+// addition to RFC 3339 (as used by the API), this includes the format of
+// [time.Time.String], which is used by formatters (such as "CreatedAt").
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+}
+
+// ParseTime parses a time in one of the [timeLayouts].
+func ParseTime(value string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %q", value)
+}
+
+// YAMLValue converts [json.Number] values in v, which would be written as
+// strings, to integers or floats. It's used to write values that are decoded
+// with [json.Decoder.UseNumber] as YAML.
+func YAMLValue(v any) any {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+		return val.String()
+	case map[string]any:
+		for k, child := range val {
+			val[k] = YAMLValue(child)
+		}
+	case []any:
+		for i, child := range val {
+			val[i] = YAMLValue(child)
+		}
+	}
+	return v
+}
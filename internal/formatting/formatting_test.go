@@ -0,0 +1,35 @@
+package formatting
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestParseTime(t *testing.T) {
+	expected := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, value := range []string{"2024-01-02T03:04:05Z", "2024-01-02 03:04:05 +0000 UTC"} {
+		actual, err := ParseTime(value)
+		assert.NilError(t, err)
+		assert.Check(t, actual.Equal(expected), value)
+	}
+	_, err := ParseTime("yesterday")
+	assert.Check(t, is.Error(err, `invalid time: "yesterday"`))
+}
+
+func TestYAMLValue(t *testing.T) {
+	actual := YAMLValue(map[string]any{
+		"Size":  json.Number("42"),
+		"Ratio": json.Number("0.5"),
+		"Ports": []any{json.Number("80"), "443"},
+	})
+	expected := map[string]any{
+		"Size":  int64(42),
+		"Ratio": 0.5,
+		"Ports": []any{int64(80), "443"},
+	}
+	assert.Check(t, is.DeepEqual(actual, expected))
+}
@@ -0,0 +1,153 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package prompt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/tui"
+)
+
+// maxPickItems is the maximum number of candidates that is shown by [Pick].
+const maxPickItems = 10
+
+// Pick requests the user to pick one of the candidates.
+//
+// It displays the provided message, followed by a numbered list of the
+// candidates. The user can either enter the number of a candidate to pick
+// it, or enter a pattern to filter the list using fuzzy matching. Entering
+// an empty line picks the first candidate in the list.
+//
+// It returns an empty string ("") with an [ErrTerminated] if the user
+// terminates the CLI with SIGINT or SIGTERM while the prompt is active,
+// or if the input is closed.
+//
+// Input is read one line per prompt, and without reading ahead, so that
+// input following the answer is left for the command that runs after
+// the prompt. A read that's in progress when the prompt is terminated
+// can't be interrupted; its line is used by the next prompt for the same
+// input, instead of being lost.
+func Pick(ctx context.Context, in io.Reader, out *streams.Out, message string, candidates []string) (string, error) {
+	o := tui.NewOutput(out)
+	var pattern string
+	for {
+		matches := tui.Fuzzy(pattern, candidates)
+		if len(matches) == 0 {
+			o.PrintlnWithColor(tui.ColorWarning, fmt.Sprintf("No matches for %q", pattern))
+			if pattern == "" {
+				return "", ErrTerminated
+			}
+			pattern = ""
+			continue
+		}
+
+		shown := matches
+		if len(shown) > maxPickItems {
+			shown = shown[:maxPickItems]
+		}
+		_, _ = fmt.Fprintln(out, message)
+		for i, m := range shown {
+			_, _ = fmt.Fprintf(out, "  %2d) %s\n", i+1, m.Highlight(o))
+		}
+		if n := len(matches) - len(shown); n > 0 {
+			o.PrintlnWithColor(tui.ColorTertiary, fmt.Sprintf("  ... %d more, type to filter", n))
+		}
+		_, _ = fmt.Fprint(out, "Type to filter, or enter a number to select [1]: ")
+
+		var line string
+		result := readLineAsync(in)
+		select {
+		case <-ctx.Done():
+			_, _ = out.Write([]byte("\n"))
+			return "", ErrTerminated
+		case r := <-result:
+			pending.done(result)
+			if !r.ok {
+				_, _ = out.Write([]byte("\n"))
+				return "", ErrTerminated
+			}
+			line = r.line
+		}
+
+		if line == "" {
+			return shown[0].Value, nil
+		}
+		if n, err := strconv.Atoi(line); err == nil && n >= 1 && n <= len(shown) {
+			return shown[n-1].Value, nil
+		}
+		pattern = line
+	}
+}
+
+// lineResult is the result of [readLine].
+type lineResult struct {
+	line string
+	ok   bool
+}
+
+// pending is the read that's in progress for an input, if any.
+var pending pendingRead
+
+// pendingRead tracks a read of a line that's in progress, so that a read
+// that outlives the prompt it was started for is reused by the next prompt,
+// instead of competing with a new read for the input.
+type pendingRead struct {
+	mu     sync.Mutex
+	in     io.Reader
+	result chan lineResult
+}
+
+// readLineAsync reads a line from in in the background. It returns the
+// result of the read that's in progress for in, if any.
+func readLineAsync(in io.Reader) chan lineResult {
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+	if pending.result != nil && reflect.TypeOf(in).Comparable() && pending.in == in {
+		return pending.result
+	}
+	result := make(chan lineResult, 1)
+	go func() {
+		line, ok := readLine(in)
+		result <- lineResult{line: line, ok: ok}
+	}()
+	pending.in, pending.result = in, result
+	return result
+}
+
+// done marks the read that returned to result as completed.
+func (p *pendingRead) done(result chan lineResult) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.result == result {
+		p.in, p.result = nil, nil
+	}
+}
+
+// readLine reads a single line from in, one byte at a time, and returns
+// it with surrounding whitespace trimmed. Unlike [bufio.Scanner], it does
+// not consume any input beyond the line's newline. It returns false if
+// the input was closed before a line was read.
+func readLine(in io.Reader) (string, bool) {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := in.Read(b)
+		if n > 0 {
+			if b[0] == '\n' {
+				return strings.TrimSpace(string(line)), true
+			}
+			line = append(line, b[0])
+		}
+		if err != nil {
+			return strings.TrimSpace(string(line)), len(line) > 0
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package prompt_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/prompt"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestPick(t *testing.T) {
+	candidates := []string{"web-frontend", "api", "api-worker", "db", "redis-cache"}
+
+	tests := []struct {
+		doc      string
+		input    string
+		expected string
+	}{
+		{
+			doc:      "default",
+			input:    "\n",
+			expected: "web-frontend",
+		},
+		{
+			doc:      "number",
+			input:    "3\n",
+			expected: "api-worker",
+		},
+		{
+			doc:      "filter",
+			input:    "rc\n\n",
+			expected: "redis-cache",
+		},
+		{
+			doc:      "filter prefers consecutive characters",
+			input:    "api\n2\n",
+			expected: "api-worker",
+		},
+		{
+			doc:      "filter without matches",
+			input:    "nosuchcandidate\n2\n",
+			expected: "api",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			picked, err := prompt.Pick(context.Background(), strings.NewReader(tc.input), streams.NewOut(out), "Select container:", candidates)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(picked, tc.expected))
+		})
+	}
+}
+
+func TestPickOutput(t *testing.T) {
+	out := new(bytes.Buffer)
+	picked, err := prompt.Pick(context.Background(), strings.NewReader("api\n\n"), streams.NewOut(out), "Select container:", []string{"web-frontend", "api-worker", "api"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(picked, "api"))
+
+	const expected = `Select container:
+   1) web-frontend
+   2) api-worker
+   3) api
+Type to filter, or enter a number to select [1]: Select container:
+   1) api
+   2) api-worker
+Type to filter, or enter a number to select [1]: `
+	assert.Check(t, is.Equal(out.String(), expected))
+}
+
+func TestPickLeavesRemainingInput(t *testing.T) {
+	in := strings.NewReader("api\n2\nremaining input")
+	picked, err := prompt.Pick(context.Background(), in, streams.NewOut(io.Discard), "Select container:", []string{"web-frontend", "api", "api-worker"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(picked, "api-worker"))
+
+	remaining, err := io.ReadAll(in)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(remaining), "remaining input"))
+}
+
+func TestPickTerminated(t *testing.T) {
+	t.Run("closed input", func(t *testing.T) {
+		_, err := prompt.Pick(context.Background(), strings.NewReader(""), streams.NewOut(io.Discard), "Select container:", []string{"web"})
+		assert.Check(t, is.ErrorIs(err, prompt.ErrTerminated))
+	})
+
+	t.Run("cancelling the context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		reader, _ := io.Pipe()
+		_, err := prompt.Pick(ctx, reader, streams.NewOut(io.Discard), "Select container:", []string{"web"})
+		assert.Check(t, is.ErrorIs(err, prompt.ErrTerminated))
+	})
+	t.Run("next prompt after cancelling the context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		reader, writer := io.Pipe()
+		_, err := prompt.Pick(ctx, reader, streams.NewOut(io.Discard), "Select container:", []string{"web", "api"})
+		assert.Check(t, is.ErrorIs(err, prompt.ErrTerminated))
+
+		// The read that was started by the terminated prompt is used by
+		// the next prompt, instead of consuming part of its input.
+		go func() { _, _ = writer.Write([]byte("2\n")) }()
+		picked, err := prompt.Pick(context.Background(), reader, streams.NewOut(io.Discard), "Select container:", []string{"web", "api"})
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(picked, "api"))
+	})
+}
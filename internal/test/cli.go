@@ -30,6 +30,7 @@ type FakeCli struct {
 	registryClient registryclient.RegistryClient
 	contextStore   store.Store
 	currentContext string
+	contextSource  string
 	dockerEndpoint docker.Endpoint
 }
 
@@ -85,6 +86,11 @@ func (c *FakeCli) SetCurrentContext(name string) {
 	c.currentContext = name
 }
 
+// SetCurrentContextSource sets the "fake" source of the current context
+func (c *FakeCli) SetCurrentContextSource(source string) {
+	c.contextSource = source
+}
+
 // SetDockerEndpoint sets the "fake" docker endpoint
 func (c *FakeCli) SetDockerEndpoint(ep docker.Endpoint) {
 	c.dockerEndpoint = ep
@@ -130,6 +136,11 @@ func (c *FakeCli) CurrentContext() string {
 	return c.currentContext
 }
 
+// CurrentContextSource returns the source of the cli context
+func (c *FakeCli) CurrentContextSource() string {
+	return c.contextSource
+}
+
 // DockerEndpoint returns the current DockerEndpoint
 func (c *FakeCli) DockerEndpoint() docker.Endpoint {
 	return c.dockerEndpoint
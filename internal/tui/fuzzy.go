@@ -0,0 +1,104 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package tui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// FuzzyMatch is a candidate matching a fuzzy pattern.
+type FuzzyMatch struct {
+	// Value is the candidate that matched the pattern.
+	Value string
+
+	// Positions are the byte offsets of the characters in Value that
+	// matched the pattern.
+	Positions []int
+
+	score int
+}
+
+// Highlight returns the value, with the characters that matched the pattern
+// highlighted using the given color.
+func (m FuzzyMatch) Highlight(o Output) string {
+	if len(m.Positions) == 0 {
+		return m.Value
+	}
+	clr := o.Color(ColorPrimary)
+	var sb strings.Builder
+	next := 0
+	for i, r := range m.Value {
+		if next < len(m.Positions) && m.Positions[next] == i {
+			sb.WriteString(clr.Apply(string(r)))
+			next++
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// Fuzzy returns the candidates matching the pattern, best matches first.
+//
+// A candidate matches if all characters of the pattern occur in the
+// candidate in the same order, ignoring case. Candidates in which the
+// characters of the pattern are consecutive, or in which they occur at the
+// start of a word, are considered better matches. If the pattern is empty,
+// all candidates are returned in their original order.
+func Fuzzy(pattern string, candidates []string) []FuzzyMatch {
+	matches := make([]FuzzyMatch, 0, len(candidates))
+	for _, c := range candidates {
+		if m, ok := fuzzyMatch(pattern, c); ok {
+			matches = append(matches, m)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	return matches
+}
+
+func fuzzyMatch(pattern, candidate string) (FuzzyMatch, bool) {
+	m := FuzzyMatch{Value: candidate}
+	p := []rune(strings.ToLower(pattern))
+	if len(p) == 0 {
+		return m, true
+	}
+
+	prev, prevPos, last := rune(0), -1, -2
+	for i, r := range candidate {
+		if len(m.Positions) == len(p) {
+			break
+		}
+		if unicode.ToLower(r) == p[len(m.Positions)] {
+			switch {
+			case last == prevPos:
+				m.score += 5 // consecutive characters
+			case i == 0 || isWordSeparator(prev):
+				m.score += 3 // start of a word
+			}
+			m.score++
+			m.Positions = append(m.Positions, i)
+			last = i
+		}
+		prev, prevPos = r, i
+	}
+	if len(m.Positions) < len(p) {
+		return FuzzyMatch{}, false
+	}
+	// Prefer shorter candidates if the score is otherwise equal.
+	m.score = m.score*100 - len(candidate)
+	return m, true
+}
+
+func isWordSeparator(r rune) bool {
+	switch r {
+	case '-', '_', '.', '/', ':', '@', ' ':
+		return true
+	default:
+		return false
+	}
+}
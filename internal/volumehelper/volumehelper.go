@@ -0,0 +1,101 @@
+// Package volumehelper creates helper containers that have a volume mounted,
+// to copy the content of the volume with the archive copy APIs.
+package volumehelper
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/cli/internal/jsonstream"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/mount"
+	"github.com/moby/moby/client"
+	"github.com/opencontainers/go-digest"
+)
+
+// MountPath is the path at which the volume is mounted in the helper
+// container. Archives that are copied from the helper container contain
+// the content of the volume in a directory with the same name, without
+// the leading slash, and archives that are copied to "/" are extracted
+// into the volume if their entries are in that directory.
+const MountPath = "/volume"
+
+// Create creates a container that has the volume mounted at [MountPath].
+// The container is created from an empty image, so that it doesn't depend
+// on images that are available on the daemon, and is never started. The
+// returned function removes both the container and the image.
+func Create(ctx context.Context, apiClient client.APIClient, volumeName string, readOnly bool) (string, func(), error) {
+	var emptyTar bytes.Buffer
+	if err := tar.NewWriter(&emptyTar).Close(); err != nil {
+		return "", nil, err
+	}
+	res, err := apiClient.ImageImport(ctx, client.ImageImportSource{Source: &emptyTar, SourceName: "-"}, "", client.ImageImportOptions{})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create helper image: %w", err)
+	}
+	imageID, err := importedImageID(res)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create helper image: %w", err)
+	}
+	removeImage := func() {
+		_, _ = apiClient.ImageRemove(context.WithoutCancel(ctx), imageID, client.ImageRemoveOptions{})
+	}
+
+	ctr, err := apiClient.ContainerCreate(ctx, client.ContainerCreateOptions{
+		Config: &container.Config{
+			Image:           imageID,
+			Cmd:             []string{"none"},
+			NetworkDisabled: true,
+		},
+		HostConfig: &container.HostConfig{
+			Mounts: []mount.Mount{{
+				Type:          mount.TypeVolume,
+				Source:        volumeName,
+				Target:        MountPath,
+				ReadOnly:      readOnly,
+				VolumeOptions: &mount.VolumeOptions{NoCopy: true},
+			}},
+		},
+	})
+	if err != nil {
+		removeImage()
+		return "", nil, fmt.Errorf("failed to create helper container for volume %s: %w", volumeName, err)
+	}
+	return ctr.ID, func() {
+		_, _ = apiClient.ContainerRemove(context.WithoutCancel(ctx), ctr.ID, client.ContainerRemoveOptions{Force: true})
+		removeImage()
+	}, nil
+}
+
+// importedImageID returns the ID of the image from the response of the
+// image import API, which reports it as the status of the last message.
+func importedImageID(res io.ReadCloser) (string, error) {
+	defer func() { _ = res.Close() }()
+	var imageID string
+	dec := json.NewDecoder(res)
+	for {
+		var msg jsonstream.JSONMessage
+		if err := dec.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return "", err
+		}
+		if msg.Error != nil {
+			return "", msg.Error
+		}
+		if dgst, err := digest.Parse(strings.TrimSpace(msg.Status)); err == nil {
+			imageID = dgst.String()
+		}
+	}
+	if imageID == "" {
+		return "", errors.New("no image ID in response")
+	}
+	return imageID, nil
+}
@@ -13,20 +13,36 @@
 // ReadKVStrings reads a file of line terminated key=value pairs, and overrides any keys
 // present in the file with additional pairs specified in the override parameter
 func ReadKVStrings(files []string, override []string) ([]string, error) {
-	return readKVStrings(files, override, nil)
+	return readKVStrings(files, override, kvfile.FormatPlain, nil)
+}
+
+// ReadKVStringsWithFormat is like [ReadKVStrings], but parses files using the
+// given format.
+func ReadKVStringsWithFormat(files []string, override []string, format kvfile.Format) ([]string, error) {
+	return readKVStrings(files, override, format, nil)
 }
 
 // ReadKVEnvStrings reads a file of line terminated key=value pairs, and overrides any keys
 // present in the file with additional pairs specified in the override parameter.
 // If a key has no value, it will get the value from the environment.
 func ReadKVEnvStrings(files []string, override []string) ([]string, error) {
-	return readKVStrings(files, override, os.LookupEnv)
+	return readKVStrings(files, override, kvfile.FormatPlain, os.LookupEnv)
 }
 
-func readKVStrings(files []string, override []string, emptyFn func(string) (string, bool)) ([]string, error) {
+// ReadKVEnvStringsWithFormat is like [ReadKVEnvStrings], but parses files using
+// the given format. With the [kvfile.FormatDotenv] format, variables in values
+// are interpolated from variables defined in the file, and the environment.
+func ReadKVEnvStringsWithFormat(files []string, override []string, format kvfile.Format) ([]string, error) {
+	return readKVStrings(files, override, format, os.LookupEnv)
+}
+
+func readKVStrings(files []string, override []string, format kvfile.Format, emptyFn func(string) (string, bool)) ([]string, error) {
+	if err := kvfile.ValidateFormat(format); err != nil {
+		return nil, err
+	}
 	var variables []string
 	for _, ef := range files {
-		parsedVars, err := kvfile.Parse(ef, emptyFn)
+		parsedVars, err := kvfile.ParseWithFormat(ef, format, emptyFn)
 		if err != nil {
 			return nil, err
 		}
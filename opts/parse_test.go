@@ -3,6 +3,7 @@
 import (
 	"testing"
 
+	"github.com/docker/cli/pkg/kvfile"
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/fs"
 )
@@ -68,3 +69,21 @@ func TestReadKVEnvStrings(t *testing.T) {
 		})
 	}
 }
+
+func TestReadKVEnvStringsWithFormat(t *testing.T) {
+	envFile := fs.NewFile(t, t.Name(), fs.WithContent(`export GREETING="hello ${FROM_ENV}"
+QUOTED='single quoted'
+`))
+	defer envFile.Remove()
+	t.Setenv("FROM_ENV", "from-env")
+
+	envs, err := ReadKVEnvStringsWithFormat([]string{envFile.Path()}, []string{"EXTRA=extra"}, kvfile.FormatDotenv)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"GREETING=hello from-env", "QUOTED=single quoted", "EXTRA=extra"}, envs)
+
+	_, err = ReadKVEnvStringsWithFormat([]string{envFile.Path()}, nil, kvfile.FormatPlain)
+	assert.ErrorContains(t, err, "contains whitespaces")
+
+	_, err = ReadKVEnvStringsWithFormat(nil, nil, "unknown")
+	assert.Error(t, err, "invalid format 'unknown': must be one of 'plain' or 'dotenv'")
+}
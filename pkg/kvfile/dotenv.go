@@ -0,0 +1,299 @@
+package kvfile
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/docker/cli/cli/compose/template"
+)
+
+// Format is the syntax used by a key/value file.
+type Format string
+
+const (
+	// FormatPlain is the default format, as described in the package
+	// documentation.
+	FormatPlain Format = "plain"
+
+	// FormatDotenv is a format compatible with ".env" files as used by
+	// other tools. Refer to [ParseDotenvFromReader] for details.
+	FormatDotenv Format = "dotenv"
+)
+
+// ValidateFormat validates the given format. An empty format is accepted,
+// and equivalent to [FormatPlain].
+func ValidateFormat(format Format) error {
+	switch format {
+	case "", FormatPlain, FormatDotenv:
+		return nil
+	default:
+		return fmt.Errorf("invalid format '%s': must be one of '%s' or '%s'", format, FormatPlain, FormatDotenv)
+	}
+}
+
+// ParseWithFormat parses a key/value file using the given format. An empty
+// format is equivalent to [FormatPlain]. Refer to [Parse] and [ParseDotenv]
+// for details.
+func ParseWithFormat(filename string, format Format, lookupFn func(key string) (value string, found bool)) ([]string, error) {
+	switch format {
+	case "", FormatPlain:
+		return Parse(filename, lookupFn)
+	case FormatDotenv:
+		return ParseDotenv(filename, lookupFn)
+	default:
+		return []string{}, ValidateFormat(format)
+	}
+}
+
+// ParseDotenv parses a dotenv-compatible file. Refer to [ParseDotenvFromReader]
+// for details on the format.
+func ParseDotenv(filename string, lookupFn func(key string) (value string, found bool)) ([]string, error) {
+	fh, err := os.Open(filename)
+	if err != nil {
+		return []string{}, err
+	}
+	out, err := parseDotenv(fh, lookupFn)
+	_ = fh.Close()
+	if err != nil {
+		return []string{}, fmt.Errorf("invalid env file (%s): %v", filename, err)
+	}
+	return out, nil
+}
+
+// ParseDotenvFromReader parses a dotenv-compatible file. In addition to the
+// syntax described in the package documentation, it supports:
+//
+//   - An optional "export" prefix ("export KEY=VALUE").
+//   - Whitespace around the "=" delimiter, and around unquoted values.
+//   - Comments after unquoted values, separated by whitespace ("KEY=VALUE # comment").
+//   - Single-quoted values, which are used literally, and may span multiple lines.
+//   - Double-quoted values, which may span multiple lines, and support the
+//     escape sequences "\n", "\r", "\t", "\\", "\"", and "\$".
+//   - Interpolation of variables ("$VAR", "${VAR}", "${VAR:-default}", etc.)
+//     in unquoted and double-quoted values, using the same syntax as compose
+//     files. Variables are looked up in the variables defined earlier in the
+//     file, then using lookupFn. Undefined variables are substituted with an
+//     empty string, and "$$" produces a literal "$".
+//
+// Keys without a value are handled as described in the package documentation.
+// Errors include the line number at which the error occurred.
+func ParseDotenvFromReader(r io.Reader, lookupFn func(key string) (value string, found bool)) ([]string, error) {
+	return parseDotenv(r, lookupFn)
+}
+
+func parseDotenv(r io.Reader, lookupFn func(string) (string, bool)) ([]string, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return []string{}, err
+	}
+
+	p := dotenvParser{
+		lines:    lines,
+		vars:     map[string]string{},
+		lookupFn: lookupFn,
+	}
+	out := []string{}
+	for p.next < len(p.lines) {
+		kv, ok, err := p.parseEntry()
+		if err != nil {
+			return []string{}, err
+		}
+		if ok {
+			out = append(out, kv)
+		}
+	}
+	return out, nil
+}
+
+// readLines reads all lines from r, validating that they are valid UTF-8,
+// and removing the UTF-8 BOM.
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	utf8bom := []byte{0xEF, 0xBB, 0xBF}
+	for currentLine := 1; scanner.Scan(); currentLine++ {
+		scannedBytes := scanner.Bytes()
+		if !utf8.Valid(scannedBytes) {
+			return nil, fmt.Errorf("line %d: invalid utf8 bytes: %v", currentLine, scannedBytes)
+		}
+		if currentLine == 1 {
+			scannedBytes = bytes.TrimPrefix(scannedBytes, utf8bom)
+		}
+		lines = append(lines, string(scannedBytes))
+	}
+	return lines, scanner.Err()
+}
+
+type dotenvParser struct {
+	lines []string
+	// next is the index of the next line to parse.
+	next     int
+	vars     map[string]string
+	lookupFn func(string) (string, bool)
+}
+
+// parseEntry parses the entry starting at the next line. It returns false
+// if the line is empty or a comment, or if the entry has no value, and no
+// value was found through lookupFn.
+func (p *dotenvParser) parseEntry() (string, bool, error) {
+	lineNum := p.next + 1
+	line := strings.TrimLeftFunc(p.lines[p.next], unicode.IsSpace)
+	p.next++
+
+	if len(line) == 0 || line[0] == '#' {
+		return "", false, nil
+	}
+	if rest, ok := strings.CutPrefix(line, "export"); ok && len(rest) > 0 && strings.ContainsRune(whiteSpaces, rune(rest[0])) {
+		line = strings.TrimLeft(rest, whiteSpaces)
+	}
+
+	key, value, hasValue := strings.Cut(line, "=")
+	key = strings.TrimRight(key, whiteSpaces)
+	if !hasValue {
+		key, _, _ = strings.Cut(key, " #")
+		key = strings.TrimRight(key, whiteSpaces)
+	}
+	if len(key) == 0 {
+		return "", false, fmt.Errorf("line %d: no variable name", lineNum)
+	}
+	if strings.ContainsAny(key, whiteSpaces) {
+		return "", false, fmt.Errorf("line %d: variable '%s' contains whitespaces", lineNum, key)
+	}
+
+	if !hasValue {
+		if p.lookupFn != nil {
+			if v, found := p.lookupFn(key); found {
+				p.vars[key] = v
+				return key + "=" + v, true, nil
+			}
+		}
+		return "", false, nil
+	}
+
+	var err error
+	switch trimmed := strings.TrimLeft(value, whiteSpaces); {
+	case strings.HasPrefix(trimmed, "'"):
+		value, err = p.readQuoted(lineNum, key, trimmed)
+	case strings.HasPrefix(trimmed, `"`):
+		value, err = p.readQuoted(lineNum, key, trimmed)
+		if err == nil {
+			value, err = p.interpolate(lineNum, key, unescapeDoubleQuoted(value))
+		}
+	default:
+		value, err = p.interpolate(lineNum, key, strings.TrimLeft(stripComment(value), whiteSpaces))
+	}
+	if err != nil {
+		return "", false, err
+	}
+	p.vars[key] = value
+	return key + "=" + value, true, nil
+}
+
+// stripComment removes a comment from an unquoted value, and trailing
+// whitespace. Comments must be separated from the value by whitespace.
+func stripComment(value string) string {
+	for i := 1; i < len(value); i++ {
+		if value[i] == '#' && strings.ContainsRune(whiteSpaces, rune(value[i-1])) {
+			value = value[:i]
+			break
+		}
+	}
+	return strings.TrimRight(value, whiteSpaces)
+}
+
+// readQuoted reads a quoted value, which starts with the quote character,
+// and may continue on the following lines. It returns the value without
+// quotes; escape sequences are not processed.
+func (p *dotenvParser) readQuoted(lineNum int, key, value string) (string, error) {
+	quote := value[0]
+	body := value[1:]
+	for {
+		if end := findClosingQuote(body, quote); end >= 0 {
+			rest := strings.TrimLeft(body[end+1:], whiteSpaces)
+			if rest != "" && rest[0] != '#' {
+				return "", fmt.Errorf("line %d: unexpected characters after quoted value for '%s': %s", p.next, key, rest)
+			}
+			return body[:end], nil
+		}
+		if p.next >= len(p.lines) {
+			return "", fmt.Errorf("line %d: unterminated quoted value for '%s'", lineNum, key)
+		}
+		body += "\n" + p.lines[p.next]
+		p.next++
+	}
+}
+
+// findClosingQuote returns the index of the closing quote in s, or -1 if s
+// does not contain a closing quote. Backslash-escaped quotes are skipped in
+// double-quoted values.
+func findClosingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quote == '"' {
+				i++
+			}
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeDoubleQuoted processes escape sequences in a double-quoted value.
+// Escaped dollar-signs are converted to "$$", so that they are preserved
+// when interpolating the value. Unknown escape sequences are kept as-is.
+func unescapeDoubleQuoted(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case '\\', '"':
+			sb.WriteByte(s[i])
+		case '$':
+			sb.WriteString("$$")
+		default:
+			sb.WriteByte('\\')
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
+
+func (p *dotenvParser) interpolate(lineNum int, key, value string) (string, error) {
+	out, err := template.Substitute(value, func(name string) (string, bool) {
+		if v, ok := p.vars[name]; ok {
+			return v, true
+		}
+		if p.lookupFn != nil {
+			return p.lookupFn(name)
+		}
+		return "", false
+	})
+	if err != nil {
+		var invalidTemplate *template.InvalidTemplateError
+		if errors.As(err, &invalidTemplate) && invalidTemplate.Template != value {
+			// errors for required variables use the error-message as template.
+			return "", fmt.Errorf("line %d: variable '%s': %s", lineNum, key, invalidTemplate.Template)
+		}
+		return "", fmt.Errorf("line %d: invalid interpolation format for variable '%s': %s", lineNum, key, value)
+	}
+	return out, nil
+}
@@ -0,0 +1,127 @@
+package kvfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestParseDotenvFromReader(t *testing.T) {
+	content := `# comment
+PLAIN=value
+export EXPORTED=exported
+  SPACED = spaced value   # trailing comment
+HASH=value#not-a-comment
+EMPTY=
+EMPTY_COMMENT= # comment
+SINGLE='single $PLAIN \n "quoted"' # comment
+DOUBLE="double $PLAIN\t\"quoted\" \$PLAIN \\ \x"
+MULTI_SINGLE='line 1
+line 2'
+MULTI_DOUBLE="line 1
+  line 2\nline 3"
+INTERPOLATED=${PLAIN}-${FROM_ENV}-${UNDEFINED:-default}-$$
+FROM_ENV
+UNDEFINED
+export=not-a-prefix
+`
+	lookupFn := func(name string) (string, bool) {
+		if name == "FROM_ENV" {
+			return "env-value", true
+		}
+		return "", false
+	}
+
+	variables, err := ParseDotenvFromReader(strings.NewReader(content), lookupFn)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(variables, []string{
+		"PLAIN=value",
+		"EXPORTED=exported",
+		"SPACED=spaced value",
+		"HASH=value#not-a-comment",
+		"EMPTY=",
+		"EMPTY_COMMENT=",
+		`SINGLE=single $PLAIN \n "quoted"`,
+		"DOUBLE=double value\t\"quoted\" $PLAIN \\ \\x",
+		"MULTI_SINGLE=line 1\nline 2",
+		"MULTI_DOUBLE=line 1\n  line 2\nline 3",
+		"INTERPOLATED=value-env-value-default-$",
+		"FROM_ENV=env-value",
+		"export=not-a-prefix",
+	}))
+}
+
+func TestParseDotenvFromReaderErrors(t *testing.T) {
+	testCases := []struct {
+		doc         string
+		content     string
+		expectedErr string
+	}{
+		{
+			doc:         "no variable name",
+			content:     "FOO=bar\n=value",
+			expectedErr: "line 2: no variable name",
+		},
+		{
+			doc:         "whitespace in variable name",
+			content:     "FOO BAR=value",
+			expectedErr: "line 1: variable 'FOO BAR' contains whitespaces",
+		},
+		{
+			doc:         "unterminated single quote",
+			content:     "FOO=bar\nBAZ='value\nmore\n",
+			expectedErr: "line 2: unterminated quoted value for 'BAZ'",
+		},
+		{
+			doc:         "unterminated double quote",
+			content:     `FOO="value\"`,
+			expectedErr: "line 1: unterminated quoted value for 'FOO'",
+		},
+		{
+			doc:         "characters after quoted value",
+			content:     "FOO='multi\nline'trailing",
+			expectedErr: "line 2: unexpected characters after quoted value for 'FOO': trailing",
+		},
+		{
+			doc:         "invalid interpolation",
+			content:     "FOO=bar\nBAZ=${FOO",
+			expectedErr: "line 2: invalid interpolation format for variable 'BAZ': ${FOO",
+		},
+		{
+			doc:         "required variable",
+			content:     "FOO=${BAR:?must be set}",
+			expectedErr: "line 1: variable 'FOO': required variable BAR is missing a value: must be set",
+		},
+		{
+			doc:         "invalid utf8",
+			content:     "FOO=bar\nBAZ=\xff",
+			expectedErr: "line 2: invalid utf8 bytes",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			_, err := ParseDotenvFromReader(strings.NewReader(tc.content), nil)
+			assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+		})
+	}
+}
+
+func TestParseWithFormat(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), ".env")
+	err := os.WriteFile(fileName, []byte("export FOO='bar baz'\n"), 0o644)
+	assert.NilError(t, err)
+
+	_, err = ParseWithFormat(fileName, FormatPlain, nil)
+	assert.Check(t, is.ErrorContains(err, "variable 'export FOO' contains whitespaces"))
+
+	variables, err := ParseWithFormat(fileName, FormatDotenv, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(variables, []string{"FOO=bar baz"}))
+
+	_, err = ParseWithFormat(fileName, "yaml", nil)
+	assert.Check(t, is.Error(err, "invalid format 'yaml': must be one of 'plain' or 'dotenv'"))
+}
@@ -21,6 +21,10 @@
 // that the file format is line-delimited, neither key, nor value, can contain
 // newlines.
 //
+// A dotenv-compatible format ([FormatDotenv]), which supports quoting,
+// escaping, multi-line values, and interpolation, can be used through
+// [ParseDotenv] and [ParseWithFormat].
+//
 // # Key/Value pairs
 //
 // Key/Value pairs take the following format:
@@ -0,0 +1,298 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package templates
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/internal/formatting"
+	"github.com/docker/go-units"
+	"go.yaml.in/yaml/v3"
+)
+
+// now is used by time-related functions, and can be replaced in tests.
+var now = time.Now
+
+// defaultValue returns value if it's not empty, and def otherwise. Values are
+// considered empty if they are the zero-value for their type, or an empty
+// slice or map. It takes the value as last argument, so that it can be used
+// in a pipeline (for example, {{.Name | default "none"}}).
+func defaultValue(def any, value ...any) any {
+	if len(value) == 0 || isEmpty(value[0]) {
+		return def
+	}
+	return value[0]
+}
+
+func isEmpty(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() { //nolint:exhaustive // ignore: too many options to make exhaustive
+	case reflect.Array, reflect.Slice, reflect.Map, reflect.String:
+		return rv.Len() == 0
+	default:
+		return rv.IsZero()
+	}
+}
+
+// toTime converts v to a [time.Time]. It accepts a [time.Time], a string
+// in a format accepted by [formatting.ParseTime], or a Unix timestamp (in seconds).
+func toTime(v any) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case *time.Time:
+		if t == nil {
+			return time.Time{}, nil
+		}
+		return *t, nil
+	case string:
+		if ts, err := formatting.ParseTime(t); err == nil {
+			return ts, nil
+		}
+		if ts, err := strconv.ParseInt(t, 10, 64); err == nil {
+			return time.Unix(ts, 0), nil
+		}
+		return time.Time{}, fmt.Errorf("invalid time: %q", t)
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() { //nolint:exhaustive // ignore: too many options to make exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return time.Unix(rv.Int(), 0), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return time.Unix(int64(rv.Uint()), 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid time: expected time, string, or number, got %T", v)
+	}
+}
+
+// since returns the time elapsed since the given time in a human-readable
+// format (for example, "5 minutes").
+func since(v any) (string, error) {
+	t, err := toTime(v)
+	if err != nil {
+		return "", err
+	}
+	if t.IsZero() {
+		return "", nil
+	}
+	return units.HumanDuration(now().Sub(t)), nil
+}
+
+// humanTime formats the given time relative to the current time (for
+// example, "5 minutes ago").
+func humanTime(v any) (string, error) {
+	t, err := toTime(v)
+	if err != nil {
+		return "", err
+	}
+	if t.IsZero() {
+		return "", nil
+	}
+	if d := t.Sub(now()); d > 0 {
+		return "in " + units.HumanDuration(d), nil
+	}
+	return units.HumanDuration(now().Sub(t)) + " ago", nil
+}
+
+// humanSize formats a size in bytes in a human-readable format, using the
+// same format as the "SIZE" column of "docker image ls" (for example, "1.5MB").
+// It accepts numbers, and numeric strings.
+func humanSize(v any) (string, error) {
+	var size float64
+	switch rv := reflect.ValueOf(v); rv.Kind() { //nolint:exhaustive // ignore: too many options to make exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		size = rv.Float()
+	case reflect.String:
+		var err error
+		size, err = strconv.ParseFloat(rv.String(), 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid size: %q", rv.String())
+		}
+	default:
+		return "", fmt.Errorf("invalid size: expected number, got %T", v)
+	}
+	return units.HumanSizeWithPrecision(size, 3), nil
+}
+
+// toYAML formats v as YAML. Similar to the "json" function, fields are named
+// according to their JSON representation.
+func toYAML(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return "", err
+	}
+	out, err := yaml.Marshal(formatting.YAMLValue(generic))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// sortBy returns a copy of the given slice, sorted by the given field or map
+// key. Nested fields can be separated by dots (for example, "Config.Name").
+// If key is empty, elements are sorted by their own value. It takes the slice
+// as last argument, so that it can be used in a pipeline (for example,
+// {{range .Mounts | sortBy "Destination"}}).
+func sortBy(key string, list any) ([]any, error) {
+	if list == nil {
+		return nil, nil
+	}
+	rv := reflect.ValueOf(list)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("sortBy: expected slice, got %T", list)
+	}
+
+	type item struct {
+		elem any
+		key  reflect.Value
+	}
+	items := make([]item, rv.Len())
+	for i := range rv.Len() {
+		k, err := lookupField(rv.Index(i), key)
+		if err != nil {
+			return nil, fmt.Errorf("sortBy: %w", err)
+		}
+		items[i] = item{elem: rv.Index(i).Interface(), key: k}
+	}
+	slices.SortStableFunc(items, func(a, b item) int {
+		return compareValues(a.key, b.key)
+	})
+
+	out := make([]any, len(items))
+	for i, it := range items {
+		out[i] = it.elem
+	}
+	return out, nil
+}
+
+// lookupField returns the value of the field or map key with the given
+// (dot-separated) path in v. Missing map keys and nil pointers produce an
+// invalid [reflect.Value], which sorts before other values.
+func lookupField(v reflect.Value, path string) (reflect.Value, error) {
+	if path == "" {
+		return indirect(v), nil
+	}
+	for name := range strings.SplitSeq(path, ".") {
+		v = indirect(v)
+		switch v.Kind() { //nolint:exhaustive // ignore: too many options to make exhaustive
+		case reflect.Invalid:
+			return v, nil
+		case reflect.Struct:
+			f, ok := v.Type().FieldByName(name)
+			if !ok || !f.IsExported() {
+				return reflect.Value{}, fmt.Errorf("%s has no field %q", v.Type(), name)
+			}
+			v = v.FieldByIndex(f.Index)
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}, fmt.Errorf("%s has no string keys", v.Type())
+			}
+			v = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		default:
+			return reflect.Value{}, fmt.Errorf("cannot get %q from %s", name, v.Type())
+		}
+	}
+	return indirect(v), nil
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// compareValues compares numbers numerically, times chronologically, and
+// other values by their string representation.
+func compareValues(a, b reflect.Value) int {
+	switch {
+	case !a.IsValid() || !b.IsValid():
+		return cmp.Compare(boolToInt(a.IsValid()), boolToInt(b.IsValid()))
+	case a.CanInt() && b.CanInt():
+		return cmp.Compare(a.Int(), b.Int())
+	case a.CanUint() && b.CanUint():
+		return cmp.Compare(a.Uint(), b.Uint())
+	case a.CanFloat() && b.CanFloat():
+		return cmp.Compare(a.Float(), b.Float())
+	}
+	if ta, ok := a.Interface().(time.Time); ok {
+		if tb, ok := b.Interface().(time.Time); ok {
+			return ta.Compare(tb)
+		}
+	}
+	return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// dict creates a map from the given list of key/value pairs, for example, to
+// pass multiple values to a template (dict "Name" .Name "ID" .ID).
+func dict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("dict: expected an even number of arguments, got %d", len(pairs))
+	}
+	m := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		k, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict: expected string key, got %T", pairs[i])
+		}
+		m[k] = pairs[i+1]
+	}
+	return m, nil
+}
+
+// regexMatch reports whether s contains a match of the regular expression.
+func regexMatch(expr, s string) (bool, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(s), nil
+}
+
+// hasKey reports whether the map m contains the given key. It returns false
+// if m is not a map with string keys.
+func hasKey(m any, key string) bool {
+	rv := indirect(reflect.ValueOf(m))
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return false
+	}
+	return rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key())).IsValid()
+}
+
+// headerValue is used in place of formatting functions for table headers,
+// and returns the header as-is.
+func headerValue(v any) string {
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,253 @@
+package templates
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func execute(t *testing.T, format string, data any) (string, error) {
+	t.Helper()
+	tmpl, err := Parse(format)
+	assert.NilError(t, err)
+	var b bytes.Buffer
+	err = tmpl.Execute(&b, data)
+	return b.String(), err
+}
+
+func TestDefault(t *testing.T) {
+	tests := []struct {
+		doc      string
+		data     any
+		expected string
+	}{
+		{doc: "string", data: "value", expected: "value"},
+		{doc: "empty string", data: "", expected: "none"},
+		{doc: "nil", data: nil, expected: "none"},
+		{doc: "zero number", data: 0, expected: "none"},
+		{doc: "number", data: 42, expected: "42"},
+		{doc: "empty slice", data: []string{}, expected: "none"},
+		{doc: "slice", data: []string{"a"}, expected: "[a]"},
+		{doc: "empty map", data: map[string]string{}, expected: "none"},
+		{doc: "false", data: false, expected: "none"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			out, err := execute(t, `{{. | default "none"}}`, tc.data)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(out, tc.expected))
+		})
+	}
+}
+
+func TestSinceAndHumanTime(t *testing.T) {
+	fixedNow := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fixedNow }
+	t.Cleanup(func() { now = time.Now })
+
+	past := fixedNow.Add(-5 * time.Minute)
+	tests := []struct {
+		doc       string
+		data      any
+		since     string
+		humanTime string
+	}{
+		{doc: "time", data: past, since: "5 minutes", humanTime: "5 minutes ago"},
+		{doc: "time pointer", data: &past, since: "5 minutes", humanTime: "5 minutes ago"},
+		{doc: "RFC 3339", data: "2024-01-01T09:00:00Z", since: "3 hours", humanTime: "3 hours ago"},
+		{doc: "time.String", data: past.String(), since: "5 minutes", humanTime: "5 minutes ago"},
+		{doc: "unix timestamp", data: past.Unix(), since: "5 minutes", humanTime: "5 minutes ago"},
+		{doc: "unix timestamp string", data: "1704110100", since: "5 minutes", humanTime: "5 minutes ago"},
+		{doc: "future", data: fixedNow.Add(2 * time.Hour), since: "Less than a second", humanTime: "in 2 hours"},
+		{doc: "zero", data: time.Time{}, since: "", humanTime: ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			out, err := execute(t, `{{since .}}`, tc.data)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(out, tc.since))
+
+			out, err = execute(t, `{{humanTime .}}`, tc.data)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(out, tc.humanTime))
+		})
+	}
+
+	_, err := execute(t, `{{since .}}`, "yesterday")
+	assert.Check(t, is.ErrorContains(err, `invalid time: "yesterday"`))
+	_, err = execute(t, `{{humanTime .}}`, []string{})
+	assert.Check(t, is.ErrorContains(err, "invalid time: expected time, string, or number, got []string"))
+}
+
+func TestHumanSize(t *testing.T) {
+	tests := []struct {
+		data     any
+		expected string
+	}{
+		{data: 0, expected: "0B"},
+		{data: int64(1000), expected: "1kB"},
+		{data: uint64(1500000), expected: "1.5MB"},
+		{data: 2.5e9, expected: "2.5GB"},
+		{data: "123456", expected: "123kB"},
+	}
+	for _, tc := range tests {
+		out, err := execute(t, `{{humanSize .}}`, tc.data)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(out, tc.expected))
+	}
+
+	_, err := execute(t, `{{humanSize .}}`, "big")
+	assert.Check(t, is.ErrorContains(err, `invalid size: "big"`))
+}
+
+func TestToYAML(t *testing.T) {
+	type mount struct {
+		Source      string `json:"source"`
+		Destination string `json:"destination,omitempty"`
+		ReadOnly    bool
+	}
+	out, err := execute(t, `{{toYaml .}}`, map[string]any{
+		"Name":   "web",
+		"Mounts": []mount{{Source: "/data", ReadOnly: true}},
+		"Size":   int64(1500000000),
+		"Ratio":  0.5,
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(out, `Mounts:
+    - ReadOnly: true
+      source: /data
+Name: web
+Ratio: 0.5
+Size: 1500000000`))
+
+	out, err = execute(t, `{{toYaml .}}`, "value")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(out, "value"))
+}
+
+func TestSortBy(t *testing.T) {
+	type port struct {
+		Name   string
+		Number int
+		Labels map[string]string
+	}
+	ports := []*port{
+		{Name: "https", Number: 443, Labels: map[string]string{"order": "b"}},
+		{Name: "http", Number: 80, Labels: map[string]string{"order": "c"}},
+		{Name: "ssh", Number: 22},
+	}
+	tests := []struct {
+		doc      string
+		format   string
+		data     any
+		expected string
+	}{
+		{
+			doc:      "string field",
+			format:   `{{range sortBy "Name" .}}{{.Name}} {{end}}`,
+			data:     ports,
+			expected: "http https ssh ",
+		},
+		{
+			doc:      "numeric field",
+			format:   `{{range . | sortBy "Number"}}{{.Number}} {{end}}`,
+			data:     ports,
+			expected: "22 80 443 ",
+		},
+		{
+			doc:      "nested map key",
+			format:   `{{range sortBy "Labels.order" .}}{{.Name}} {{end}}`,
+			data:     ports,
+			expected: "ssh https http ",
+		},
+		{
+			doc:      "maps",
+			format:   `{{range sortBy "Name" .}}{{.Name}} {{end}}`,
+			data:     []map[string]string{{"Name": "b"}, {"Name": "a"}},
+			expected: "a b ",
+		},
+		{
+			doc:      "elements",
+			format:   `{{range sortBy "" .}}{{.}} {{end}}`,
+			data:     []int{10, 2, 33},
+			expected: "2 10 33 ",
+		},
+		{
+			doc:      "times",
+			format:   `{{range sortBy "" .}}{{.Year}} {{end}}`,
+			data:     []time.Time{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+			expected: "2020 2024 ",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			out, err := execute(t, tc.format, tc.data)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(out, tc.expected))
+		})
+	}
+
+	// the original slice must not be modified.
+	assert.Check(t, is.Equal(ports[0].Name, "https"))
+
+	_, err := execute(t, `{{sortBy "Missing" .}}`, ports)
+	assert.Check(t, is.ErrorContains(err, `sortBy: templates.port has no field "Missing"`))
+	_, err = execute(t, `{{sortBy "Name" .}}`, "not a slice")
+	assert.Check(t, is.ErrorContains(err, "sortBy: expected slice, got string"))
+}
+
+func TestDict(t *testing.T) {
+	out, err := execute(t, `{{with dict "Name" .Name "Count" 2}}{{.Name}}: {{.Count}}{{end}}`, map[string]string{"Name": "web"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(out, "web: 2"))
+
+	_, err = execute(t, `{{dict "Name"}}`, nil)
+	assert.Check(t, is.ErrorContains(err, "dict: expected an even number of arguments, got 1"))
+	_, err = execute(t, `{{dict 1 2}}`, nil)
+	assert.Check(t, is.ErrorContains(err, "dict: expected string key, got int"))
+}
+
+func TestRegexMatch(t *testing.T) {
+	out, err := execute(t, `{{if regexMatch "^web-[0-9]+$" .}}match{{else}}no match{{end}}`, "web-1")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(out, "match"))
+
+	out, err = execute(t, `{{if . | regexMatch "^web-[0-9]+$"}}match{{else}}no match{{end}}`, "db-1")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(out, "no match"))
+
+	_, err = execute(t, `{{regexMatch "[" .}}`, "web")
+	assert.Check(t, is.ErrorContains(err, "missing closing ]"))
+}
+
+func TestEnv(t *testing.T) {
+	t.Setenv("TEMPLATE_TEST_VAR", "hello")
+	out, err := execute(t, `{{env "TEMPLATE_TEST_VAR"}}|{{env "TEMPLATE_TEST_UNSET"}}`, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(out, "hello|"))
+}
+
+func TestHasKey(t *testing.T) {
+	type labels map[string]string
+	tests := []struct {
+		doc      string
+		data     any
+		expected string
+	}{
+		{doc: "has key", data: map[string]string{"com.example": ""}, expected: "true"},
+		{doc: "missing key", data: map[string]int{"other": 1}, expected: "false"},
+		{doc: "named map type", data: labels{"com.example": "value"}, expected: "true"},
+		{doc: "nil map", data: map[string]string(nil), expected: "false"},
+		{doc: "not a map", data: "com.example", expected: "false"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			out, err := execute(t, `{{hasKey . "com.example"}}`, tc.data)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(out, tc.expected))
+		})
+	}
+}
@@ -7,6 +7,7 @@
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"os"
 	"reflect"
 	"sort"
 	"strings"
@@ -24,6 +25,17 @@
 	"upper":    strings.ToUpper,
 	"pad":      padWithSpace,
 	"truncate": truncateWithLength,
+
+	"default":    defaultValue,
+	"since":      since,
+	"humanTime":  humanTime,
+	"humanSize":  humanSize,
+	"toYaml":     toYAML,
+	"sortBy":     sortBy,
+	"dict":       dict,
+	"regexMatch": regexMatch,
+	"env":        os.Getenv,
+	"hasKey":     hasKey,
 }
 
 // HeaderFunctions are used to created headers of a table.
@@ -58,6 +70,16 @@
 	"truncate": func(v string, _ int) string {
 		return v
 	},
+	"default": func(_ any, v ...any) any {
+		if len(v) == 0 {
+			return ""
+		}
+		return v[0]
+	},
+	"since":     headerValue,
+	"humanTime": headerValue,
+	"humanSize": headerValue,
+	"toYaml":    headerValue,
 }
 
 // Parse creates a new anonymous template with the basic functions
@@ -124,6 +124,26 @@ func TestHeaderFunctions(t *testing.T) {
 			doc:      "truncate",
 			template: `{{ truncate . 2}}`,
 		},
+		{
+			doc:      "default",
+			template: `{{ default "none" .}}`,
+		},
+		{
+			doc:      "since",
+			template: `{{ since .}}`,
+		},
+		{
+			doc:      "humanTime",
+			template: `{{ humanTime .}}`,
+		},
+		{
+			doc:      "humanSize",
+			template: `{{ humanSize .}}`,
+		},
+		{
+			doc:      "toYaml",
+			template: `{{ toYaml .}}`,
+		},
 	}
 
 	for _, tc := range tests {